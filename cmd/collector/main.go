@@ -2,23 +2,125 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/stkisengese/B2B-Data-Platform/internal/api"
 	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
 	"github.com/stkisengese/B2B-Data-Platform/internal/config"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database"
+	"github.com/stkisengese/B2B-Data-Platform/internal/email"
+	"github.com/stkisengese/B2B-Data-Platform/internal/geocode"
+	"github.com/stkisengese/B2B-Data-Platform/internal/importer"
+	"github.com/stkisengese/B2B-Data-Platform/internal/quality"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+	"github.com/stkisengese/B2B-Data-Platform/internal/resolution"
 )
 
 func main() {
+	details := flag.String("details", "", "comma-separated Companies House company numbers whose officers, PSCs and filings to import")
+	linkLEI := flag.Int("link-lei", 0, "look up this many stored companies in GLEIF and attach their LEIs")
+	enrichWikidata := flag.Int("enrich-wikidata", 0, "fill missing details of this many stored companies from Wikidata")
+	vatID := flag.String("vat", "", "EU VAT ID to validate with VIES")
+	company := flag.String("company", "", "external ID of the company to link a valid -vat ID to")
+	resolve := flag.Bool("resolve", false, "cluster stored companies that describe the same entity")
+	acceptMatch := flag.Int64("accept-match", 0, "accept the queued match review with this ID")
+	rejectMatch := flag.Int64("reject-match", 0, "reject the queued match review with this ID")
+	indexDomains := flag.Bool("index-domains", false, "canonicalize stored websites and index their registrable domains")
+	normalizeStatuses := flag.Bool("normalize-statuses", false, "map the reported status of stored companies to a normalized status again")
+	scoreQuality := flag.Bool("score-quality", false, "score the data quality of stored companies and report it by source and country")
+	loadPostcodes := flag.String("load-postcodes", "", "load a postcode centroid dataset (file or zip) into postcode_centroids")
+	postcodeFormat := flag.String("postcode-format", geocode.FormatGeoNames, "format of the -load-postcodes dataset: geonames or onspd")
+	geocodeAddresses := flag.Int("geocode", 0, "geocode this many stored addresses without coordinates")
+	processRaw := flag.Bool("process-raw", false, "map and save stored raw records that have not been processed")
+	replay := flag.String("replay", "", "map and save the latest stored raw record of every record again, of one source or \"all\"")
+	verifyEmails := flag.String("verify-email", "", "comma-separated email addresses to verify")
+	verifyContacts := flag.Int("verify-contacts", 0, "verify the emails of this many unverified contacts")
+	optOut := flag.String("opt-out", "", "email address to opt out of contact, or domain to suppress")
+	reason := flag.String("reason", "requested", "reason recorded for -opt-out")
+	flag.Parse()
+
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	if *details != "" {
+		importCompanyDetails(cfg, strings.Split(*details, ","))
+		return
+	}
+
+	if *vatID != "" {
+		validateVAT(cfg, *vatID, *company)
+		return
+	}
+
+	if *linkLEI > 0 {
+		linkCompaniesToLEIs(cfg, *linkLEI)
+		return
+	}
+
+	if *enrichWikidata > 0 {
+		enrichFromWikidata(cfg, *enrichWikidata)
+		return
+	}
+
+	if *resolve || *acceptMatch > 0 || *rejectMatch > 0 {
+		resolveEntities(cfg, *acceptMatch, *rejectMatch, *resolve)
+		return
+	}
+
+	if *indexDomains {
+		indexCompanyDomains(cfg)
+		return
+	}
+
+	if *normalizeStatuses {
+		normalizeCompanyStatuses(cfg)
+		return
+	}
+
+	if *scoreQuality {
+		scoreDataQuality(cfg)
+		return
+	}
+
+	if *processRaw || *replay != "" {
+		processRawRecords(cfg, *replay)
+		return
+	}
+
+	if *verifyEmails != "" {
+		verifyEmailAddresses(cfg, strings.Split(*verifyEmails, ","))
+		return
+	}
+
+	if *verifyContacts > 0 {
+		verifyContactEmails(cfg, *verifyContacts)
+		return
+	}
+
+	if *optOut != "" {
+		suppressContact(cfg, *optOut, *reason)
+		return
+	}
+
+	if *loadPostcodes != "" {
+		loadPostcodeCentroids(cfg, *loadPostcodes, *postcodeFormat)
+		return
+	}
+
+	if *geocodeAddresses > 0 {
+		geocodeStoredAddresses(cfg, *geocodeAddresses)
+		return
+	}
+
 	// Initialize source manager
 	sourceManager := api.NewSourceManager()
 
@@ -37,6 +139,45 @@ func main() {
 		}
 	}
 
+	if cfg.DataSources.GLEIF.Enabled {
+		if err := sourceManager.RegisterSource(sources.NewGLEIFSource()); err != nil {
+			log.Printf("Failed to register GLEIF source: %v", err)
+		}
+	}
+
+	if cfg.DataSources.EDGAR.Enabled {
+		edgarSource := sources.NewEDGARSource(cfg.DataSources.EDGAR.UserAgent)
+		if err := sourceManager.RegisterSource(edgarSource); err != nil {
+			log.Printf("Failed to register SEC EDGAR source: %v", err)
+		}
+	}
+
+	if cfg.DataSources.VIES.Enabled {
+		if err := sourceManager.RegisterSource(sources.NewVIESSource()); err != nil {
+			log.Printf("Failed to register VIES source: %v", err)
+		}
+	}
+
+	if cfg.DataSources.Wikidata.Enabled {
+		wikidataSource := sources.NewWikidataSource(cfg.DataSources.Wikidata.UserAgent)
+		if err := sourceManager.RegisterSource(wikidataSource); err != nil {
+			log.Printf("Failed to register Wikidata source: %v", err)
+		}
+	}
+
+	genericConfigs, err := sources.LoadGenericRESTConfigs(cfg.DataSources.GenericPath)
+	if err != nil {
+		log.Printf("Failed to load generic REST sources: %v", err)
+	}
+	for _, genericConfig := range genericConfigs {
+		if !genericConfig.Enabled {
+			continue
+		}
+		if err := sourceManager.RegisterSource(sources.NewGenericRESTSource(genericConfig)); err != nil {
+			log.Printf("Failed to register %s source: %v", genericConfig.Name, err)
+		}
+	}
+
 	// Example data collection
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -50,13 +191,35 @@ func main() {
 	log.Println("Starting data collection from all sources...")
 	results, errors := sourceManager.CollectFromAllSources(ctx, params)
 
+	for source, err := range errors {
+		log.Printf("Error collecting from %s: %v", source, err)
+	}
+
+	// Collected records are landed in raw_records before they are mapped, so
+	// they can be processed again without collecting them again
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// The collection timeout does not cover storage
+	storeCtx := context.Background()
+	processor := importer.NewRawProcessor(db)
 	for source, records := range results {
-		log.Printf("Collected %d records from %s", len(records), source)
+		stats, err := processor.Store(storeCtx, records)
+		if err != nil {
+			log.Printf("Error storing records from %s: %v", source, err)
+			continue
+		}
+		log.Printf("Collected %d records from %s, %d new", len(records), source, stats.Stored)
 	}
 
-	for source, err := range errors {
-		log.Printf("Error collecting from %s: %v", source, err)
+	processed, err := processor.ProcessPending(storeCtx)
+	if err != nil {
+		log.Printf("Error processing raw records: %v", err)
 	}
+	log.Printf("Processed %d raw records: %d saved, %d skipped", processed.Processed, processed.Saved, processed.Skipped)
 
 	// Set up graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -65,3 +228,396 @@ func main() {
 
 	log.Println("Collector service shutting down...")
 }
+
+// importCompanyDetails stores the officers, PSCs and filing history of the
+// given Companies House companies
+func importCompanyDetails(cfg config.Config, companyNumbers []string) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	chSource := sources.NewCompaniesHouseSource(cfg.DataSources.CompaniesHouse.APIKey)
+	detailsImporter := importer.NewCompaniesHouseDetailsImporter(db, chSource)
+
+	for _, number := range companyNumbers {
+		number = strings.TrimSpace(number)
+		if number == "" {
+			continue
+		}
+
+		stats, err := detailsImporter.Import(ctx, number)
+		if err != nil {
+			log.Printf("Error importing details of %s: %v", number, err)
+			continue
+		}
+		log.Printf("Imported %d officers, %d PSCs and %d filings for %s", stats.Officers, stats.PSCs, stats.Filings, number)
+	}
+}
+
+// linkCompaniesToLEIs attaches LEIs from GLEIF to up to limit stored
+// companies that have a registration number but no LEI yet
+func linkCompaniesToLEIs(cfg config.Config, limit int) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stats, err := importer.NewGLEIFLinker(db, sources.NewGLEIFSource()).Run(ctx, limit)
+	if err != nil {
+		log.Printf("Error linking LEIs: %v", err)
+	}
+	log.Printf("Looked up %d registration numbers, linked %d companies to LEIs", stats.Checked, stats.Linked)
+}
+
+// validateVAT checks a VAT ID with VIES and, when externalID is set and the
+// VAT ID is valid, links it to that company
+func validateVAT(cfg config.Config, vatID, externalID string) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	validator := importer.NewVATValidator(db, sources.NewVIESSource())
+	if cfg.DataSources.VIES.CacheTTL > 0 {
+		validator.CacheTTL = cfg.DataSources.VIES.CacheTTL
+	}
+
+	var validation repository.VATValidation
+	if externalID != "" {
+		validation, err = validator.Link(ctx, externalID, vatID)
+	} else {
+		validation, err = validator.Validate(ctx, vatID)
+	}
+	if err != nil {
+		log.Fatalf("Error validating VAT ID %s: %v", vatID, err)
+	}
+
+	if !validation.Valid {
+		log.Printf("VAT ID %s is not valid", validation.VATID)
+		return
+	}
+	log.Printf("VAT ID %s is valid: %s, %s", validation.VATID, validation.Name.String, validation.Address.String)
+}
+
+// enrichFromWikidata fills the missing website, industry, employee count and
+// founded year of up to limit stored companies from Wikidata
+func enrichFromWikidata(cfg config.Config, limit int) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	source := sources.NewWikidataSource(cfg.DataSources.Wikidata.UserAgent)
+	stats, err := importer.NewWikidataEnricher(db, source).Run(ctx, limit)
+	if err != nil {
+		log.Printf("Error enriching from Wikidata: %v", err)
+	}
+	log.Printf("Looked up %d companies, matched %d, updated %d", stats.Checked, stats.Matched, stats.Updated)
+}
+
+// resolveEntities records decisions on queued match reviews and, when run is
+// set, clusters the stored companies and lists the matches awaiting review
+func resolveEntities(cfg config.Config, accept, reject int64, run bool) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	resolver := resolution.NewResolver(db)
+	resolver.MatchThreshold = cfg.Resolution.MatchThreshold
+	resolver.ReviewThreshold = cfg.Resolution.ReviewThreshold
+	resolver.Rules = survivorshipRules(cfg.Resolution.Survivorship)
+
+	if accept > 0 {
+		if err := resolver.Review(ctx, accept, true); err != nil {
+			log.Fatalf("Error accepting match review: %v", err)
+		}
+		log.Printf("Accepted match review %d", accept)
+	}
+	if reject > 0 {
+		if err := resolver.Review(ctx, reject, false); err != nil {
+			log.Fatalf("Error rejecting match review: %v", err)
+		}
+		log.Printf("Rejected match review %d", reject)
+	}
+	if !run {
+		return
+	}
+
+	stats, err := resolver.Run(ctx)
+	if err != nil {
+		log.Fatalf("Error resolving entities: %v", err)
+	}
+	log.Printf("Resolved %d companies into %d clusters (%d identifier links, %d fuzzy matches, %d queued for review)",
+		stats.Companies, stats.Clusters, stats.IdentifierLinks, stats.FuzzyMatches, stats.Queued)
+
+	reviews, err := repository.NewMatchReviewRepository(db).List(ctx, repository.ReviewPending, 20)
+	if err != nil {
+		log.Fatalf("Error listing match reviews: %v", err)
+	}
+	for _, review := range reviews {
+		log.Printf("Review %d: companies %d and %d score %.2f (name %.2f, address %.2f)",
+			review.ID, review.CompanyID, review.CandidateID, review.Score, review.NameScore, review.AddressScore)
+	}
+}
+
+// indexCompanyDomains canonicalizes the websites of stored companies and
+// indexes their registrable domains
+func indexCompanyDomains(cfg config.Config) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stats, err := importer.NewDomainIndexer(db).Run(ctx)
+	if err != nil {
+		log.Printf("Error indexing domains: %v", err)
+	}
+	log.Printf("Checked %d websites, indexed %d, skipped %d invalid", stats.Checked, stats.Indexed, stats.Invalid)
+}
+
+// normalizeCompanyStatuses maps the reported status of every stored
+// company to a normalized status again
+func normalizeCompanyStatuses(cfg config.Config) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stats, err := importer.NewStatusNormalizer(db).Run(ctx)
+	if err != nil {
+		log.Printf("Error normalizing statuses: %v", err)
+	}
+	log.Printf("Checked %d statuses, updated %d, %d unmapped", stats.Checked, stats.Updated, stats.Unmapped)
+}
+
+// scoreDataQuality scores every stored company and logs the average scores
+// by data source and by country
+func scoreDataQuality(cfg config.Config) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stats, err := quality.NewScorer(db).Run(ctx)
+	if err != nil {
+		log.Fatalf("Error scoring data quality: %v", err)
+	}
+	log.Printf("Scored %d companies, average score %.1f", stats.Scored, stats.Average)
+
+	repo := repository.NewQualityRepository(db)
+	for _, groupBy := range []string{repository.ReportBySource, repository.ReportByCountry} {
+		reports, err := repo.Report(ctx, groupBy)
+		if err != nil {
+			log.Fatalf("Error building quality report: %v", err)
+		}
+		for _, report := range reports {
+			log.Printf("%s %q: %d companies, score %.1f (completeness %.2f, validity %.2f, freshness %.2f, agreement %.2f)",
+				groupBy, report.Group, report.Companies, report.Score,
+				report.Completeness, report.Validity, report.Freshness, report.Agreement)
+		}
+	}
+}
+
+// survivorshipRules applies the configured survivorship rules over the
+// defaults
+func survivorshipRules(cfg config.SurvivorshipConfig) resolution.Rules {
+	rules := resolution.DefaultRules()
+	if cfg.Default.Strategy != "" || len(cfg.Default.Sources) > 0 {
+		rules.Default = resolution.Rule{Strategy: cfg.Default.Strategy, Sources: cfg.Default.Sources}
+	}
+	for field, rule := range cfg.Fields {
+		rules.Fields[field] = resolution.Rule{Strategy: rule.Strategy, Sources: rule.Sources}
+	}
+	return rules
+}
+
+// loadPostcodeCentroids loads a GeoNames or ONSPD postcode dataset used to
+// geocode addresses offline
+func loadPostcodeCentroids(cfg config.Config, path, format string) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stats, err := geocode.LoadFile(ctx, db, path, format)
+	if err != nil {
+		log.Printf("Error loading postcodes from %s: %v", path, err)
+	}
+	log.Printf("Loaded %d postcode centroids, skipped %d rows", stats.Loaded, stats.Skipped)
+}
+
+// geocodeStoredAddresses geocodes up to limit stored addresses from postcode
+// centroids, falling back to Nominatim when a server is configured
+func geocodeStoredAddresses(cfg config.Config, limit int) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	provider := geocode.Chain{geocode.NewPostcodeProvider(db)}
+	if cfg.Geocoding.NominatimURL != "" {
+		provider = append(provider, geocode.NewNominatimProvider(cfg.Geocoding.NominatimURL, cfg.Geocoding.UserAgent))
+	}
+
+	stats, err := geocode.NewGeocoder(db, provider).Run(ctx, limit)
+	if err != nil {
+		log.Printf("Error geocoding addresses: %v", err)
+	}
+	log.Printf("Checked %d addresses, geocoded %d, %d not found", stats.Checked, stats.Geocoded, stats.Unmatched)
+}
+
+// verifyEmailAddresses verifies email addresses and logs their scores
+func verifyEmailAddresses(cfg config.Config, addresses []string) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	results, err := newEmailVerifier(cfg, db).VerifyAll(ctx, addresses)
+	if err != nil {
+		log.Printf("Error verifying email addresses: %v", err)
+	}
+	for _, result := range results {
+		if result.Email == "" {
+			continue
+		}
+		log.Printf("%s: valid=%t deliverable=%t score=%.0f mailbox=%s disposable=%t role=%t",
+			result.Email, result.IsValid, result.IsDeliverable, result.Score,
+			result.Checks.Mailbox, result.Checks.IsDisposable, result.Checks.IsRoleAccount)
+	}
+}
+
+// verifyContactEmails verifies the emails of up to limit unverified contacts
+func verifyContactEmails(cfg config.Config, limit int) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stats, err := newEmailVerifier(cfg, db).VerifyContacts(ctx, limit)
+	if err != nil {
+		log.Printf("Error verifying contacts: %v", err)
+	}
+	log.Printf("Checked %d contact emails: %d verified, %d risky, %d invalid", stats.Checked, stats.Verified, stats.Risky, stats.Invalid)
+}
+
+// newEmailVerifier creates an email verifier with the configured settings
+func newEmailVerifier(cfg config.Config, db *sqlx.DB) *email.Verifier {
+	verifier := email.NewVerifier(db)
+	verifier.SMTPCheck = cfg.Email.SMTPCheck
+	if cfg.Email.HeloName != "" {
+		verifier.HeloName = cfg.Email.HeloName
+	}
+	if cfg.Email.MailFrom != "" {
+		verifier.MailFrom = cfg.Email.MailFrom
+	}
+	if cfg.Email.CacheTTL > 0 {
+		verifier.CacheTTL = cfg.Email.CacheTTL
+	}
+	return verifier
+}
+
+// suppressContact opts an email address out of contact, or suppresses a
+// whole domain
+func suppressContact(cfg config.Config, value, reason string) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	contacts := repository.NewContactRepository(db)
+	if !strings.Contains(value, "@") {
+		if err := contacts.Suppress(ctx, value, reason); err != nil {
+			log.Fatalf("Error suppressing %s: %v", value, err)
+		}
+		log.Printf("Suppressed domain %s", value)
+		return
+	}
+
+	marked, err := contacts.OptOut(ctx, value, reason)
+	if err != nil {
+		log.Fatalf("Error opting out %s: %v", value, err)
+	}
+	log.Printf("Opted out %s from %d contacts", value, marked)
+}
+
+// processRawRecords maps and saves pending raw records, or replays the
+// stored records of a source ("all" for every source)
+func processRawRecords(cfg config.Config, source string) {
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	processor := importer.NewRawProcessor(db)
+
+	var stats importer.ProcessStats
+	switch source {
+	case "":
+		stats, err = processor.ProcessPending(ctx)
+	case "all":
+		stats, err = processor.Replay(ctx, "")
+	default:
+		stats, err = processor.Replay(ctx, source)
+	}
+	if err != nil {
+		log.Printf("Error processing raw records: %v", err)
+	}
+	log.Printf("Processed %d raw records: %d saved, %d skipped", stats.Processed, stats.Saved, stats.Skipped)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/config"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database"
+	"github.com/stkisengese/B2B-Data-Platform/internal/importer"
+)
+
+func main() {
+	batchSize := flag.Int("batch", importer.DefaultBatchSize, "rows committed per transaction")
+	flag.Usage = func() {
+		log.Printf("Usage: %s [-batch N] BasicCompanyData-*.zip...", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Stop between batches on interrupt; the next run resumes from the checkpoint
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	imp := importer.NewCompaniesHouseImporter(db, sources.NewCompaniesHouseBulkSource(flag.Args()...))
+	imp.BatchSize = *batchSize
+
+	stats, err := imp.Run(ctx)
+	log.Printf("Processed %d parts (%d already complete), imported %d companies", stats.Parts, stats.Skipped, stats.Imported)
+	for _, unmapped := range importer.UnmappedStatuses() {
+		log.Printf("Unmapped status %q from %s seen %d times", unmapped.Value, unmapped.Source, unmapped.Count)
+	}
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+}
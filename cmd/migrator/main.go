@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database"
+	"github.com/stkisengese/B2B-Data-Platform/internal/importer"
 )
 
+// statusRawVersion is the migration that adds status_raw. Its SQL backfill
+// only knows part of the status mapping tables, so statuses are normalized
+// again in Go after it has been applied.
+const statusRawVersion = 10
+
 func main() {
 	m, err := migrate.New(
 		"file://migrations",
@@ -17,9 +26,49 @@ func main() {
 		log.Fatalf("failed to create migrate instance: %v", err)
 	}
 
+	before, _, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		log.Fatalf("failed to read migration version: %v", err)
+	}
+
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
 		log.Fatalf("failed to apply migrations: %v", err)
 	}
 
 	log.Println("Migrations applied successfully")
+
+	db, err := database.NewDatabaseConnection("b2b.db")
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if before < statusRawVersion {
+		stats, err := importer.NewStatusNormalizer(db).Run(context.Background())
+		if err != nil {
+			log.Fatalf("failed to normalize company statuses: %v", err)
+		}
+		log.Printf("Normalized %d company statuses", stats.Updated)
+	}
+
+	// Full-text search needs SQLite with FTS5; its migrations are tracked
+	// separately so that they can be applied once the binary has it
+	if !database.HasFTS5(db) {
+		log.Println("SQLite was built without FTS5, skipping search migrations (build with -tags sqlite_fts5)")
+		return
+	}
+
+	m, err = migrate.New(
+		"file://migrations/fts5",
+		"sqlite3://b2b.db?x-migrations-table=schema_migrations_fts5",
+	)
+	if err != nil {
+		log.Fatalf("failed to create migrate instance: %v", err)
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		log.Fatalf("failed to apply search migrations: %v", err)
+	}
+
+	log.Println("Search migrations applied successfully")
 }
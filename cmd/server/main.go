@@ -1,12 +1,20 @@
 package main
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stkisengese/B2B-Data-Platform/internal/config"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+	"github.com/stkisengese/B2B-Data-Platform/internal/search"
+	"github.com/stkisengese/B2B-Data-Platform/internal/website"
 )
 
 func main() {
@@ -15,6 +23,12 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -31,5 +45,283 @@ func main() {
 		})
 	})
 
+	// Average quality scores by data source (default) or by country, as
+	// computed by the collector's -score-quality run
+	r.GET("/api/v1/admin/data-quality", func(c *gin.Context) {
+		by := c.DefaultQuery("by", repository.ReportBySource)
+		if by != repository.ReportBySource && by != repository.ReportByCountry {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "by must be source or country"})
+			return
+		}
+
+		reports, err := repository.NewQualityRepository(db).Report(c.Request.Context(), by)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"reports": reports})
+	})
+
+	// Companies whose website is on the registrable domain of a URL, host or
+	// email address, e.g. /api/v1/domains/www.example.co.uk/companies
+	r.GET("/api/v1/domains/:domain/companies", func(c *gin.Context) {
+		domain, err := website.LookupDomain(c.Param("domain"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		companies, err := repository.NewCompanyRepository(db).ListByDomain(c.Request.Context(), domain)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		results := make([]gin.H, len(companies))
+		for i, company := range companies {
+			results[i] = companyJSON(company)
+		}
+		c.JSON(http.StatusOK, gin.H{"domain": domain, "companies": results})
+	})
+
+	// Companies filtered by name, country, status, industry, category,
+	// domain, data source and employee count, e.g.
+	// /api/v1/companies?country=GB&category=J62&sort=-employee_count&limit=20
+	r.GET("/api/v1/companies", func(c *gin.Context) {
+		query := repository.CompanyQuery{
+			Search:     c.Query("q"),
+			Country:    c.Query("country"),
+			Status:     c.Query("status"),
+			Industry:   c.Query("industry"),
+			Category:   c.Query("category"),
+			Domain:     c.Query("domain"),
+			DataSource: c.Query("data_source"),
+			Sort:       c.Query("sort"),
+		}
+		for param, field := range map[string]*int{
+			"min_employees": &query.MinEmployees,
+			"max_employees": &query.MaxEmployees,
+			"limit":         &query.Limit,
+			"offset":        &query.Offset,
+		} {
+			if value := c.Query(param); value != "" {
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a number"})
+					return
+				}
+				*field = n
+			}
+		}
+
+		companies, total, err := repository.NewCompanyRepository(db).Find(c.Request.Context(), query)
+		if errors.Is(err, repository.ErrInvalidQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		results := make([]gin.H, len(companies))
+		for i, company := range companies {
+			results[i] = companyJSON(company)
+		}
+		c.JSON(http.StatusOK, gin.H{"total": total, "companies": results})
+	})
+
+	// Ranked full-text search over company names, descriptions and
+	// addresses, e.g. /api/v1/search?q="acme widgets" leeds or
+	// /api/v1/search?q=acm&prefix=true for search-as-you-type
+	r.GET("/api/v1/search", func(c *gin.Context) {
+		query := search.Query{Text: c.Query("q"), Prefix: c.Query("prefix") == "true"}
+		for param, field := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+			if value := c.Query(param); value != "" {
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a number"})
+					return
+				}
+				*field = n
+			}
+		}
+
+		results, err := search.NewService(db).Search(c.Request.Context(), query)
+		switch {
+		case errors.Is(err, search.ErrEmptyQuery):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, search.ErrUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"query": query.Text, "results": results})
+	})
+
+	// Companies with an address within radius_km of a point, nearest first,
+	// e.g. /api/v1/search/geo?lat=51.5074&lon=-0.1278&radius_km=10, or
+	// within a bounding box given as bbox=min_lon,min_lat,max_lon,max_lat
+	r.GET("/api/v1/search/geo", func(c *gin.Context) {
+		query := search.GeoQuery{Sort: c.Query("sort")}
+		if bbox := c.Query("bbox"); bbox != "" {
+			var box search.BoundingBox
+			if _, err := fmt.Sscanf(bbox, "%g,%g,%g,%g", &box.MinLon, &box.MinLat, &box.MaxLon, &box.MaxLat); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "bbox must be min_lon,min_lat,max_lon,max_lat"})
+				return
+			}
+			query.Box = &box
+		}
+		for param, field := range map[string]*float64{
+			"lat":       &query.Latitude,
+			"lon":       &query.Longitude,
+			"radius_km": &query.RadiusKm,
+		} {
+			if value := c.Query(param); value != "" {
+				n, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a number"})
+					return
+				}
+				*field = n
+			}
+		}
+		for param, field := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+			if value := c.Query(param); value != "" {
+				n, err := strconv.Atoi(value)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a number"})
+					return
+				}
+				*field = n
+			}
+		}
+
+		results, total, err := search.NewGeoService(db).Search(c.Request.Context(), query)
+		if errors.Is(err, search.ErrInvalidGeoQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"total": total, "results": results})
+	})
+
+	// A company with its addresses and identifiers
+	r.GET("/api/v1/companies/:id", func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "id must be a number"})
+			return
+		}
+
+		ctx := c.Request.Context()
+		companies := repository.NewCompanyRepository(db)
+		company, err := companies.Get(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "company not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		addresses, err := companies.ListAddresses(ctx, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		identifiers, err := repository.NewIdentifierRepository(db).ListByCompany(ctx, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		result := companyJSON(company)
+		result["legal_name"] = company.LegalName.String
+		result["description"] = company.Description.String
+		result["phone"] = company.Phone.String
+		result["updated_at"] = company.UpdatedAt
+
+		addressResults := make([]gin.H, len(addresses))
+		for i, addr := range addresses {
+			addressResults[i] = gin.H{
+				"address_line1": addr.AddressLine1.String,
+				"address_line2": addr.AddressLine2.String,
+				"city":          addr.City.String,
+				"state":         addr.State.String,
+				"postal_code":   addr.PostalCode.String,
+				"country":       addr.Country.String,
+				"latitude":      addr.Latitude.Float64,
+				"longitude":     addr.Longitude.Float64,
+				"is_primary":    addr.IsPrimary,
+			}
+		}
+		result["addresses"] = addressResults
+
+		identifierResults := make([]gin.H, len(identifiers))
+		for i, identifier := range identifiers {
+			identifierResults[i] = gin.H{"scheme": identifier.Scheme, "value": identifier.Value}
+		}
+		result["identifiers"] = identifierResults
+
+		c.JSON(http.StatusOK, result)
+	})
+
+	// Contacts of a company that may be exported: opted-out, suppressed and
+	// invalid contacts are left out
+	r.GET("/api/v1/companies/:id/contacts", func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "id must be a number"})
+			return
+		}
+
+		contacts, err := repository.NewContactRepository(db).ListExportable(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		results := make([]gin.H, len(contacts))
+		for i, contact := range contacts {
+			results[i] = gin.H{
+				"id":                  contact.ID,
+				"kind":                contact.Kind,
+				"name":                contact.Name.String,
+				"job_title":           contact.JobTitle.String,
+				"department":          contact.Department.String,
+				"email":               contact.Email.String,
+				"phone":               contact.Phone.String,
+				"verification_status": contact.VerificationStatus,
+				"data_source":         contact.DataSource,
+			}
+		}
+		c.JSON(http.StatusOK, gin.H{"company_id": id, "contacts": results})
+	})
+
 	r.Run(fmt.Sprintf(":%d", cfg.Server.Port))
 }
+
+// companyJSON returns the summary fields of a company as served by the
+// company listings
+func companyJSON(company repository.Company) gin.H {
+	return gin.H{
+		"id":             company.ID,
+		"external_id":    company.ExternalID.String,
+		"name":           company.Name,
+		"website":        company.Website.String,
+		"domain":         company.Domain.String,
+		"industry":       company.Industry.String,
+		"employee_count": company.EmployeeCount.Int64,
+		"founded_year":   company.FoundedYear.Int64,
+		"status":         company.Status.String,
+		"data_source":    company.DataSource,
+	}
+}
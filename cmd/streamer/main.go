@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/config"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database"
+	"github.com/stkisengese/B2B-Data-Platform/internal/importer"
+)
+
+func main() {
+	insertNew := flag.Bool("insert-new", false, "Also store companies that are not in the database yet")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.NewDatabaseConnection(cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	stream := sources.NewCompaniesHouseStream(cfg.DataSources.CompaniesHouse.StreamKey)
+	consumer := importer.NewCompaniesHouseStreamConsumer(db, stream)
+	consumer.InsertNew = *insertNew
+
+	log.Println("Consuming Companies House company stream...")
+	if err := consumer.Run(ctx); err != nil && ctx.Err() == nil {
+		log.Fatalf("Stream consumer stopped: %v", err)
+	}
+
+	log.Println("Stream consumer shutting down...")
+}
@@ -0,0 +1,99 @@
+package address
+
+import (
+	"sort"
+	"strings"
+)
+
+// countryNames maps country names and codes found at the end of addresses
+// to ISO 3166 codes
+var countryNames = map[string]string{
+	"united kingdom":           "GB",
+	"uk":                       "GB",
+	"gb":                       "GB",
+	"great britain":            "GB",
+	"england":                  "GB",
+	"scotland":                 "GB",
+	"wales":                    "GB",
+	"northern ireland":         "GB",
+	"united states":            "US",
+	"united states of america": "US",
+	"usa":                      "US",
+	"us":                       "US",
+	"austria":                  "AT",
+	"österreich":               "AT",
+	"belgium":                  "BE",
+	"belgique":                 "BE",
+	"belgië":                   "BE",
+	"switzerland":              "CH",
+	"schweiz":                  "CH",
+	"suisse":                   "CH",
+	"germany":                  "DE",
+	"deutschland":              "DE",
+	"denmark":                  "DK",
+	"danmark":                  "DK",
+	"spain":                    "ES",
+	"españa":                   "ES",
+	"finland":                  "FI",
+	"suomi":                    "FI",
+	"france":                   "FR",
+	"ireland":                  "IE",
+	"italy":                    "IT",
+	"italia":                   "IT",
+	"luxembourg":               "LU",
+	"netherlands":              "NL",
+	"the netherlands":          "NL",
+	"nederland":                "NL",
+	"norway":                   "NO",
+	"norge":                    "NO",
+	"poland":                   "PL",
+	"polska":                   "PL",
+	"portugal":                 "PT",
+	"sweden":                   "SE",
+	"sverige":                  "SE",
+}
+
+// countryCode returns the ISO code of a country name
+func countryCode(name string) (string, bool) {
+	code, ok := countryNames[strings.ToLower(strings.TrimSpace(strings.TrimSuffix(name, ".")))]
+	return code, ok
+}
+
+// CountryCode returns the ISO code of a country given by its two-letter
+// code or by one of the names in countryNames
+func CountryCode(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) == 2 && strings.Trim(strings.ToUpper(value), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") == "" {
+		if strings.EqualFold(value, "uk") {
+			return "GB", true
+		}
+		return strings.ToUpper(value), true
+	}
+	return countryCode(value)
+}
+
+// CountryNames returns the lower-cased code and names a country may be
+// written as, e.g. "gb", "uk" and "united kingdom" for "GB"
+func CountryNames(code string) []string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	names := []string{strings.ToLower(code)}
+	for name, c := range countryNames {
+		if c == code && name != names[0] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// usStates holds the USPS codes of US states, districts and territories
+var usStates = map[string]bool{
+	"AL": true, "AK": true, "AZ": true, "AR": true, "CA": true, "CO": true, "CT": true,
+	"DE": true, "DC": true, "FL": true, "GA": true, "HI": true, "ID": true, "IL": true,
+	"IN": true, "IA": true, "KS": true, "KY": true, "LA": true, "ME": true, "MD": true,
+	"MA": true, "MI": true, "MN": true, "MS": true, "MO": true, "MT": true, "NE": true,
+	"NV": true, "NH": true, "NJ": true, "NM": true, "NY": true, "NC": true, "ND": true,
+	"OH": true, "OK": true, "OR": true, "PA": true, "RI": true, "SC": true, "SD": true,
+	"TN": true, "TX": true, "UT": true, "VT": true, "VA": true, "WA": true, "WV": true,
+	"WI": true, "WY": true, "PR": true, "GU": true, "VI": true, "AS": true, "MP": true,
+}
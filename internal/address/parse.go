@@ -0,0 +1,235 @@
+// Package address splits single-line postal addresses into components
+// without calling external services.
+package address
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// MinConfidence is the confidence below which callers should keep the raw
+// line instead of the parsed components
+const MinConfidence = 0.7
+
+// Confidence contributions of the recognised components
+const (
+	postalCodeWeight = 0.4
+	cityWeight       = 0.2
+	streetWeight     = 0.2
+	countryWeight    = 0.2
+)
+
+var (
+	ukPostcode = regexp.MustCompile(`(?i)\b([A-Z]{1,2}\d[A-Z\d]?)\s*(\d[A-Z]{2})\b`)
+	usZIP      = regexp.MustCompile(`^(?:(.+?)\s+)?([A-Za-z]{2})\.?\s+(\d{5}(?:-\d{4})?)$`)
+)
+
+// euPostcodes are the postcode patterns of European countries whose
+// addresses end in "<postcode> <city>"
+var euPostcodes = map[string]string{
+	"AT": `\d{4}`,
+	"BE": `\d{4}`,
+	"CH": `\d{4}`,
+	"DE": `\d{5}`,
+	"DK": `\d{4}`,
+	"ES": `\d{5}`,
+	"FI": `\d{5}`,
+	"FR": `\d{5}`,
+	"IT": `\d{5}`,
+	"LU": `\d{4}`,
+	"NL": `\d{4}\s?[A-Z]{2}`,
+	"NO": `\d{4}`,
+	"PL": `\d{2}-\d{3}`,
+	"PT": `\d{4}-\d{3}`,
+	"SE": `\d{3}\s?\d{2}`,
+}
+
+// euPatterns holds the compiled "<postcode> <city>" pattern of each country;
+// postcodes may carry a country prefix such as "D-" or "FR-"
+var euPatterns = compileEUPatterns()
+
+func compileEUPatterns() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp, len(euPostcodes))
+	for country, postcode := range euPostcodes {
+		patterns[country] = regexp.MustCompile(`(?i)^(?:[A-Z]{1,2}-)?(` + postcode + `)\s+(\D.*)$`)
+	}
+	return patterns
+}
+
+// Parse splits a single-line address such as "1 High Street, London, EC1A
+// 1BB" into components. country is the ISO code to assume when the line
+// does not name one, and may be empty. The returned confidence ranges from
+// 0 to 1; the address always keeps the line in Raw.
+func Parse(raw, country string) (model.Address, float64) {
+	addr := model.Address{Raw: strings.TrimSpace(raw)}
+
+	parts := split(addr.Raw)
+	if len(parts) == 0 {
+		return addr, 0
+	}
+
+	// A trailing country name takes precedence over the hint
+	if code, ok := countryCode(parts[len(parts)-1]); ok {
+		addr.Country = code
+		parts = parts[:len(parts)-1]
+	} else {
+		addr.Country = strings.ToUpper(strings.TrimSpace(country))
+	}
+
+	var found bool
+	switch addr.Country {
+	case "GB":
+		parts, found = parseUK(&addr, parts)
+	case "US":
+		parts, found = parseUS(&addr, parts)
+	case "":
+		// Without a country, try the formats that identify themselves
+		if parts, found = parseUK(&addr, parts); found {
+			addr.Country = "GB"
+		} else if parts, found = parseUS(&addr, parts); found {
+			addr.Country = "US"
+		}
+	default:
+		parts, found = parseEU(&addr, parts)
+	}
+
+	confidence := 0.0
+	if found {
+		confidence += postalCodeWeight
+	}
+	if addr.Country != "" {
+		confidence += countryWeight
+	}
+
+	// What remains is street lines, followed by the city when the postcode
+	// rule did not find one
+	if addr.City == "" && len(parts) > 1 {
+		addr.City = parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+	if addr.City != "" && !hasDigit(addr.City) {
+		confidence += cityWeight
+	}
+	if len(parts) > 0 {
+		addr.Line1 = parts[0]
+		addr.Line2 = strings.Join(parts[1:], ", ")
+		confidence += streetWeight
+	}
+
+	return addr, confidence
+}
+
+// parseUK takes the postcode from the last part that contains one; the part
+// before it, or the rest of the same part, is the town
+func parseUK(addr *model.Address, parts []string) ([]string, bool) {
+	for i := len(parts) - 1; i >= 0 && i >= len(parts)-2; i-- {
+		match := ukPostcode.FindStringSubmatchIndex(parts[i])
+		if match == nil {
+			continue
+		}
+
+		addr.PostalCode = strings.ToUpper(parts[i][match[2]:match[3]] + " " + parts[i][match[4]:match[5]])
+		rest := strings.TrimSpace(parts[i][:match[0]] + parts[i][match[1]:])
+
+		remaining := append([]string{}, parts[:i]...)
+		if rest != "" {
+			addr.City = rest
+		} else if len(remaining) > 1 {
+			addr.City = remaining[len(remaining)-1]
+			remaining = remaining[:len(remaining)-1]
+		}
+		return append(remaining, parts[i+1:]...), true
+	}
+	return parts, false
+}
+
+// parseUS recognises "City, ST 12345" and "City ST 12345" endings
+func parseUS(addr *model.Address, parts []string) ([]string, bool) {
+	// Nothing is left when the line was only a country name
+	if len(parts) == 0 {
+		return parts, false
+	}
+	last := parts[len(parts)-1]
+	match := usZIP.FindStringSubmatch(last)
+	if match == nil || !usStates[strings.ToUpper(match[2])] {
+		return parts, false
+	}
+
+	addr.Region = strings.ToUpper(match[2])
+	addr.PostalCode = match[3]
+
+	remaining := parts[:len(parts)-1]
+	if match[1] != "" {
+		addr.City = match[1]
+	} else if len(remaining) > 1 {
+		addr.City = remaining[len(remaining)-1]
+		remaining = remaining[:len(remaining)-1]
+	}
+	return remaining, true
+}
+
+// parseEU recognises "<postcode> <city>" in the last two parts
+func parseEU(addr *model.Address, parts []string) ([]string, bool) {
+	pattern, ok := euPatterns[addr.Country]
+	if !ok {
+		return parts, false
+	}
+
+	for i := len(parts) - 1; i >= 0 && i >= len(parts)-2; i-- {
+		match := pattern.FindStringSubmatch(parts[i])
+		if match == nil {
+			continue
+		}
+
+		addr.PostalCode = strings.ToUpper(match[1])
+		addr.City = strings.TrimSpace(match[2])
+		return append(append([]string{}, parts[:i]...), parts[i+1:]...), true
+	}
+	return parts, false
+}
+
+// split breaks an address on commas and line breaks
+func split(raw string) []string {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == '\n' || r == ';'
+	})
+
+	var parts []string
+	for _, field := range fields {
+		if field = strings.Join(strings.Fields(field), " "); field != "" {
+			parts = append(parts, field)
+		}
+	}
+	return parts
+}
+
+func hasDigit(s string) bool {
+	return strings.ContainsAny(s, "0123456789")
+}
+
+// postcodeFormats holds the anchored postcode pattern of each country with
+// known rules
+var postcodeFormats = compilePostcodeFormats()
+
+func compilePostcodeFormats() map[string]*regexp.Regexp {
+	formats := map[string]*regexp.Regexp{
+		"GB": regexp.MustCompile(`(?i)^[A-Z]{1,2}\d[A-Z\d]?\s*\d[A-Z]{2}$`),
+		"US": regexp.MustCompile(`^\d{5}(?:-\d{4})?$`),
+	}
+	for country, postcode := range euPostcodes {
+		formats[country] = regexp.MustCompile(`(?i)^` + postcode + `$`)
+	}
+	return formats
+}
+
+// ValidPostcode reports whether postcode has the format of the country's
+// postcodes. known is false for countries without rules.
+func ValidPostcode(country, postcode string) (valid, known bool) {
+	format, ok := postcodeFormats[strings.ToUpper(country)]
+	if !ok {
+		return false, false
+	}
+	return format.MatchString(strings.TrimSpace(postcode)), true
+}
@@ -0,0 +1,87 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw      string
+		country  string
+		expected model.Address
+	}{
+		{
+			raw:     "1 High Street, Flat 2, London, EC1A 1BB",
+			country: "gb",
+			expected: model.Address{Line1: "1 High Street", Line2: "Flat 2", City: "London",
+				PostalCode: "EC1A 1BB", Country: "GB"},
+		},
+		{
+			raw:      "10 Downing St, London sw1a2aa, United Kingdom",
+			expected: model.Address{Line1: "10 Downing St", City: "London", PostalCode: "SW1A 2AA", Country: "GB"},
+		},
+		{
+			raw:     "One Apple Park Way, Cupertino, CA 95014",
+			country: "us",
+			expected: model.Address{Line1: "One Apple Park Way", City: "Cupertino", Region: "CA",
+				PostalCode: "95014", Country: "US"},
+		},
+		{
+			raw: "1600 Amphitheatre Parkway, Mountain View CA 94043-1351, USA",
+			expected: model.Address{Line1: "1600 Amphitheatre Parkway", City: "Mountain View", Region: "CA",
+				PostalCode: "94043-1351", Country: "US"},
+		},
+		{
+			raw:      "Unter den Linden 1, 10117 Berlin, Germany",
+			expected: model.Address{Line1: "Unter den Linden 1", City: "Berlin", PostalCode: "10117", Country: "DE"},
+		},
+		{
+			raw:      "Damrak 1, 1012 LG Amsterdam",
+			country:  "NL",
+			expected: model.Address{Line1: "Damrak 1", City: "Amsterdam", PostalCode: "1012 LG", Country: "NL"},
+		},
+	}
+
+	for _, tt := range tests {
+		addr, confidence := Parse(tt.raw, tt.country)
+		tt.expected.Raw = tt.raw
+		if addr != tt.expected {
+			t.Errorf("Expected %q to parse as %+v, got %+v", tt.raw, tt.expected, addr)
+		}
+		if confidence < MinConfidence {
+			t.Errorf("Expected confident parse of %q, got %.1f", tt.raw, confidence)
+		}
+	}
+}
+
+func TestParse_Uncertain(t *testing.T) {
+	for _, raw := range []string{"", "PO Box 12", "Somewhere, Nowhere", "Main Street 5, 123 City", "USA", "United States", "United Kingdom"} {
+		if _, confidence := Parse(raw, ""); confidence >= MinConfidence {
+			t.Errorf("Expected low confidence for %q, got %.1f", raw, confidence)
+		}
+	}
+}
+
+func TestValidPostcode(t *testing.T) {
+	tests := []struct {
+		country, postcode string
+		valid, known      bool
+	}{
+		{"GB", "EC1A 1BB", true, true},
+		{"gb", "sw1a2aa", true, true},
+		{"GB", "12345", false, true},
+		{"US", "94043-1351", true, true},
+		{"DE", "10117", true, true},
+		{"NL", "1012 LG", true, true},
+		{"JP", "100-0001", false, false},
+	}
+
+	for _, tt := range tests {
+		valid, known := ValidPostcode(tt.country, tt.postcode)
+		if valid != tt.valid || known != tt.known {
+			t.Errorf("Expected %s postcode %q to be (%v, %v), got (%v, %v)", tt.country, tt.postcode, tt.valid, tt.known, valid, known)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -24,6 +25,38 @@ type APIClient struct {
 	mutex          sync.RWMutex
 }
 
+// HTTPError is returned by MakeRequest for a response with an error status
+type HTTPError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Status)
+}
+
+// IsRejected reports whether err is an HTTPError for a request the service
+// refused as invalid or answered as not found. Such answers are about the
+// request rather than the service, unlike 401, 403 and 429, which affect
+// every following request.
+func IsRejected(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	switch httpErr.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity:
+		return true
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an HTTPError with status 404
+func IsNotFound(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+}
+
 // ClientConfig holds configuration for API clients
 type ClientConfig struct {
 	APIName          string
@@ -84,8 +117,10 @@ func (c *APIClient) MakeRequest(ctx context.Context, method, endpoint string, he
 		req.Header.Set(key, value)
 	}
 
-	// Execute request with circuit breaker
+	// Execute request with circuit breaker. Rejected requests don't count
+	// towards opening the circuit, as the service did answer them.
 	var resp *http.Response
+	var rejectedErr error
 	err = c.CircuitBreaker.Execute(func() error {
 		c.mutex.Lock()
 		c.RequestCount++
@@ -109,7 +144,14 @@ func (c *APIClient) MakeRequest(ctx context.Context, method, endpoint string, he
 				"endpoint":    endpoint,
 				"status_code": resp.StatusCode,
 			}).Warn("HTTP request returned error status")
-			return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+			resp.Body.Close()
+
+			httpErr := &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+			if IsRejected(httpErr) {
+				rejectedErr = httpErr
+				return nil
+			}
+			return httpErr
 		}
 
 		c.Logger.WithFields(logrus.Fields{
@@ -124,6 +166,9 @@ func (c *APIClient) MakeRequest(ctx context.Context, method, endpoint string, he
 	if err != nil {
 		return nil, err
 	}
+	if rejectedErr != nil {
+		return nil, rejectedErr
+	}
 
 	return resp, nil
 }
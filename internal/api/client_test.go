@@ -149,3 +149,48 @@ func TestAPIClient_MakeRequest_CircuitBreaker(t *testing.T) {
 		t.Errorf("Expected circuit breaker error, got: %v", err)
 	}
 }
+
+func TestAPIClient_MakeRequest_RejectedRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/forbidden" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	config := ClientConfig{
+		APIName:          "TestAPI",
+		BaseURL:          server.URL,
+		RateLimit:        rate.Limit(100),
+		RateBurst:        100,
+		Timeout:          5 * time.Second,
+		CircuitThreshold: 3,
+	}
+
+	client := NewAPIClient(config)
+	ctx := context.Background()
+
+	// Rejected requests are reported but don't open the circuit
+	for i := 0; i < 5; i++ {
+		_, err := client.MakeRequest(ctx, "GET", "/missing", nil)
+		if !IsNotFound(err) || !IsRejected(err) {
+			t.Fatalf("Request %d: expected a 404 HTTPError, got %v", i+1, err)
+		}
+	}
+	if client.CircuitBreaker.GetState() != Closed {
+		t.Errorf("Expected the circuit to stay closed, got %v", client.CircuitBreaker.GetState())
+	}
+
+	// A forbidden client fails every request, so it opens the circuit
+	for i := 0; i < 3; i++ {
+		_, err := client.MakeRequest(ctx, "GET", "/forbidden", nil)
+		if err == nil || IsRejected(err) {
+			t.Fatalf("Request %d: expected a 403 failure, got %v", i+1, err)
+		}
+	}
+	if client.CircuitBreaker.GetState() != Open {
+		t.Errorf("Expected the circuit to open, got %v", client.CircuitBreaker.GetState())
+	}
+}
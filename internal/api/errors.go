@@ -9,4 +9,8 @@ var (
 	ErrMaxRetriesExceeded = errors.New("maximum retries exceeded")
 	ErrInvalidResponse = errors.New("invalid response from API")
 	ErrUnauthorized = errors.New("unauthorized request")
+	ErrNoInputFiles = errors.New("no input files configured")
+	ErrUserAgentMissing = errors.New("User-Agent is missing")
+	ErrQueryMissing = errors.New("search query is missing")
+	ErrServiceUnavailable = errors.New("service temporarily unavailable")
 )
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/stkisengese/B2B-Data-Platform/internal/api"
@@ -39,6 +40,25 @@ type CompaniesHouseResponse struct {
 	ItemsPerPage int `json:"items_per_page"`
 }
 
+// CompaniesHouseProfile represents the /company/{n} response
+type CompaniesHouseProfile struct {
+	CompanyNumber           string                `json:"company_number"`
+	CompanyName             string                `json:"company_name"`
+	Type                    string                `json:"type"`
+	CompanyStatus           string                `json:"company_status"`
+	CompanyStatusDetail     string                `json:"company_status_detail"`
+	DateOfCreation          string                `json:"date_of_creation"`
+	DateOfCessation         string                `json:"date_of_cessation"`
+	Jurisdiction            string                `json:"jurisdiction"`
+	SICCodes                []string              `json:"sic_codes"`
+	RegisteredOfficeAddress CompaniesHouseAddress `json:"registered_office_address"`
+	PreviousCompanyNames    []struct {
+		Name          string `json:"name"`
+		CeasedOn      string `json:"ceased_on"`
+		EffectiveFrom string `json:"effective_from"`
+	} `json:"previous_company_names"`
+}
+
 // NewCompaniesHouseSource creates a new Companies House data source
 func NewCompaniesHouseSource(apiKey string) *CompaniesHouseSource {
 	config := api.ClientConfig{
@@ -124,6 +144,71 @@ func (ch *CompaniesHouseSource) Collect(ctx context.Context, params api.Collecti
 	return records, nil
 }
 
+// CollectCompany retrieves the profile of a single company by number
+func (ch *CompaniesHouseSource) CollectCompany(ctx context.Context, companyNumber string) (api.RawRecord, error) {
+	if err := ch.Validate(); err != nil {
+		return api.RawRecord{}, err
+	}
+
+	resp, err := ch.APIClient.MakeRequest(ctx, "GET", "/company/"+url.PathEscape(companyNumber), map[string]string{
+		"Accept": "application/json",
+	})
+	if err != nil {
+		return api.RawRecord{}, fmt.Errorf("Companies House API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var profile CompaniesHouseProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return api.RawRecord{}, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return profileRecord(profile, time.Now()), nil
+}
+
+// profileRecord converts a company profile into the record layout produced
+// by Collect, adding the fields only the profile carries
+func profileRecord(profile CompaniesHouseProfile, collectedAt time.Time) api.RawRecord {
+	sicCodes := make([]interface{}, len(profile.SICCodes))
+	for i, code := range profile.SICCodes {
+		sicCodes[i] = code
+	}
+
+	previousNames := make([]interface{}, len(profile.PreviousCompanyNames))
+	for i, prev := range profile.PreviousCompanyNames {
+		previousNames[i] = map[string]interface{}{
+			"name":           prev.Name,
+			"ceased_on":      prev.CeasedOn,
+			"effective_from": prev.EffectiveFrom,
+		}
+	}
+
+	return api.RawRecord{
+		ID:          fmt.Sprintf("ch_%s", profile.CompanyNumber),
+		Source:      "companies_house",
+		CollectedAt: collectedAt,
+		Data: map[string]interface{}{
+			"name":              profile.CompanyName,
+			"company_number":    profile.CompanyNumber,
+			"company_type":      profile.Type,
+			"company_status":    profile.CompanyStatus,
+			"date_of_creation":  profile.DateOfCreation,
+			"date_of_cessation": profile.DateOfCessation,
+			"jurisdiction":      profile.Jurisdiction,
+			"sic_codes":         sicCodes,
+			"previous_names":    previousNames,
+			"address": map[string]interface{}{
+				"address_line_1": joinNonEmpty(" ", profile.RegisteredOfficeAddress.Premises, profile.RegisteredOfficeAddress.AddressLine1),
+				"address_line_2": profile.RegisteredOfficeAddress.AddressLine2,
+				"locality":       profile.RegisteredOfficeAddress.Locality,
+				"region":         profile.RegisteredOfficeAddress.Region,
+				"postal_code":    profile.RegisteredOfficeAddress.PostalCode,
+				"country":        profile.RegisteredOfficeAddress.Country,
+			},
+		},
+	}
+}
+
 // Validate checks if the data source is properly configured
 func (ch *CompaniesHouseSource) Validate() error {
 	if ch.APIClient.APIKey == "" {
@@ -132,6 +217,17 @@ func (ch *CompaniesHouseSource) Validate() error {
 	return nil
 }
 
+// joinNonEmpty joins the non-empty values with sep
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
 // min returns the smaller of two integers
 func min(a, b int) int {
 	if a < b {
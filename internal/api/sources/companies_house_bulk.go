@@ -0,0 +1,296 @@
+package sources
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"golang.org/x/time/rate"
+)
+
+// CompaniesHouseBulkSource reads the Companies House "Free Company Data
+// Product" snapshot, published monthly as one or more zipped CSV files
+type CompaniesHouseBulkSource struct {
+	api.BaseDataSource
+	Files []string
+}
+
+// BulkPart identifies one CSV entry inside one zip file of the snapshot
+type BulkPart struct {
+	File  string
+	Entry string
+}
+
+// Maximum number of previous names and SIC codes in the snapshot layout
+const (
+	bulkMaxPreviousNames = 10
+	bulkMaxSICCodes      = 4
+)
+
+var snapshotDatePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// NewCompaniesHouseBulkSource creates a source over the given snapshot zip files
+func NewCompaniesHouseBulkSource(files ...string) *CompaniesHouseBulkSource {
+	return &CompaniesHouseBulkSource{
+		BaseDataSource: api.BaseDataSource{
+			Name:      "CompaniesHouseBulk",
+			RateLimit: rate.Inf, // local files are not rate limited
+		},
+		Files: files,
+	}
+}
+
+// Parts lists the CSV entries of all configured files in a stable order
+func (s *CompaniesHouseBulkSource) Parts() ([]BulkPart, error) {
+	files := append([]string(nil), s.Files...)
+	sort.Strings(files)
+
+	var parts []BulkPart
+	for _, file := range files {
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file, err)
+		}
+
+		var entries []string
+		for _, f := range zr.File {
+			if strings.EqualFold(filepath.Ext(f.Name), ".csv") {
+				entries = append(entries, f.Name)
+			}
+		}
+		zr.Close()
+
+		sort.Strings(entries)
+		for _, entry := range entries {
+			parts = append(parts, BulkPart{File: file, Entry: entry})
+		}
+	}
+
+	return parts, nil
+}
+
+// ReadPart streams the rows of one CSV entry to fn, skipping the first skip
+// data rows. The row index passed to fn counts data rows from zero.
+func (s *CompaniesHouseBulkSource) ReadPart(ctx context.Context, part BulkPart, skip int, fn func(row int, record api.RawRecord) error) error {
+	zr, err := zip.OpenReader(part.File)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", part.File, err)
+	}
+	defer zr.Close()
+
+	var entry *zip.File
+	for _, f := range zr.File {
+		if f.Name == part.Entry {
+			entry = f
+			break
+		}
+	}
+	if entry == nil {
+		return fmt.Errorf("entry %s not found in %s", part.Entry, part.File)
+	}
+
+	rc, err := entry.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s in %s: %w", part.Entry, part.File, err)
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header of %s: %w", part.Entry, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// The published header has stray spaces, e.g. " CompanyNumber"
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	if _, ok := columns["CompanyNumber"]; !ok {
+		return fmt.Errorf("%s is not a basic company data file: CompanyNumber column missing", part.Entry)
+	}
+
+	collectedAt := snapshotDate(part.File)
+
+	for row := 0; ; row++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read row %d of %s: %w", row, part.Entry, err)
+		}
+
+		if row < skip {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(fields) {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		if err := fn(row, bulkRecord(get, collectedAt)); err != nil {
+			return err
+		}
+	}
+}
+
+// Collect reads records from the snapshot, honouring Offset and Limit and
+// keeping only names containing Query when one is given
+func (s *CompaniesHouseBulkSource) Collect(ctx context.Context, params api.CollectionParams) ([]api.RawRecord, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	parts, err := s.Parts()
+	if err != nil {
+		return nil, err
+	}
+
+	query := strings.ToLower(params.Query)
+	errLimitReached := errors.New("limit reached")
+
+	var records []api.RawRecord
+	matched := 0
+	for _, part := range parts {
+		err := s.ReadPart(ctx, part, 0, func(row int, record api.RawRecord) error {
+			name, _ := record.Data["name"].(string)
+			if query != "" && !strings.Contains(strings.ToLower(name), query) {
+				return nil
+			}
+
+			matched++
+			if matched <= params.Offset {
+				return nil
+			}
+
+			records = append(records, record)
+			if params.Limit > 0 && len(records) >= params.Limit {
+				return errLimitReached
+			}
+			return nil
+		})
+		if err == errLimitReached {
+			break
+		}
+		if err != nil {
+			return records, err
+		}
+	}
+
+	return records, nil
+}
+
+// Validate checks that snapshot files are configured and readable
+func (s *CompaniesHouseBulkSource) Validate() error {
+	if len(s.Files) == 0 {
+		return api.ErrNoInputFiles
+	}
+	for _, file := range s.Files {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("bulk data file unavailable: %w", err)
+		}
+	}
+	return nil
+}
+
+// bulkRecord converts one snapshot row into the record layout used by
+// CompaniesHouseSource so both feed the same downstream processing
+func bulkRecord(get func(string) string, collectedAt time.Time) api.RawRecord {
+	companyNumber := get("CompanyNumber")
+
+	var sicCodes, sicText []interface{}
+	for i := 1; i <= bulkMaxSICCodes; i++ {
+		text := get(fmt.Sprintf("SICCode.SicText_%d", i))
+		if text == "" || strings.EqualFold(text, "None Supplied") {
+			continue
+		}
+		code, _, _ := strings.Cut(text, " - ")
+		sicCodes = append(sicCodes, strings.TrimSpace(code))
+		sicText = append(sicText, text)
+	}
+
+	var previousNames []interface{}
+	for i := 1; i <= bulkMaxPreviousNames; i++ {
+		name := get(fmt.Sprintf("PreviousName_%d.CompanyName", i))
+		if name == "" {
+			continue
+		}
+		previousNames = append(previousNames, map[string]interface{}{
+			"name":      name,
+			"ceased_on": bulkDate(get(fmt.Sprintf("PreviousName_%d.CONDATE", i))),
+		})
+	}
+
+	return api.RawRecord{
+		ID:          fmt.Sprintf("ch_%s", companyNumber),
+		Source:      "companies_house",
+		CollectedAt: collectedAt,
+		Data: map[string]interface{}{
+			"name":              get("CompanyName"),
+			"company_number":    companyNumber,
+			"company_type":      get("CompanyCategory"),
+			"company_status":    get("CompanyStatus"),
+			"date_of_creation":  bulkDate(get("IncorporationDate")),
+			"date_of_cessation": bulkDate(get("DissolutionDate")),
+			"country_of_origin": get("CountryOfOrigin"),
+			"sic_codes":         sicCodes,
+			"sic_text":          sicText,
+			"previous_names":    previousNames,
+			"uri":               get("URI"),
+			"address": map[string]interface{}{
+				"care_of":        get("RegAddress.CareOf"),
+				"po_box":         get("RegAddress.POBox"),
+				"address_line_1": get("RegAddress.AddressLine1"),
+				"address_line_2": get("RegAddress.AddressLine2"),
+				"locality":       get("RegAddress.PostTown"),
+				"region":         get("RegAddress.County"),
+				"country":        get("RegAddress.Country"),
+				"postal_code":    get("RegAddress.PostCode"),
+			},
+		},
+	}
+}
+
+// bulkDate converts the snapshot's dd/mm/yyyy dates to the yyyy-mm-dd form
+// returned by the REST API, passing through values it cannot parse
+func bulkDate(value string) string {
+	if value == "" {
+		return ""
+	}
+	t, err := time.Parse("02/01/2006", value)
+	if err != nil {
+		return value
+	}
+	return t.Format("2006-01-02")
+}
+
+// snapshotDate extracts the publication date from a file name such as
+// BasicCompanyData-2024-01-01-part1_7.zip, falling back to the current time
+func snapshotDate(file string) time.Time {
+	if match := snapshotDatePattern.FindString(filepath.Base(file)); match != "" {
+		if t, err := time.Parse("2006-01-02", match); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
@@ -0,0 +1,146 @@
+package sources
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+const bulkHeader = `CompanyName, CompanyNumber,RegAddress.CareOf,RegAddress.POBox,RegAddress.AddressLine1, RegAddress.AddressLine2,RegAddress.PostTown,RegAddress.County,RegAddress.Country,RegAddress.PostCode,CompanyCategory,CompanyStatus,CountryOfOrigin,DissolutionDate,IncorporationDate,SICCode.SicText_1,SICCode.SicText_2,SICCode.SicText_3,SICCode.SicText_4,URI,PreviousName_1.CONDATE, PreviousName_1.CompanyName
+`
+
+func writeBulkZip(t *testing.T, dir, name string, entries map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for entry, content := range entries {
+		w, err := zw.Create(entry)
+		if err != nil {
+			t.Fatalf("Failed to create zip entry: %v", err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+
+	return path
+}
+
+func TestCompaniesHouseBulkSource_ReadPart(t *testing.T) {
+	csv := bulkHeader +
+		`"TEST COMPANY LTD","12345678","","","1 Test Street","Floor 2","LONDON","GREATER LONDON","UNITED KINGDOM","EC1A 1BB","Private Limited Company","Active","United Kingdom","","01/02/2020","62012 - Business and domestic software development","None Supplied","","","http://business.data.gov.uk/id/company/12345678","15/06/2021","OLD TEST COMPANY LTD"` + "\n"
+
+	path := writeBulkZip(t, t.TempDir(), "BasicCompanyData-2024-01-01-part1_1.zip", map[string]string{
+		"BasicCompanyData-2024-01-01-part1_1.csv": csv,
+	})
+
+	source := NewCompaniesHouseBulkSource(path)
+	parts, err := source.Parts()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(parts) != 1 {
+		t.Fatalf("Expected 1 part, got %d", len(parts))
+	}
+
+	var records []api.RawRecord
+	err = source.ReadPart(context.Background(), parts[0], 0, func(row int, record api.RawRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	record := records[0]
+	if record.ID != "ch_12345678" {
+		t.Errorf("Expected ID 'ch_12345678', got '%s'", record.ID)
+	}
+	if record.Source != "companies_house" {
+		t.Errorf("Expected source 'companies_house', got '%s'", record.Source)
+	}
+	if !record.CollectedAt.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected snapshot date 2024-01-01, got %v", record.CollectedAt)
+	}
+	if record.Data["date_of_creation"] != "2020-02-01" {
+		t.Errorf("Expected date_of_creation '2020-02-01', got '%v'", record.Data["date_of_creation"])
+	}
+
+	sicCodes := record.Data["sic_codes"].([]interface{})
+	if len(sicCodes) != 1 || sicCodes[0] != "62012" {
+		t.Errorf("Expected SIC codes [62012], got %v", sicCodes)
+	}
+
+	previous := record.Data["previous_names"].([]interface{})
+	if len(previous) != 1 {
+		t.Fatalf("Expected 1 previous name, got %d", len(previous))
+	}
+	if prev := previous[0].(map[string]interface{}); prev["name"] != "OLD TEST COMPANY LTD" || prev["ceased_on"] != "2021-06-15" {
+		t.Errorf("Unexpected previous name %v", prev)
+	}
+
+	address := record.Data["address"].(map[string]interface{})
+	if address["locality"] != "LONDON" || address["postal_code"] != "EC1A 1BB" {
+		t.Errorf("Unexpected address %v", address)
+	}
+}
+
+func TestCompaniesHouseBulkSource_Collect(t *testing.T) {
+	dir := t.TempDir()
+	row := func(name, number string) string {
+		return `"` + name + `","` + number + `","","","","","","","","","","Active","","","","","","","","","",""` + "\n"
+	}
+
+	path := writeBulkZip(t, dir, "BasicCompanyData-2024-01-01.zip", map[string]string{
+		"part1.csv": bulkHeader + row("ALPHA LTD", "00000001") + row("BETA LTD", "00000002"),
+		"part2.csv": bulkHeader + row("ALPHA TWO LTD", "00000003"),
+		"README":    "not a csv",
+	})
+
+	source := NewCompaniesHouseBulkSource(path)
+
+	records, err := source.Collect(context.Background(), api.CollectionParams{Query: "alpha", Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[1].ID != "ch_00000003" {
+		t.Errorf("Expected second record from part2, got '%s'", records[1].ID)
+	}
+
+	records, err = source.Collect(context.Background(), api.CollectionParams{Limit: 1, Offset: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 1 || records[0].ID != "ch_00000002" {
+		t.Errorf("Expected only ch_00000002, got %v", records)
+	}
+}
+
+func TestCompaniesHouseBulkSource_Validate(t *testing.T) {
+	if err := NewCompaniesHouseBulkSource().Validate(); err != api.ErrNoInputFiles {
+		t.Errorf("Expected ErrNoInputFiles, got %v", err)
+	}
+
+	if err := NewCompaniesHouseBulkSource(filepath.Join(t.TempDir(), "missing.zip")).Validate(); err == nil {
+		t.Error("Expected error for missing file, got nil")
+	}
+}
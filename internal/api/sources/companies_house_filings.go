@@ -0,0 +1,76 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+// CompaniesHouseFilingHistoryResponse represents the /company/{n}/filing-history response
+type CompaniesHouseFilingHistoryResponse struct {
+	Items []struct {
+		TransactionID string `json:"transaction_id"`
+		Category      string `json:"category"`
+		Subcategory   string `json:"subcategory"`
+		Type          string `json:"type"`
+		Description   string `json:"description"`
+		Date          string `json:"date"`
+		ActionDate    string `json:"action_date"`
+		Pages         int    `json:"pages"`
+		Barcode       string `json:"barcode"`
+		Links         struct {
+			Self             string `json:"self"`
+			DocumentMetadata string `json:"document_metadata"`
+		} `json:"links"`
+	} `json:"items"`
+	TotalCount   int `json:"total_count"`
+	StartIndex   int `json:"start_index"`
+	ItemsPerPage int `json:"items_per_page"`
+}
+
+// CollectFilingHistory retrieves the filing history of a company, newest
+// first, following pagination until limit filings have been read (limit 0 reads all)
+func (ch *CompaniesHouseSource) CollectFilingHistory(ctx context.Context, companyNumber string, limit int) ([]api.RawRecord, error) {
+	var records []api.RawRecord
+
+	err := ch.collectPages(ctx, fmt.Sprintf("/company/%s/filing-history", url.PathEscape(companyNumber)), limit, func(body []byte) (int, int, error) {
+		var apiResp CompaniesHouseFilingHistoryResponse
+		if err := json.Unmarshal(body, &apiResp); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse JSON response: %w", err)
+		}
+
+		for _, item := range apiResp.Items {
+			id := item.TransactionID
+			if id == "" {
+				id = stableHash(item.Type, item.Date, item.Barcode)
+			}
+
+			records = append(records, api.RawRecord{
+				ID:          fmt.Sprintf("ch_filing_%s_%s", companyNumber, id),
+				Source:      "companies_house_filing_history",
+				CollectedAt: time.Now(),
+				Data: map[string]interface{}{
+					"company_number":    companyNumber,
+					"transaction_id":    item.TransactionID,
+					"category":          item.Category,
+					"subcategory":       item.Subcategory,
+					"type":              item.Type,
+					"description":       item.Description,
+					"date":              item.Date,
+					"action_date":       item.ActionDate,
+					"pages":             item.Pages,
+					"barcode":           item.Barcode,
+					"document_metadata": item.Links.DocumentMetadata,
+				},
+			})
+		}
+
+		return len(apiResp.Items), apiResp.TotalCount, nil
+	})
+
+	return truncate(records, limit), err
+}
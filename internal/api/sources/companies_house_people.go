@@ -0,0 +1,275 @@
+package sources
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+// companiesHousePageSize is the largest page the list endpoints accept
+const companiesHousePageSize = 100
+
+// CompaniesHouseDateOfBirth is the partial date of birth published for people
+type CompaniesHouseDateOfBirth struct {
+	Month int `json:"month"`
+	Year  int `json:"year"`
+}
+
+// CompaniesHouseAddress is the address layout shared by the REST resources
+type CompaniesHouseAddress struct {
+	Premises     string `json:"premises"`
+	AddressLine1 string `json:"address_line_1"`
+	AddressLine2 string `json:"address_line_2"`
+	Locality     string `json:"locality"`
+	Region       string `json:"region"`
+	PostalCode   string `json:"postal_code"`
+	Country      string `json:"country"`
+}
+
+// CompaniesHouseOfficersResponse represents the /company/{n}/officers response
+type CompaniesHouseOfficersResponse struct {
+	Items []struct {
+		Name               string                     `json:"name"`
+		OfficerRole        string                     `json:"officer_role"`
+		AppointedOn        string                     `json:"appointed_on"`
+		ResignedOn         string                     `json:"resigned_on"`
+		Nationality        string                     `json:"nationality"`
+		CountryOfResidence string                     `json:"country_of_residence"`
+		Occupation         string                     `json:"occupation"`
+		DateOfBirth        *CompaniesHouseDateOfBirth `json:"date_of_birth"`
+		Address            CompaniesHouseAddress      `json:"address"`
+		Links              struct {
+			Self    string `json:"self"`
+			Officer struct {
+				Appointments string `json:"appointments"`
+			} `json:"officer"`
+		} `json:"links"`
+	} `json:"items"`
+	TotalResults int `json:"total_results"`
+	StartIndex   int `json:"start_index"`
+	ItemsPerPage int `json:"items_per_page"`
+}
+
+// CompaniesHousePSCResponse represents the /company/{n}/persons-with-significant-control response
+type CompaniesHousePSCResponse struct {
+	Items []struct {
+		Name               string                     `json:"name"`
+		Kind               string                     `json:"kind"`
+		NaturesOfControl   []string                   `json:"natures_of_control"`
+		NotifiedOn         string                     `json:"notified_on"`
+		CeasedOn           string                     `json:"ceased_on"`
+		Nationality        string                     `json:"nationality"`
+		CountryOfResidence string                     `json:"country_of_residence"`
+		DateOfBirth        *CompaniesHouseDateOfBirth `json:"date_of_birth"`
+		Address            CompaniesHouseAddress      `json:"address"`
+		Identification     struct {
+			LegalAuthority     string `json:"legal_authority"`
+			LegalForm          string `json:"legal_form"`
+			PlaceRegistered    string `json:"place_registered"`
+			RegistrationNumber string `json:"registration_number"`
+			CountryRegistered  string `json:"country_registered"`
+		} `json:"identification"`
+		Links struct {
+			Self string `json:"self"`
+		} `json:"links"`
+	} `json:"items"`
+	TotalResults int `json:"total_results"`
+	StartIndex   int `json:"start_index"`
+	ItemsPerPage int `json:"items_per_page"`
+}
+
+// CollectOfficers retrieves the officers of a company, following pagination
+// until limit records have been read or the list is exhausted (limit 0 reads all)
+func (ch *CompaniesHouseSource) CollectOfficers(ctx context.Context, companyNumber string, limit int) ([]api.RawRecord, error) {
+	var records []api.RawRecord
+
+	err := ch.collectPages(ctx, fmt.Sprintf("/company/%s/officers", url.PathEscape(companyNumber)), limit, func(body []byte) (int, int, error) {
+		var apiResp CompaniesHouseOfficersResponse
+		if err := json.Unmarshal(body, &apiResp); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse JSON response: %w", err)
+		}
+
+		for _, item := range apiResp.Items {
+			id := lastPathSegment(item.Links.Self)
+			if id == "" {
+				id = stableHash(item.Name, item.OfficerRole, item.AppointedOn)
+			}
+
+			// Officer links look like /officers/{officer_id}/appointments
+			officerID := lastPathSegment(strings.TrimSuffix(item.Links.Officer.Appointments, "/appointments"))
+
+			records = append(records, api.RawRecord{
+				ID:          fmt.Sprintf("ch_officer_%s_%s", companyNumber, id),
+				Source:      "companies_house_officers",
+				CollectedAt: time.Now(),
+				Data: map[string]interface{}{
+					"company_number":       companyNumber,
+					"officer_id":           officerID,
+					"name":                 item.Name,
+					"officer_role":         item.OfficerRole,
+					"appointed_on":         item.AppointedOn,
+					"resigned_on":          item.ResignedOn,
+					"nationality":          item.Nationality,
+					"country_of_residence": item.CountryOfResidence,
+					"occupation":           item.Occupation,
+					"date_of_birth":        dateOfBirthData(item.DateOfBirth),
+					"address":              addressData(item.Address),
+				},
+			})
+		}
+
+		return len(apiResp.Items), apiResp.TotalResults, nil
+	})
+
+	return truncate(records, limit), err
+}
+
+// CollectPersonsWithSignificantControl retrieves the PSC register of a company,
+// following pagination as CollectOfficers does
+func (ch *CompaniesHouseSource) CollectPersonsWithSignificantControl(ctx context.Context, companyNumber string, limit int) ([]api.RawRecord, error) {
+	var records []api.RawRecord
+
+	err := ch.collectPages(ctx, fmt.Sprintf("/company/%s/persons-with-significant-control", url.PathEscape(companyNumber)), limit, func(body []byte) (int, int, error) {
+		var apiResp CompaniesHousePSCResponse
+		if err := json.Unmarshal(body, &apiResp); err != nil {
+			return 0, 0, fmt.Errorf("failed to parse JSON response: %w", err)
+		}
+
+		for _, item := range apiResp.Items {
+			id := lastPathSegment(item.Links.Self)
+			if id == "" {
+				id = stableHash(item.Name, item.Kind, item.NotifiedOn)
+			}
+
+			natures := make([]interface{}, len(item.NaturesOfControl))
+			for i, nature := range item.NaturesOfControl {
+				natures[i] = nature
+			}
+
+			records = append(records, api.RawRecord{
+				ID:          fmt.Sprintf("ch_psc_%s_%s", companyNumber, id),
+				Source:      "companies_house_psc",
+				CollectedAt: time.Now(),
+				Data: map[string]interface{}{
+					"company_number":       companyNumber,
+					"name":                 item.Name,
+					"kind":                 item.Kind,
+					"natures_of_control":   natures,
+					"notified_on":          item.NotifiedOn,
+					"ceased_on":            item.CeasedOn,
+					"nationality":          item.Nationality,
+					"country_of_residence": item.CountryOfResidence,
+					"date_of_birth":        dateOfBirthData(item.DateOfBirth),
+					"address":              addressData(item.Address),
+					"identification": map[string]interface{}{
+						"legal_authority":     item.Identification.LegalAuthority,
+						"legal_form":          item.Identification.LegalForm,
+						"place_registered":    item.Identification.PlaceRegistered,
+						"registration_number": item.Identification.RegistrationNumber,
+						"country_registered":  item.Identification.CountryRegistered,
+					},
+				},
+			})
+		}
+
+		return len(apiResp.Items), apiResp.TotalResults, nil
+	})
+
+	return truncate(records, limit), err
+}
+
+// collectPages requests a list endpoint page by page. parse handles one page
+// body and reports the number of items on it and the total available.
+func (ch *CompaniesHouseSource) collectPages(ctx context.Context, endpoint string, limit int, parse func(body []byte) (int, int, error)) error {
+	if err := ch.Validate(); err != nil {
+		return err
+	}
+
+	pageSize := companiesHousePageSize
+	if limit > 0 && limit < pageSize {
+		pageSize = limit
+	}
+
+	for startIndex := 0; ; {
+		queryParams := url.Values{}
+		queryParams.Add("items_per_page", fmt.Sprintf("%d", pageSize))
+		queryParams.Add("start_index", fmt.Sprintf("%d", startIndex))
+
+		resp, err := ch.APIClient.MakeRequest(ctx, "GET", endpoint+"?"+queryParams.Encode(), map[string]string{
+			"Accept": "application/json",
+		})
+		if err != nil {
+			return fmt.Errorf("Companies House API request failed: %w", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		count, total, err := parse(body)
+		if err != nil {
+			return err
+		}
+
+		startIndex += count
+		if count == 0 || startIndex >= total || (limit > 0 && startIndex >= limit) {
+			return nil
+		}
+	}
+}
+
+// lastPathSegment returns the final element of a resource link such as
+// /company/12345678/appointments/AbCdEf, which Companies House keeps stable
+func lastPathSegment(link string) string {
+	link = strings.TrimSuffix(link, "/")
+	if link == "" {
+		return ""
+	}
+	return path.Base(link)
+}
+
+// stableHash derives a deterministic identifier for items without a self link
+func stableHash(parts ...string) string {
+	sum := sha1.Sum([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func truncate(records []api.RawRecord, limit int) []api.RawRecord {
+	if limit > 0 && len(records) > limit {
+		return records[:limit]
+	}
+	return records
+}
+
+func dateOfBirthData(dob *CompaniesHouseDateOfBirth) interface{} {
+	if dob == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"month": dob.Month,
+		"year":  dob.Year,
+	}
+}
+
+func addressData(addr CompaniesHouseAddress) map[string]interface{} {
+	return map[string]interface{}{
+		"premises":       addr.Premises,
+		"address_line_1": addr.AddressLine1,
+		"address_line_2": addr.AddressLine2,
+		"locality":       addr.Locality,
+		"region":         addr.Region,
+		"postal_code":    addr.PostalCode,
+		"country":        addr.Country,
+	}
+}
@@ -0,0 +1,120 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestCompaniesHouseSource_CollectOfficers_Pagination(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/company/12345678/officers" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		requests = append(requests, r.URL.RawQuery)
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("start_index"))
+		w.Header().Set("Content-Type", "application/json")
+		if start == 0 {
+			fmt.Fprint(w, `{"total_results": 3, "start_index": 0, "items_per_page": 2, "items": [
+				{"name": "SMITH, John", "officer_role": "director", "appointed_on": "2020-01-01",
+				 "date_of_birth": {"month": 4, "year": 1970},
+				 "links": {"self": "/company/12345678/appointments/appt1", "officer": {"appointments": "/officers/off1/appointments"}}},
+				{"name": "JONES, Mary", "officer_role": "secretary", "appointed_on": "2021-05-01", "resigned_on": "2022-01-01",
+				 "links": {"self": "/company/12345678/appointments/appt2", "officer": {"appointments": "/officers/off2/appointments"}}}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_results": 3, "start_index": 2, "items_per_page": 2, "items": [
+			{"name": "BROWN, Alan", "officer_role": "director", "appointed_on": "2022-02-02", "links": {}}
+		]}`)
+	}))
+	defer server.Close()
+
+	source := NewCompaniesHouseSource("test-api-key")
+	source.APIClient.BaseURL = server.URL
+
+	records, err := source.CollectOfficers(context.Background(), "12345678", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("Expected 2 page requests, got %d", len(requests))
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	if records[0].ID != "ch_officer_12345678_appt1" {
+		t.Errorf("Expected ID 'ch_officer_12345678_appt1', got '%s'", records[0].ID)
+	}
+	if records[0].Data["officer_id"] != "off1" {
+		t.Errorf("Expected officer_id 'off1', got '%v'", records[0].Data["officer_id"])
+	}
+	if records[0].Source != "companies_house_officers" {
+		t.Errorf("Expected source 'companies_house_officers', got '%s'", records[0].Source)
+	}
+
+	// Items without a self link get an ID derived from their content, which
+	// must not change between collections
+	again, err := source.CollectOfficers(context.Background(), "12345678", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if records[2].ID != again[2].ID {
+		t.Errorf("Expected stable ID, got '%s' then '%s'", records[2].ID, again[2].ID)
+	}
+}
+
+func TestCompaniesHouseSource_CollectPSCAndFilings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/company/12345678/persons-with-significant-control":
+			fmt.Fprint(w, `{"total_results": 1, "items": [
+				{"name": "Mr John Smith", "kind": "individual-person-with-significant-control",
+				 "natures_of_control": ["ownership-of-shares-75-to-100-percent"], "notified_on": "2020-01-01",
+				 "links": {"self": "/company/12345678/persons-with-significant-control/individual/psc1"}}
+			]}`)
+		case "/company/12345678/filing-history":
+			if r.URL.Query().Get("items_per_page") != "1" {
+				t.Errorf("Expected items_per_page 1, got '%s'", r.URL.Query().Get("items_per_page"))
+			}
+			fmt.Fprint(w, `{"total_count": 40, "items": [
+				{"transaction_id": "MzAwMDAwMDAwMA", "category": "accounts", "type": "AA", "date": "2023-09-30", "pages": 10}
+			]}`)
+		default:
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := NewCompaniesHouseSource("test-api-key")
+	source.APIClient.BaseURL = server.URL
+
+	pscs, err := source.CollectPersonsWithSignificantControl(context.Background(), "12345678", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(pscs) != 1 || pscs[0].ID != "ch_psc_12345678_psc1" {
+		t.Errorf("Expected PSC 'ch_psc_12345678_psc1', got %v", pscs)
+	}
+
+	// The limit stops pagination even though more filings are available
+	filings, err := source.CollectFilingHistory(context.Background(), "12345678", 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(filings) != 1 || filings[0].ID != "ch_filing_12345678_MzAwMDAwMDAwMA" {
+		t.Errorf("Expected filing 'ch_filing_12345678_MzAwMDAwMDAwMA', got %v", filings)
+	}
+	if filings[0].Data["pages"] != 10 {
+		t.Errorf("Expected 10 pages, got %v", filings[0].Data["pages"])
+	}
+}
@@ -0,0 +1,218 @@
+package sources
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+// Event types published on the streaming API
+const (
+	StreamEventChanged = "changed"
+	StreamEventDeleted = "deleted"
+)
+
+// maxStreamEventSize bounds a single newline-delimited event
+const maxStreamEventSize = 4 * 1024 * 1024
+
+// DefaultStreamIdleTimeout is how long a stream may send nothing, not even
+// a heartbeat, before the connection is considered dead. Companies House
+// sends heartbeats about every 30 seconds.
+const DefaultStreamIdleTimeout = 2 * time.Minute
+
+// ErrStreamIdle is returned by Stream when the connection went silent
+var ErrStreamIdle = errors.New("stream idle for too long")
+
+// CompaniesHouseStream reads the Companies House streaming API, which keeps
+// a single HTTP connection open and writes one JSON event per line
+type CompaniesHouseStream struct {
+	HTTPClient *http.Client
+	Logger     *logrus.Logger
+	BaseURL    string
+	StreamKey  string
+	// IdleTimeout closes a connection that sends nothing for this long;
+	// zero waits indefinitely
+	IdleTimeout time.Duration
+}
+
+// StreamEvent is one event from the streaming API
+type StreamEvent struct {
+	ResourceKind string          `json:"resource_kind"`
+	ResourceURI  string          `json:"resource_uri"`
+	ResourceID   string          `json:"resource_id"`
+	Data         json.RawMessage `json:"data"`
+	Event        struct {
+		Timepoint   int64  `json:"timepoint"`
+		PublishedAt string `json:"published_at"`
+		Type        string `json:"type"`
+	} `json:"event"`
+}
+
+// NewCompaniesHouseStream creates a streaming API client. Streams stay open
+// indefinitely, so the HTTP client has no overall timeout; use the context
+// to stop reading.
+func NewCompaniesHouseStream(streamKey string) *CompaniesHouseStream {
+	return &CompaniesHouseStream{
+		HTTPClient:  &http.Client{},
+		Logger:      logrus.New(),
+		BaseURL:     "https://stream.companieshouse.gov.uk",
+		StreamKey:   streamKey,
+		IdleTimeout: DefaultStreamIdleTimeout,
+	}
+}
+
+// Stream connects to a stream such as /companies and passes each event to
+// fn until the server closes the connection, ctx is cancelled or fn returns
+// an error. When timepoint is positive the stream starts from that point.
+// A connection that stays silent for IdleTimeout is closed with
+// ErrStreamIdle.
+func (s *CompaniesHouseStream) Stream(ctx context.Context, path string, timepoint int64, fn func(StreamEvent) error) error {
+	if s.StreamKey == "" {
+		return api.ErrAPIKeyMissing
+	}
+
+	// The request is cancelled when the idle timer fires, which unblocks
+	// a read waiting on a dead connection
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	resetIdle := func() {}
+	if s.IdleTimeout > 0 {
+		idle := time.AfterFunc(s.IdleTimeout, func() { cancel(ErrStreamIdle) })
+		defer idle.Stop()
+		resetIdle = func() { idle.Reset(s.IdleTimeout) }
+	}
+
+	endpoint := s.BaseURL + path
+	if timepoint > 0 {
+		endpoint += "?" + url.Values{"timepoint": {strconv.FormatInt(timepoint, 10)}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	// The stream key is sent as the basic auth user name with no password
+	req.SetBasicAuth(s.StreamKey, "")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, ErrStreamIdle) {
+			return cause
+		}
+		return fmt.Errorf("stream connection failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return api.ErrUnauthorized
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return api.ErrRateLimitExceeded
+	case resp.StatusCode >= 400:
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	s.Logger.WithFields(logrus.Fields{
+		"stream":    path,
+		"timepoint": timepoint,
+	}).Info("Stream connected")
+
+	reader := bufio.NewReaderSize(resp.Body, 64*1024)
+	for {
+		line, size, readErr := readLine(reader, maxStreamEventSize)
+		resetIdle()
+
+		switch {
+		case size > maxStreamEventSize:
+			// An oversized event is read to its end and skipped, as
+			// reconnecting would receive it again
+			s.Logger.WithFields(logrus.Fields{
+				"stream": path,
+				"size":   size,
+			}).Warn("Skipping oversized stream event")
+		case len(line) == 0:
+			// Empty lines are heartbeats that keep the connection alive
+		default:
+			// A line that is not JSON has no timepoint to resume after, and
+			// reconnecting would receive it again, so it is logged and skipped
+			var event StreamEvent
+			if err := json.Unmarshal(line, &event); err != nil {
+				s.Logger.WithFields(logrus.Fields{
+					"stream": path,
+					"line":   string(line[:min(len(line), 200)]),
+					"error":  err,
+				}).Warn("Skipping unreadable stream event")
+				break
+			}
+
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		if readErr == io.EOF {
+			return ctx.Err()
+		}
+		if readErr != nil {
+			if cause := context.Cause(ctx); errors.Is(cause, ErrStreamIdle) {
+				return cause
+			}
+			return fmt.Errorf("stream read failed: %w", readErr)
+		}
+	}
+}
+
+// readLine reads the next line from r without its line ending, and returns
+// the line's size. A line longer than limit is read to its end but returned
+// empty, so that the caller can skip it.
+func readLine(r *bufio.Reader, limit int) ([]byte, int, error) {
+	var line []byte
+	size := 0
+	for {
+		chunk, err := r.ReadSlice('\n')
+		size += len(chunk)
+		if size <= limit {
+			line = append(line, chunk...)
+		} else {
+			line = nil
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return bytes.TrimRight(line, "\r\n"), size, err
+	}
+}
+
+// CompanyProfileRecord converts the data of a company-profile event into the
+// record layout produced by CompaniesHouseSource
+func CompanyProfileRecord(event StreamEvent) (api.RawRecord, error) {
+	var profile CompaniesHouseProfile
+	if err := json.Unmarshal(event.Data, &profile); err != nil {
+		return api.RawRecord{}, fmt.Errorf("failed to parse company profile: %w", err)
+	}
+	if profile.CompanyNumber == "" {
+		profile.CompanyNumber = event.ResourceID
+	}
+
+	collectedAt := time.Now()
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, event.Event.PublishedAt); err == nil {
+			collectedAt = t
+			break
+		}
+	}
+
+	return profileRecord(profile, collectedAt), nil
+}
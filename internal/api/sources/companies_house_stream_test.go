@@ -0,0 +1,136 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+func TestCompaniesHouseStream_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		if !ok || user != "stream-key" {
+			t.Errorf("Expected basic auth user 'stream-key', got '%s'", user)
+		}
+		if r.URL.Query().Get("timepoint") != "42" {
+			t.Errorf("Expected timepoint 42, got '%s'", r.URL.Query().Get("timepoint"))
+		}
+
+		// Write events as separate chunks with heartbeats in between
+		flusher := w.(http.Flusher)
+		fmt.Fprint(w, `{"resource_kind":"company-profile","resource_id":"12345678","data":{"company_number":"12345678","company_name":"TEST COMPANY LTD","company_status":"active"},"event":{"timepoint":42,"published_at":"2024-01-15T10:03:12","type":"changed"}}`+"\n")
+		flusher.Flush()
+		fmt.Fprint(w, "\n\n")
+		flusher.Flush()
+		// A malformed line is skipped rather than ending the stream
+		fmt.Fprint(w, `{"resource_kind":"company-profile","data":`+"\n")
+		flusher.Flush()
+		fmt.Fprint(w, `{"resource_kind":"company-profile","resource_id":"87654321","data":{},"event":{"timepoint":43,"type":"deleted"}}`+"\n")
+		flusher.Flush()
+	}))
+	defer server.Close()
+
+	stream := NewCompaniesHouseStream("stream-key")
+	stream.BaseURL = server.URL
+
+	var events []StreamEvent
+	err := stream.Stream(context.Background(), "/companies", 42, func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[1].Event.Type != StreamEventDeleted || events[1].Event.Timepoint != 43 {
+		t.Errorf("Unexpected second event %+v", events[1].Event)
+	}
+
+	record, err := CompanyProfileRecord(events[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if record.ID != "ch_12345678" || record.Data["name"] != "TEST COMPANY LTD" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if record.CollectedAt.Format("2006-01-02") != "2024-01-15" {
+		t.Errorf("Expected collected date from published_at, got %v", record.CollectedAt)
+	}
+}
+
+func TestCompaniesHouseStream_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	stream := NewCompaniesHouseStream("")
+	stream.BaseURL = server.URL
+	noop := func(StreamEvent) error { return nil }
+
+	if err := stream.Stream(context.Background(), "/companies", 0, noop); err != api.ErrAPIKeyMissing {
+		t.Errorf("Expected ErrAPIKeyMissing, got %v", err)
+	}
+
+	stream.StreamKey = "bad-key"
+	if err := stream.Stream(context.Background(), "/companies", 0, noop); err != api.ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestCompaniesHouseStream_OversizedEvent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// An event larger than the limit is skipped and the next one read
+		fmt.Fprint(w, `{"resource_kind":"company-profile","data":"`+strings.Repeat("x", maxStreamEventSize)+`"}`+"\n")
+		fmt.Fprint(w, `{"resource_kind":"company-profile","resource_id":"87654321","data":{},"event":{"timepoint":43,"type":"deleted"}}`+"\n")
+	}))
+	defer server.Close()
+
+	stream := NewCompaniesHouseStream("stream-key")
+	stream.BaseURL = server.URL
+
+	var events []StreamEvent
+	err := stream.Stream(context.Background(), "/companies", 0, func(event StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 1 || events[0].Event.Timepoint != 43 {
+		t.Errorf("Expected only the event after the oversized one, got %+v", events)
+	}
+}
+
+func TestCompaniesHouseStream_Idle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "\n")
+		w.(http.Flusher).Flush()
+		// The connection stays open without heartbeats
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	stream := NewCompaniesHouseStream("stream-key")
+	stream.BaseURL = server.URL
+	stream.IdleTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := stream.Stream(context.Background(), "/companies", 0, func(StreamEvent) error { return nil })
+	if !errors.Is(err, ErrStreamIdle) {
+		t.Errorf("Expected ErrStreamIdle, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the idle stream to be closed promptly, took %v", elapsed)
+	}
+}
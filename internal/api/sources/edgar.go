@@ -0,0 +1,353 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"golang.org/x/time/rate"
+)
+
+// EDGARSource implements data collection from SEC EDGAR. Tickers are
+// resolved through company_tickers.json on www.sec.gov and company details
+// are read from the submissions API on data.sec.gov.
+type EDGARSource struct {
+	api.BaseDataSource
+	// TickersClient requests www.sec.gov and shares the rate limiter of
+	// APIClient, since SEC's fair-access limit applies across both hosts
+	TickersClient *api.APIClient
+	// UserAgent identifies the requester as SEC requires, e.g.
+	// "Sample Company admin@example.com"
+	UserAgent string
+	// FilingLimit caps the number of recent filings kept per company
+	FilingLimit int
+
+	tickersMu sync.Mutex
+	tickers   []EDGARTicker
+}
+
+// EDGARTicker is an entry of company_tickers.json
+type EDGARTicker struct {
+	CIK    int64  `json:"cik_str"`
+	Ticker string `json:"ticker"`
+	Title  string `json:"title"`
+}
+
+// EDGARAddress represents an address in EDGAR submissions
+type EDGARAddress struct {
+	Street1        string `json:"street1"`
+	Street2        string `json:"street2"`
+	City           string `json:"city"`
+	StateOrCountry string `json:"stateOrCountry"`
+	ZipCode        string `json:"zipCode"`
+}
+
+// EDGARSubmissions represents the submissions document of a company
+type EDGARSubmissions struct {
+	CIK                  string   `json:"cik"`
+	EntityType           string   `json:"entityType"`
+	SIC                  string   `json:"sic"`
+	SICDescription       string   `json:"sicDescription"`
+	Name                 string   `json:"name"`
+	Tickers              []string `json:"tickers"`
+	Exchanges            []string `json:"exchanges"`
+	EIN                  string   `json:"ein"`
+	StateOfIncorporation string   `json:"stateOfIncorporation"`
+	FiscalYearEnd        string   `json:"fiscalYearEnd"`
+	Website              string   `json:"website"`
+	Phone                string   `json:"phone"`
+	Addresses            struct {
+		Mailing  EDGARAddress `json:"mailing"`
+		Business EDGARAddress `json:"business"`
+	} `json:"addresses"`
+	FormerNames []struct {
+		Name string `json:"name"`
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"formerNames"`
+	Filings struct {
+		Recent struct {
+			AccessionNumber       []string `json:"accessionNumber"`
+			FilingDate            []string `json:"filingDate"`
+			ReportDate            []string `json:"reportDate"`
+			Form                  []string `json:"form"`
+			PrimaryDocument       []string `json:"primaryDocument"`
+			PrimaryDocDescription []string `json:"primaryDocDescription"`
+		} `json:"recent"`
+	} `json:"filings"`
+}
+
+// NewEDGARSource creates a new SEC EDGAR data source
+func NewEDGARSource(userAgent string) *EDGARSource {
+	config := api.ClientConfig{
+		APIName:          "SEC EDGAR",
+		BaseURL:          "https://data.sec.gov",
+		RateLimit:        rate.Limit(10), // SEC fair access: 10 requests per second
+		RateBurst:        1,
+		Timeout:          30 * time.Second,
+		MaxRetries:       3,
+		CircuitThreshold: 5,
+	}
+	client := api.NewAPIClient(config)
+
+	config.BaseURL = "https://www.sec.gov"
+	tickersClient := api.NewAPIClient(config)
+	tickersClient.RateLimiter = client.RateLimiter
+
+	return &EDGARSource{
+		BaseDataSource: api.BaseDataSource{
+			Name:      "SEC EDGAR",
+			APIClient: client,
+			RateLimit: config.RateLimit,
+		},
+		TickersClient: tickersClient,
+		UserAgent:     userAgent,
+		FilingLimit:   20,
+	}
+}
+
+// Collect retrieves company data from EDGAR. Filters["cik"] or
+// Filters["ticker"] look up a single company; otherwise Query is matched
+// against ticker symbols and company names and must not be empty.
+func (e *EDGARSource) Collect(ctx context.Context, params api.CollectionParams) ([]api.RawRecord, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cik, ok := params.Filters["cik"].(string); ok && cik != "" {
+		record, err := e.LookupCIK(ctx, cik)
+		if err != nil {
+			return nil, err
+		}
+		return []api.RawRecord{record}, nil
+	}
+
+	query := params.Query
+	if ticker, ok := params.Filters["ticker"].(string); ok && ticker != "" {
+		query = ticker
+	}
+
+	matches, err := e.SearchTickers(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Offset >= len(matches) {
+		return nil, nil
+	}
+	matches = matches[params.Offset:]
+	if params.Limit > 0 && len(matches) > params.Limit {
+		matches = matches[:params.Limit]
+	}
+
+	var records []api.RawRecord
+	for _, match := range matches {
+		record, err := e.LookupCIK(ctx, strconv.FormatInt(match.CIK, 10))
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// SearchTickers returns the companies whose ticker equals query or whose
+// name contains it, exact ticker matches first. Companies listed under
+// several tickers are returned once. An empty query, which every name
+// contains, returns api.ErrQueryMissing.
+func (e *EDGARSource) SearchTickers(ctx context.Context, query string) ([]EDGARTicker, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, api.ErrQueryMissing
+	}
+
+	tickers, err := e.loadTickers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lowerQuery := strings.ToLower(query)
+	seen := make(map[int64]bool)
+
+	var exact, partial []EDGARTicker
+	for _, t := range tickers {
+		switch {
+		case strings.EqualFold(t.Ticker, query):
+			exact = append(exact, t)
+		case strings.Contains(strings.ToLower(t.Title), lowerQuery):
+			partial = append(partial, t)
+		}
+	}
+
+	var matches []EDGARTicker
+	for _, t := range append(exact, partial...) {
+		if !seen[t.CIK] {
+			seen[t.CIK] = true
+			matches = append(matches, t)
+		}
+	}
+
+	return matches, nil
+}
+
+// loadTickers fetches company_tickers.json once per source
+func (e *EDGARSource) loadTickers(ctx context.Context) ([]EDGARTicker, error) {
+	e.tickersMu.Lock()
+	defer e.tickersMu.Unlock()
+
+	if e.tickers != nil {
+		return e.tickers, nil
+	}
+
+	var byIndex map[string]EDGARTicker
+	if err := e.get(ctx, e.TickersClient, "/files/company_tickers.json", &byIndex); err != nil {
+		return nil, err
+	}
+
+	// The file is keyed by rank, so restore that order
+	tickers := make([]EDGARTicker, len(byIndex))
+	for key, t := range byIndex {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(tickers) {
+			return nil, fmt.Errorf("unexpected key %q in company tickers: %w", key, api.ErrInvalidResponse)
+		}
+		tickers[i] = t
+	}
+
+	e.tickers = tickers
+	return tickers, nil
+}
+
+// LookupCIK retrieves the submissions of the company with the given CIK
+func (e *EDGARSource) LookupCIK(ctx context.Context, cik string) (api.RawRecord, error) {
+	if err := e.Validate(); err != nil {
+		return api.RawRecord{}, err
+	}
+
+	number, err := strconv.ParseInt(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(cik)), "CIK"), 10, 64)
+	if err != nil {
+		return api.RawRecord{}, fmt.Errorf("invalid CIK %q: %w", cik, err)
+	}
+	padded := fmt.Sprintf("%010d", number)
+
+	var submissions EDGARSubmissions
+	if err := e.get(ctx, e.APIClient, "/submissions/CIK"+padded+".json", &submissions); err != nil {
+		return api.RawRecord{}, err
+	}
+
+	return e.toRawRecord(padded, submissions), nil
+}
+
+func (e *EDGARSource) get(ctx context.Context, client *api.APIClient, endpoint string, v interface{}) error {
+	resp, err := client.MakeRequest(ctx, "GET", endpoint, map[string]string{
+		"User-Agent": e.UserAgent,
+		"Accept":     "application/json",
+	})
+	if err != nil {
+		return fmt.Errorf("SEC EDGAR request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return nil
+}
+
+func (e *EDGARSource) toRawRecord(cik string, s EDGARSubmissions) api.RawRecord {
+	tickers := make([]interface{}, len(s.Tickers))
+	for i, ticker := range s.Tickers {
+		tickers[i] = ticker
+	}
+	exchanges := make([]interface{}, len(s.Exchanges))
+	for i, exchange := range s.Exchanges {
+		exchanges[i] = exchange
+	}
+
+	formerNames := make([]interface{}, 0, len(s.FormerNames))
+	for _, former := range s.FormerNames {
+		formerNames = append(formerNames, map[string]interface{}{
+			"name": former.Name,
+			"from": former.From,
+			"to":   former.To,
+		})
+	}
+
+	// Recent filings are stored as parallel arrays, newest first
+	recent := s.Filings.Recent
+	filings := make([]interface{}, 0)
+	for i := 0; i < len(recent.AccessionNumber) && (e.FilingLimit <= 0 || i < e.FilingLimit); i++ {
+		filings = append(filings, map[string]interface{}{
+			"accession_number": recent.AccessionNumber[i],
+			"form":             at(recent.Form, i),
+			"filing_date":      at(recent.FilingDate, i),
+			"report_date":      at(recent.ReportDate, i),
+			"primary_document": at(recent.PrimaryDocument, i),
+			"description":      at(recent.PrimaryDocDescription, i),
+		})
+	}
+
+	return api.RawRecord{
+		ID:          fmt.Sprintf("edgar_%s", cik),
+		Source:      "sec_edgar",
+		CollectedAt: time.Now(),
+		Data: map[string]interface{}{
+			"cik":                    cik,
+			"name":                   s.Name,
+			"entity_type":            s.EntityType,
+			"sic_code":               s.SIC,
+			"sic_description":        s.SICDescription,
+			"tickers":                tickers,
+			"exchanges":              exchanges,
+			"ein":                    s.EIN,
+			"state_of_incorporation": s.StateOfIncorporation,
+			"fiscal_year_end":        s.FiscalYearEnd,
+			"website":                s.Website,
+			"phone":                  s.Phone,
+			"former_names":           formerNames,
+			"business_address":       edgarAddressData(s.Addresses.Business),
+			"mailing_address":        edgarAddressData(s.Addresses.Mailing),
+			"recent_filings":         filings,
+		},
+	}
+}
+
+// Validate checks if the data source is properly configured. SEC rejects
+// requests that do not identify the requester in the User-Agent header.
+func (e *EDGARSource) Validate() error {
+	if strings.TrimSpace(e.UserAgent) == "" {
+		return api.ErrUserAgentMissing
+	}
+	return nil
+}
+
+func edgarAddressData(addr EDGARAddress) map[string]interface{} {
+	return map[string]interface{}{
+		"street1":          addr.Street1,
+		"street2":          addr.Street2,
+		"city":             addr.City,
+		"state_or_country": addr.StateOrCountry,
+		"zip_code":         addr.ZipCode,
+	}
+}
+
+// at returns values[i], or "" when the array is shorter
+func at(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+func TestEDGARSource_Collect(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "Test Company test@example.com" {
+			t.Errorf("Expected User-Agent 'Test Company test@example.com', got '%s'", r.Header.Get("User-Agent"))
+		}
+		paths = append(paths, r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/files/company_tickers.json":
+			fmt.Fprint(w, `{"0": {"cik_str": 320193, "ticker": "AAPL", "title": "Apple Inc."},
+				"1": {"cik_str": 1652044, "ticker": "GOOGL", "title": "Alphabet Inc."},
+				"2": {"cik_str": 1652044, "ticker": "GOOG", "title": "Alphabet Inc."}}`)
+		case "/submissions/CIK0001652044.json":
+			fmt.Fprint(w, `{"cik": "1652044", "entityType": "operating", "sic": "7370",
+				"sicDescription": "Services-Computer Programming, Data Processing, Etc.",
+				"name": "Alphabet Inc.", "tickers": ["GOOGL", "GOOG"], "exchanges": ["Nasdaq", "Nasdaq"],
+				"stateOfIncorporation": "DE",
+				"addresses": {
+					"mailing": {"street1": "1600 AMPHITHEATRE PARKWAY", "street2": null, "city": "MOUNTAIN VIEW", "stateOrCountry": "CA", "zipCode": "94043"},
+					"business": {"street1": "1600 AMPHITHEATRE PARKWAY", "city": "MOUNTAIN VIEW", "stateOrCountry": "CA", "zipCode": "94043"}},
+				"phone": "650-253-0000",
+				"filings": {"recent": {
+					"accessionNumber": ["0001652044-24-000001", "0001652044-24-000002", "0001652044-23-000099"],
+					"filingDate": ["2024-02-01", "2024-01-15", "2023-10-25"],
+					"reportDate": ["2023-12-31", "", "2023-09-30"],
+					"form": ["10-K", "8-K", "10-Q"],
+					"primaryDocument": ["goog-20231231.htm", "a8k.htm", "goog-20230930.htm"],
+					"primaryDocDescription": ["10-K", "8-K", "10-Q"]}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := NewEDGARSource("Test Company test@example.com")
+	source.APIClient.BaseURL = server.URL
+	source.TickersClient.BaseURL = server.URL
+	source.FilingLimit = 2
+
+	records, err := source.Collect(context.Background(), api.CollectionParams{Query: "alphabet", Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Alphabet is listed under two tickers but must be fetched once
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	if len(paths) != 2 {
+		t.Errorf("Expected 2 requests, got %v", paths)
+	}
+
+	record := records[0]
+	if record.ID != "edgar_0001652044" || record.Source != "sec_edgar" {
+		t.Errorf("Unexpected record ID '%s' or source '%s'", record.ID, record.Source)
+	}
+	if record.Data["sic_code"] != "7370" || record.Data["state_of_incorporation"] != "DE" {
+		t.Errorf("Unexpected SIC code or state of incorporation in %v", record.Data)
+	}
+
+	business, ok := record.Data["business_address"].(map[string]interface{})
+	if !ok || business["city"] != "MOUNTAIN VIEW" || business["zip_code"] != "94043" {
+		t.Errorf("Unexpected business address %v", record.Data["business_address"])
+	}
+
+	filings, ok := record.Data["recent_filings"].([]interface{})
+	if !ok || len(filings) != 2 {
+		t.Fatalf("Expected 2 recent filings, got %v", record.Data["recent_filings"])
+	}
+	if first := filings[0].(map[string]interface{}); first["form"] != "10-K" || first["filing_date"] != "2024-02-01" {
+		t.Errorf("Unexpected first filing %v", first)
+	}
+
+	// The ticker list is cached for later lookups
+	paths = nil
+	if _, err := source.Collect(context.Background(), api.CollectionParams{Filters: map[string]interface{}{"ticker": "aapl"}}); err == nil {
+		t.Errorf("Expected error for missing Apple submissions")
+	}
+	if len(paths) != 1 || paths[0] != "/submissions/CIK0000320193.json" {
+		t.Errorf("Expected only the Apple submissions request, got %v", paths)
+	}
+}
+
+func TestEDGARSource_EmptyQuery(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"0": {"cik_str": 320193, "ticker": "AAPL", "title": "Apple Inc."}}`)
+	}))
+	defer server.Close()
+
+	source := NewEDGARSource("Test Company test@example.com")
+	source.APIClient.BaseURL = server.URL
+	source.TickersClient.BaseURL = server.URL
+
+	// An empty query would match every company name
+	if _, err := source.Collect(context.Background(), api.CollectionParams{Query: " "}); err != api.ErrQueryMissing {
+		t.Errorf("Expected ErrQueryMissing, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+}
+
+func TestEDGARSource_Validate(t *testing.T) {
+	source := NewEDGARSource("")
+	if err := source.Validate(); err != api.ErrUserAgentMissing {
+		t.Errorf("Expected ErrUserAgentMissing, got %v", err)
+	}
+
+	if _, err := source.Collect(context.Background(), api.CollectionParams{Query: "apple"}); err != api.ErrUserAgentMissing {
+		t.Errorf("Expected ErrUserAgentMissing from Collect, got %v", err)
+	}
+}
+
+func TestEDGARSource_SharedRateLimiter(t *testing.T) {
+	source := NewEDGARSource("Test Company test@example.com")
+	if source.TickersClient.RateLimiter != source.APIClient.RateLimiter {
+		t.Errorf("Expected both EDGAR hosts to share one rate limiter")
+	}
+}
@@ -0,0 +1,565 @@
+package sources
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
+)
+
+// Pagination styles of a generic REST source
+const (
+	PaginationNone       = "none"
+	PaginationOffset     = "offset"
+	PaginationPage       = "page"
+	PaginationCursor     = "cursor"
+	PaginationLinkHeader = "link_header"
+)
+
+// Authentication types of a generic REST source
+const (
+	AuthNone   = "none"
+	AuthBearer = "bearer"
+	AuthHeader = "header"
+	AuthQuery  = "query"
+	AuthBasic  = "basic"
+)
+
+// maxGenericPages stops runaway pagination against APIs that never report
+// an end
+const maxGenericPages = 100
+
+// GenericRESTConfig describes a REST API in configuration. Endpoint and
+// parameter values may contain the placeholders {query}, {location},
+// {limit}, {offset} and {filter.NAME}, which are filled from
+// CollectionParams; parameters that resolve to "" are left out.
+type GenericRESTConfig struct {
+	Name       string            `yaml:"name"`
+	Enabled    bool              `yaml:"enabled"`
+	BaseURL    string            `yaml:"base_url"`
+	Endpoint   string            `yaml:"endpoint"`
+	Params     map[string]string `yaml:"params"`
+	Headers    map[string]string `yaml:"headers"`
+	RateLimit  float64           `yaml:"rate_limit"`
+	Auth       GenericAuthConfig `yaml:"auth"`
+	Pagination GenericPagination `yaml:"pagination"`
+	// RecordsPath locates the array of records in a response; empty means
+	// the response itself is the array
+	RecordsPath string `yaml:"records_path"`
+	// IDPath locates the record identifier within a record
+	IDPath   string `yaml:"id_path"`
+	IDPrefix string `yaml:"id_prefix"`
+	// Fields maps RawRecord.Data keys to paths within a record. Dotted keys
+	// such as "address.city" produce nested maps. Without fields the whole
+	// record is kept.
+	Fields map[string]string `yaml:"fields"`
+}
+
+// GenericAuthConfig describes how a generic REST source authenticates.
+// Secrets may reference environment variables as ${NAME}.
+type GenericAuthConfig struct {
+	Type string `yaml:"type"`
+	// Name is the header or query parameter carrying the key
+	Name     string `yaml:"name"`
+	Key      string `yaml:"key"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// GenericPagination describes how a generic REST source pages through results
+type GenericPagination struct {
+	Type string `yaml:"type"`
+	// Param is the offset, page or cursor query parameter
+	Param string `yaml:"param"`
+	// SizeParam is the page size query parameter
+	SizeParam string `yaml:"size_param"`
+	PageSize  int    `yaml:"page_size"`
+	// FirstPage is the number of the first page for page pagination,
+	// 1 when unset
+	FirstPage int `yaml:"first_page"`
+	// CursorPath locates the next cursor in a response
+	CursorPath string `yaml:"cursor_path"`
+}
+
+// GenericRESTSource collects records from a REST API described by a
+// GenericRESTConfig instead of code
+type GenericRESTSource struct {
+	api.BaseDataSource
+	Config GenericRESTConfig
+}
+
+// NewGenericRESTSource creates a data source from its configuration
+func NewGenericRESTSource(cfg GenericRESTConfig) *GenericRESTSource {
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = 1
+	}
+	if cfg.Pagination.Type == "" {
+		cfg.Pagination.Type = PaginationNone
+	}
+	if cfg.Pagination.PageSize <= 0 {
+		cfg.Pagination.PageSize = 20
+	}
+	if cfg.Pagination.FirstPage == 0 {
+		cfg.Pagination.FirstPage = 1
+	}
+	if cfg.Auth.Type == "" {
+		cfg.Auth.Type = AuthNone
+	}
+
+	config := api.ClientConfig{
+		APIName:          cfg.Name,
+		BaseURL:          strings.TrimRight(cfg.BaseURL, "/"),
+		RateLimit:        rate.Limit(cfg.RateLimit),
+		RateBurst:        max(1, int(cfg.RateLimit)),
+		Timeout:          30 * time.Second,
+		MaxRetries:       3,
+		CircuitThreshold: 5,
+	}
+	if cfg.Auth.Type == AuthBearer {
+		config.APIKey = os.ExpandEnv(cfg.Auth.Key)
+	}
+
+	return &GenericRESTSource{
+		BaseDataSource: api.BaseDataSource{
+			Name:      cfg.Name,
+			APIClient: api.NewAPIClient(config),
+			RateLimit: config.RateLimit,
+		},
+		Config: cfg,
+	}
+}
+
+// Collect requests pages until params.Limit records are collected or the
+// API has no more results
+func (g *GenericRESTSource) Collect(ctx context.Context, params api.CollectionParams) ([]api.RawRecord, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	endpoint := g.firstEndpoint(params)
+	pagination := g.Config.Pagination
+	var records []api.RawRecord
+
+	// Page pagination starts at the page holding params.Offset, so the
+	// records before the offset on that page are skipped
+	skip := 0
+	if pagination.Type == PaginationPage {
+		skip = max(params.Offset, 0) % pagination.PageSize
+	}
+
+	for page := 0; page < maxGenericPages && endpoint != ""; page++ {
+		body, next, err := g.fetch(ctx, endpoint)
+		if err != nil {
+			return records, err
+		}
+
+		items, err := g.items(body)
+		if err != nil {
+			return records, err
+		}
+		for i, item := range items {
+			if page == 0 && i < skip {
+				continue
+			}
+			record, err := g.toRawRecord(item)
+			if err != nil {
+				return records, err
+			}
+			records = append(records, record)
+		}
+
+		if pagination.Type == PaginationNone || len(items) == 0 || (params.Limit > 0 && len(records) >= params.Limit) {
+			break
+		}
+
+		switch pagination.Type {
+		case PaginationOffset, PaginationPage:
+			if len(items) < pagination.PageSize {
+				endpoint = ""
+				break
+			}
+			endpoint = g.pagedEndpoint(params, page+1)
+		case PaginationCursor:
+			endpoint, err = g.cursorEndpoint(params, body)
+		case PaginationLinkHeader:
+			endpoint, err = g.linkEndpoint(next)
+		}
+		if err != nil {
+			return records, err
+		}
+	}
+
+	return truncate(records, params.Limit), nil
+}
+
+// Validate checks that the configuration is complete and consistent
+func (g *GenericRESTSource) Validate() error {
+	cfg := g.Config
+	if cfg.Name == "" || cfg.BaseURL == "" {
+		return fmt.Errorf("generic REST source needs a name and base_url")
+	}
+
+	switch cfg.Auth.Type {
+	case AuthNone:
+	case AuthBearer, AuthHeader, AuthQuery:
+		if os.ExpandEnv(cfg.Auth.Key) == "" {
+			return api.ErrAPIKeyMissing
+		}
+		if cfg.Auth.Type != AuthBearer && cfg.Auth.Name == "" {
+			return fmt.Errorf("%s: auth type %q needs a name", cfg.Name, cfg.Auth.Type)
+		}
+	case AuthBasic:
+		if os.ExpandEnv(cfg.Auth.Username) == "" {
+			return api.ErrAPIKeyMissing
+		}
+	default:
+		return fmt.Errorf("%s: unknown auth type %q", cfg.Name, cfg.Auth.Type)
+	}
+
+	switch cfg.Pagination.Type {
+	case PaginationNone, PaginationLinkHeader:
+	case PaginationOffset, PaginationPage:
+		if cfg.Pagination.Param == "" {
+			return fmt.Errorf("%s: %s pagination needs a param", cfg.Name, cfg.Pagination.Type)
+		}
+	case PaginationCursor:
+		if cfg.Pagination.Param == "" || cfg.Pagination.CursorPath == "" {
+			return fmt.Errorf("%s: cursor pagination needs a param and cursor_path", cfg.Name)
+		}
+	default:
+		return fmt.Errorf("%s: unknown pagination type %q", cfg.Name, cfg.Pagination.Type)
+	}
+
+	for key, path := range cfg.Fields {
+		if _, err := parsePath(path); err != nil {
+			return fmt.Errorf("%s: field %s: %w", cfg.Name, key, err)
+		}
+	}
+
+	return nil
+}
+
+// firstEndpoint builds the endpoint of the first page
+func (g *GenericRESTSource) firstEndpoint(params api.CollectionParams) string {
+	switch g.Config.Pagination.Type {
+	case PaginationOffset, PaginationPage:
+		return g.pagedEndpoint(params, 0)
+	}
+
+	query := g.baseQuery(params)
+	if p := g.Config.Pagination; p.Type != PaginationNone && p.SizeParam != "" {
+		query.Set(p.SizeParam, strconv.Itoa(p.PageSize))
+	}
+	return g.endpoint(params, query)
+}
+
+// pagedEndpoint builds the endpoint of the nth page for offset and page
+// pagination, starting at params.Offset
+func (g *GenericRESTSource) pagedEndpoint(params api.CollectionParams, n int) string {
+	p := g.Config.Pagination
+	query := g.baseQuery(params)
+	if p.SizeParam != "" {
+		query.Set(p.SizeParam, strconv.Itoa(p.PageSize))
+	}
+
+	if p.Type == PaginationOffset {
+		query.Set(p.Param, strconv.Itoa(params.Offset+n*p.PageSize))
+	} else {
+		query.Set(p.Param, strconv.Itoa(p.FirstPage+max(params.Offset, 0)/p.PageSize+n))
+	}
+
+	return g.endpoint(params, query)
+}
+
+// cursorEndpoint builds the endpoint of the page after body, or "" when the
+// response carries no cursor
+func (g *GenericRESTSource) cursorEndpoint(params api.CollectionParams, body interface{}) (string, error) {
+	p := g.Config.Pagination
+	cursor, ok, err := lookupPath(body, p.CursorPath)
+	if err != nil || !ok || cursor == nil || fmt.Sprint(cursor) == "" {
+		return "", err
+	}
+
+	query := g.baseQuery(params)
+	if p.SizeParam != "" {
+		query.Set(p.SizeParam, strconv.Itoa(p.PageSize))
+	}
+	query.Set(p.Param, fmt.Sprint(cursor))
+
+	return g.endpoint(params, query), nil
+}
+
+// linkEndpoint converts the rel="next" URL of a Link header to an endpoint
+// relative to the base URL
+func (g *GenericRESTSource) linkEndpoint(next string) (string, error) {
+	if next == "" {
+		return "", nil
+	}
+
+	base, err := url.Parse(g.APIClient.BaseURL + "/")
+	if err != nil {
+		return "", err
+	}
+	nextURL, err := base.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link %q: %w", next, err)
+	}
+
+	endpoint, ok := strings.CutPrefix(nextURL.String(), g.APIClient.BaseURL)
+	if !ok {
+		return "", fmt.Errorf("next link %q is outside %s", next, g.APIClient.BaseURL)
+	}
+	return endpoint, nil
+}
+
+// baseQuery fills the configured parameters and query authentication
+func (g *GenericRESTSource) baseQuery(params api.CollectionParams) url.Values {
+	query := url.Values{}
+	for name, value := range g.Config.Params {
+		if resolved := expandPlaceholders(value, params, nil); resolved != "" {
+			query.Set(name, resolved)
+		}
+	}
+
+	if g.Config.Auth.Type == AuthQuery {
+		query.Set(g.Config.Auth.Name, os.ExpandEnv(g.Config.Auth.Key))
+	}
+
+	return query
+}
+
+func (g *GenericRESTSource) endpoint(params api.CollectionParams, query url.Values) string {
+	// Placeholders in the query string of the endpoint are escaped as query
+	// values, so that "&" or "=" in a value cannot add parameters
+	path, rawQuery, hasQuery := strings.Cut(g.Config.Endpoint, "?")
+	endpoint := expandPlaceholders(path, params, url.PathEscape)
+	if hasQuery {
+		endpoint += "?" + expandPlaceholders(rawQuery, params, url.QueryEscape)
+	}
+	if encoded := query.Encode(); encoded != "" {
+		separator := "?"
+		if strings.Contains(endpoint, "?") {
+			separator = "&"
+		}
+		endpoint += separator + encoded
+	}
+	return endpoint
+}
+
+// fetch requests an endpoint and returns its decoded body and the rel="next"
+// link of its Link header
+func (g *GenericRESTSource) fetch(ctx context.Context, endpoint string) (interface{}, string, error) {
+	headers := map[string]string{"Accept": "application/json"}
+	for name, value := range g.Config.Headers {
+		headers[name] = os.ExpandEnv(value)
+	}
+
+	switch g.Config.Auth.Type {
+	case AuthHeader:
+		headers[g.Config.Auth.Name] = os.ExpandEnv(g.Config.Auth.Key)
+	case AuthBasic:
+		credentials := os.ExpandEnv(g.Config.Auth.Username) + ":" + os.ExpandEnv(g.Config.Auth.Password)
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+
+	resp, err := g.APIClient.MakeRequest(ctx, "GET", endpoint, headers)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s API request failed: %w", g.Config.Name, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, "", fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return decoded, nextLink(resp.Header.Values("Link")), nil
+}
+
+// items returns the records of a response
+func (g *GenericRESTSource) items(body interface{}) ([]interface{}, error) {
+	found := body
+	if g.Config.RecordsPath != "" {
+		var ok bool
+		var err error
+		found, ok, err = lookupPath(body, g.Config.RecordsPath)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || found == nil {
+			return nil, nil
+		}
+	}
+
+	items, ok := found.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: records at %q are not an array: %w", g.Config.Name, g.Config.RecordsPath, api.ErrInvalidResponse)
+	}
+	return items, nil
+}
+
+func (g *GenericRESTSource) toRawRecord(item interface{}) (api.RawRecord, error) {
+	var id string
+	if g.Config.IDPath != "" {
+		value, ok, err := lookupPath(item, g.Config.IDPath)
+		if err != nil {
+			return api.RawRecord{}, err
+		}
+		if ok && value != nil {
+			id = fmt.Sprint(value)
+		}
+	}
+	if id == "" {
+		encoded, _ := json.Marshal(item)
+		id = stableHash(string(encoded))
+	}
+
+	data := make(map[string]interface{})
+	if len(g.Config.Fields) == 0 {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return api.RawRecord{}, fmt.Errorf("%s: record is not an object: %w", g.Config.Name, api.ErrInvalidResponse)
+		}
+		data = obj
+	}
+	for key, path := range g.Config.Fields {
+		value, ok, err := lookupPath(item, path)
+		if err != nil {
+			return api.RawRecord{}, err
+		}
+		if ok {
+			setPath(data, key, value)
+		}
+	}
+
+	return api.RawRecord{
+		ID:          g.Config.IDPrefix + id,
+		Source:      g.Config.Name,
+		CollectedAt: time.Now(),
+		Data:        normalizeNumbers(data).(map[string]interface{}),
+	}, nil
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([a-z_]+(?:\.[A-Za-z0-9_]+)?)\}`)
+
+// expandPlaceholders replaces {query}, {location}, {limit}, {offset} and
+// {filter.NAME} in template, escaping values with escape when given
+func expandPlaceholders(template string, params api.CollectionParams, escape func(string) string) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := match[1 : len(match)-1]
+
+		var value string
+		switch {
+		case name == "query":
+			value = params.Query
+		case name == "location":
+			value = params.Location
+		case name == "limit":
+			value = strconv.Itoa(params.Limit)
+		case name == "offset":
+			value = strconv.Itoa(params.Offset)
+		case strings.HasPrefix(name, "filter."):
+			if v, ok := params.Filters[strings.TrimPrefix(name, "filter.")]; ok && v != nil {
+				value = fmt.Sprint(v)
+			}
+		default:
+			return match
+		}
+
+		if escape != nil {
+			return escape(value)
+		}
+		return value
+	})
+}
+
+// nextLink returns the target of the rel="next" entry of Link headers
+func nextLink(headers []string) string {
+	for _, header := range headers {
+		for _, link := range strings.Split(header, ",") {
+			target, attrs, ok := strings.Cut(link, ";")
+			if !ok {
+				continue
+			}
+			for _, attr := range strings.Split(attrs, ";") {
+				name, value, _ := strings.Cut(strings.TrimSpace(attr), "=")
+				if name == "rel" && slices.Contains(strings.Fields(strings.Trim(value, `"`)), "next") {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// LoadGenericRESTConfigs reads source descriptions from a YAML file or from
+// every .yml and .yaml file in a directory, one source per file. A missing
+// path yields no sources.
+func LoadGenericRESTConfigs(path string) ([]GenericRESTConfig, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if !entry.IsDir() && (ext == ".yml" || ext == ".yaml") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	// yaml.v3 keeps the case of map keys, which query parameters, headers
+	// and field paths depend on
+	var configs []GenericRESTConfig
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var cfg GenericRESTConfig
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		configs = append(configs, cfg)
+	}
+
+	return configs, nil
+}
@@ -0,0 +1,282 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+func TestGenericRESTSource_OffsetPagination(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/registry/de/companies" {
+			t.Errorf("Unexpected path '%s'", r.URL.Path)
+		}
+		if r.Header.Get("X-Api-Key") != "secret" {
+			t.Errorf("Expected X-Api-Key header 'secret', got '%s'", r.Header.Get("X-Api-Key"))
+		}
+		queries = append(queries, r.URL.RawQuery)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		var items string
+		for i := offset; i < min(offset+2, 3); i++ {
+			if items != "" {
+				items += ","
+			}
+			items += fmt.Sprintf(`{"number": %d, "company": {"name": "Company %d", "address": {"zip": "1011%d"}}, "tags": [{"label": "a"}, {"label": "b"}]}`, 1000+i, i, i)
+		}
+		fmt.Fprintf(w, `{"data": {"items": [%s]}}`, items)
+	}))
+	defer server.Close()
+
+	t.Setenv("GENERIC_TEST_KEY", "secret")
+	source := NewGenericRESTSource(GenericRESTConfig{
+		Name:     "test_registry",
+		BaseURL:  server.URL + "/v1/",
+		Endpoint: "/registry/{location}/companies",
+		Params: map[string]string{
+			"q":      "{query}",
+			"status": "{filter.status}",
+		},
+		Auth:        GenericAuthConfig{Type: AuthHeader, Name: "X-Api-Key", Key: "${GENERIC_TEST_KEY}"},
+		Pagination:  GenericPagination{Type: PaginationOffset, Param: "skip", SizeParam: "take", PageSize: 2},
+		RecordsPath: "$.data.items",
+		IDPath:      "number",
+		IDPrefix:    "test_",
+		Fields: map[string]string{
+			"name":                "company.name",
+			"address.postal_code": "company.address.zip",
+			"tags":                "tags[*].label",
+		},
+	})
+
+	records, err := source.Collect(context.Background(), api.CollectionParams{Query: "acme gmbh", Location: "de", Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedQueries := []string{"q=acme+gmbh&skip=0&take=2", "q=acme+gmbh&skip=2&take=2"}
+	if fmt.Sprint(queries) != fmt.Sprint(expectedQueries) {
+		t.Errorf("Expected queries %v, got %v", expectedQueries, queries)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	record := records[2]
+	if record.ID != "test_1002" || record.Source != "test_registry" {
+		t.Errorf("Unexpected record ID '%s' or source '%s'", record.ID, record.Source)
+	}
+	if record.Data["name"] != "Company 2" {
+		t.Errorf("Expected name 'Company 2', got %v", record.Data["name"])
+	}
+	if address, ok := record.Data["address"].(map[string]interface{}); !ok || address["postal_code"] != "10112" {
+		t.Errorf("Expected nested postal code, got %v", record.Data["address"])
+	}
+	if tags := fmt.Sprint(record.Data["tags"]); tags != "[a b]" {
+		t.Errorf("Expected tags [a b], got %s", tags)
+	}
+}
+
+func TestGenericRESTSource_PagePaginationOffset(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		var items string
+		for i := (page - 1) * 3; i < min(page*3, 7); i++ {
+			if items != "" {
+				items += ","
+			}
+			items += fmt.Sprintf(`{"id": %d}`, i)
+		}
+		fmt.Fprintf(w, `[%s]`, items)
+	}))
+	defer server.Close()
+
+	source := NewGenericRESTSource(GenericRESTConfig{
+		Name:       "page_api",
+		BaseURL:    server.URL,
+		Endpoint:   "/items",
+		Pagination: GenericPagination{Type: PaginationPage, Param: "page", PageSize: 3},
+		IDPath:     "id",
+	})
+
+	// Offset 4 is the second record of page 2
+	records, err := source.Collect(context.Background(), api.CollectionParams{Offset: 4})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var ids []string
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	if fmt.Sprint(ids) != "[4 5 6]" || fmt.Sprint(pages) != "[2 3]" {
+		t.Errorf("Expected records [4 5 6] from pages [2 3], got %v from %v", ids, pages)
+	}
+}
+
+func TestGenericRESTSource_EndpointQueryPlaceholders(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	source := NewGenericRESTSource(GenericRESTConfig{
+		Name:     "query_api",
+		BaseURL:  server.URL,
+		Endpoint: "/search/{location}?orgName={query}",
+	})
+
+	// A value cannot add parameters to the query string
+	if _, err := source.Collect(context.Background(), api.CollectionParams{Query: "A&B=1 +co", Location: "de"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(query) != 1 || query.Get("orgName") != "A&B=1 +co" {
+		t.Errorf("Expected only orgName 'A&B=1 +co', got %v", query)
+	}
+}
+
+func TestGenericRESTSource_CursorPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "analyst" || pass != "pw" {
+			t.Errorf("Expected basic auth analyst:pw, got %s:%s", user, pass)
+		}
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"results": [{"id": "a"}, {"id": "b"}], "next": "abc"}`)
+		case "abc":
+			fmt.Fprint(w, `{"results": [{"id": "c"}], "next": null}`)
+		default:
+			t.Errorf("Unexpected cursor '%s'", r.URL.Query().Get("cursor"))
+		}
+	}))
+	defer server.Close()
+
+	source := NewGenericRESTSource(GenericRESTConfig{
+		Name:        "cursor_api",
+		BaseURL:     server.URL,
+		Endpoint:    "/entities",
+		Auth:        GenericAuthConfig{Type: AuthBasic, Username: "analyst", Password: "pw"},
+		Pagination:  GenericPagination{Type: PaginationCursor, Param: "cursor", CursorPath: "next"},
+		RecordsPath: "results",
+		IDPath:      "id",
+	})
+
+	records, err := source.Collect(context.Background(), api.CollectionParams{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 3 || records[2].ID != "c" {
+		t.Errorf("Expected 3 records ending with 'c', got %+v", records)
+	}
+}
+
+func TestGenericRESTSource_LinkHeaderPagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") != "k" {
+			t.Errorf("Expected token query parameter, got '%s'", r.URL.RawQuery)
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d&token=k>; rel="next", <%s/items?page=3&token=k>; rel="last"`, server.URL, page+1, server.URL))
+		}
+		fmt.Fprintf(w, `[{"n": %d, "ratio": 0.5}]`, page)
+	}))
+	defer server.Close()
+
+	source := NewGenericRESTSource(GenericRESTConfig{
+		Name:       "link_api",
+		BaseURL:    server.URL,
+		Endpoint:   "/items?page=1",
+		Auth:       GenericAuthConfig{Type: AuthQuery, Name: "token", Key: "k"},
+		Pagination: GenericPagination{Type: PaginationLinkHeader},
+		IDPath:     "n",
+	})
+
+	records, err := source.Collect(context.Background(), api.CollectionParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if records[1].ID != "2" || records[1].Data["n"] != int64(2) || records[1].Data["ratio"] != 0.5 {
+		t.Errorf("Unexpected second record %+v", records[1])
+	}
+}
+
+func TestGenericRESTSource_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  GenericRESTConfig
+	}{
+		{"missing base URL", GenericRESTConfig{Name: "x"}},
+		{"missing key", GenericRESTConfig{Name: "x", BaseURL: "http://x", Auth: GenericAuthConfig{Type: AuthBearer}}},
+		{"unknown pagination", GenericRESTConfig{Name: "x", BaseURL: "http://x", Pagination: GenericPagination{Type: "scroll"}}},
+		{"page without param", GenericRESTConfig{Name: "x", BaseURL: "http://x", Pagination: GenericPagination{Type: PaginationPage}}},
+		{"bad field path", GenericRESTConfig{Name: "x", BaseURL: "http://x", Fields: map[string]string{"name": "items[x"}}},
+	}
+
+	for _, tt := range tests {
+		if err := NewGenericRESTSource(tt.cfg).Validate(); err == nil {
+			t.Errorf("%s: expected validation error", tt.name)
+		}
+	}
+
+	if err := NewGenericRESTSource(GenericRESTConfig{Name: "x", BaseURL: "http://x"}).Validate(); err != nil {
+		t.Errorf("Expected minimal config to be valid, got %v", err)
+	}
+}
+
+func TestLoadGenericRESTConfigs(t *testing.T) {
+	t.Setenv("EXAMPLE_API_KEY", "key")
+
+	configs, err := LoadGenericRESTConfigs("../../config/sources")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(configs) != 1 || configs[0].Name != "example_registry" {
+		t.Fatalf("Expected the example registry, got %+v", configs)
+	}
+
+	cfg := configs[0]
+	if cfg.Pagination.SizeParam != "perPage" || cfg.Fields["address.postal_code"] != "address.zip" {
+		t.Errorf("Expected keys to keep their case and dots, got %+v", cfg)
+	}
+	if err := NewGenericRESTSource(cfg).Validate(); err != nil {
+		t.Errorf("Expected example to be valid, got %v", err)
+	}
+
+	if configs, err := LoadGenericRESTConfigs("does-not-exist"); err != nil || configs != nil {
+		t.Errorf("Expected no sources for a missing path, got %v (%v)", configs, err)
+	}
+
+	// Parameter and header names keep their case
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cased.yml")
+	os.WriteFile(file, []byte("name: cased\nbase_url: http://x\nparams:\n  searchTerm: \"{query}\"\nheaders:\n  X-Api-Key: k\n"), 0o644)
+	configs, err = LoadGenericRESTConfigs(file)
+	if err != nil || len(configs) != 1 || configs[0].Params["searchTerm"] != "{query}" || configs[0].Headers["X-Api-Key"] != "k" {
+		t.Errorf("Expected searchTerm and X-Api-Key as written, got %+v (%v)", configs, err)
+	}
+
+	// Misspelled keys are rejected rather than ignored
+	file = filepath.Join(dir, "typo.yml")
+	os.WriteFile(file, []byte("name: typo\nbase_url: http://x\nrecord_path: items\n"), 0o644)
+	if _, err := LoadGenericRESTConfigs(file); err == nil {
+		t.Errorf("Expected an error for an unknown key")
+	}
+}
@@ -0,0 +1,316 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+	"golang.org/x/time/rate"
+)
+
+// GLEIFSource implements data collection from the GLEIF LEI records API
+type GLEIFSource struct {
+	api.BaseDataSource
+	// ResolveParents fetches direct and ultimate parent records for each
+	// entity that reports them, at the cost of up to two extra requests
+	ResolveParents bool
+}
+
+// GLEIFAddress represents an address in a GLEIF LEI record
+type GLEIFAddress struct {
+	AddressLines  []string `json:"addressLines"`
+	AddressNumber string   `json:"addressNumber"`
+	City          string   `json:"city"`
+	Region        string   `json:"region"`
+	Country       string   `json:"country"`
+	PostalCode    string   `json:"postalCode"`
+}
+
+// GLEIFRecord represents one lei-records resource
+type GLEIFRecord struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		LEI    string `json:"lei"`
+		Entity struct {
+			LegalName struct {
+				Name string `json:"name"`
+			} `json:"legalName"`
+			OtherNames []struct {
+				Name string `json:"name"`
+				Type string `json:"type"`
+			} `json:"otherNames"`
+			LegalAddress        GLEIFAddress `json:"legalAddress"`
+			HeadquartersAddress GLEIFAddress `json:"headquartersAddress"`
+			RegisteredAt        struct {
+				ID string `json:"id"`
+			} `json:"registeredAt"`
+			RegisteredAs string `json:"registeredAs"`
+			Jurisdiction string `json:"jurisdiction"`
+			Category     string `json:"category"`
+			LegalForm    struct {
+				ID    string `json:"id"`
+				Other string `json:"other"`
+			} `json:"legalForm"`
+			Status       string `json:"status"`
+			CreationDate string `json:"creationDate"`
+		} `json:"entity"`
+		Registration struct {
+			InitialRegistrationDate string `json:"initialRegistrationDate"`
+			LastUpdateDate          string `json:"lastUpdateDate"`
+			Status                  string `json:"status"`
+			NextRenewalDate         string `json:"nextRenewalDate"`
+		} `json:"registration"`
+	} `json:"attributes"`
+	Relationships map[string]struct {
+		Links map[string]string `json:"links"`
+	} `json:"relationships"`
+}
+
+// GLEIFListResponse represents a page of lei-records
+type GLEIFListResponse struct {
+	Data []GLEIFRecord `json:"data"`
+	Meta struct {
+		Pagination struct {
+			CurrentPage int `json:"currentPage"`
+			PerPage     int `json:"perPage"`
+			Total       int `json:"total"`
+			LastPage    int `json:"lastPage"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+// GLEIFSingleResponse represents a single lei-records resource
+type GLEIFSingleResponse struct {
+	Data GLEIFRecord `json:"data"`
+}
+
+// NewGLEIFSource creates a new GLEIF data source
+func NewGLEIFSource() *GLEIFSource {
+	config := api.ClientConfig{
+		APIName:          "GLEIF",
+		BaseURL:          "https://api.gleif.org/api/v1",
+		RateLimit:        rate.Limit(1), // 60 requests per minute
+		RateBurst:        5,
+		Timeout:          30 * time.Second,
+		MaxRetries:       3,
+		CircuitThreshold: 5,
+	}
+
+	return &GLEIFSource{
+		BaseDataSource: api.BaseDataSource{
+			Name:      "GLEIF",
+			APIClient: api.NewAPIClient(config),
+			RateLimit: config.RateLimit,
+		},
+		ResolveParents: true,
+	}
+}
+
+// Collect retrieves LEI records. Filters["lei"] looks up a single LEI,
+// Filters["registration_number"] looks up an entity by its registry number
+// in the jurisdiction given by Location, and otherwise Query is searched
+// across entity names.
+func (g *GLEIFSource) Collect(ctx context.Context, params api.CollectionParams) ([]api.RawRecord, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	if lei, ok := params.Filters["lei"].(string); ok && lei != "" {
+		record, err := g.LookupLEI(ctx, lei)
+		if err != nil {
+			return nil, err
+		}
+		return []api.RawRecord{record}, nil
+	}
+
+	if number, ok := params.Filters["registration_number"].(string); ok && number != "" {
+		return g.LookupRegistration(ctx, params.Location, number)
+	}
+
+	return g.Search(ctx, params)
+}
+
+// Search finds entities whose names match params.Query, optionally limited
+// to the jurisdiction in params.Location. An offset inside a page starts on
+// that page, skipping the records before it, and continues on the next.
+func (g *GLEIFSource) Search(ctx context.Context, params api.CollectionParams) ([]api.RawRecord, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	size := min(limit, 200)
+	offset := max(params.Offset, 0)
+
+	var records []api.RawRecord
+	for page, skip := offset/size+1, offset%size; len(records) < limit; page, skip = page+1, 0 {
+		queryParams := url.Values{}
+		queryParams.Add("filter[fulltext]", params.Query)
+		queryParams.Add("page[size]", fmt.Sprintf("%d", size))
+		queryParams.Add("page[number]", fmt.Sprintf("%d", page))
+		if params.Location != "" {
+			queryParams.Add("filter[entity.jurisdiction]", model.NormalizeJurisdiction(params.Location))
+		}
+
+		batch, err := g.list(ctx, "/lei-records?"+queryParams.Encode())
+		if err != nil {
+			return records, err
+		}
+		records = append(records, batch[min(skip, len(batch)):]...)
+		if len(batch) < size {
+			break
+		}
+	}
+
+	return truncate(records, limit), nil
+}
+
+// LookupRegistration finds entities registered under number in jurisdiction,
+// e.g. ("GB", "00445790")
+func (g *GLEIFSource) LookupRegistration(ctx context.Context, jurisdiction, number string) ([]api.RawRecord, error) {
+	queryParams := url.Values{}
+	queryParams.Add("filter[entity.registeredAs]", number)
+	if jurisdiction != "" {
+		queryParams.Add("filter[entity.jurisdiction]", model.NormalizeJurisdiction(jurisdiction))
+	}
+
+	return g.list(ctx, "/lei-records?"+queryParams.Encode())
+}
+
+// LookupLEI retrieves the record of a single LEI
+func (g *GLEIFSource) LookupLEI(ctx context.Context, lei string) (api.RawRecord, error) {
+	var apiResp GLEIFSingleResponse
+	if err := g.get(ctx, "/lei-records/"+url.PathEscape(strings.ToUpper(lei)), &apiResp); err != nil {
+		return api.RawRecord{}, err
+	}
+
+	return g.toRawRecord(ctx, apiResp.Data)
+}
+
+func (g *GLEIFSource) list(ctx context.Context, endpoint string) ([]api.RawRecord, error) {
+	var apiResp GLEIFListResponse
+	if err := g.get(ctx, endpoint, &apiResp); err != nil {
+		return nil, err
+	}
+
+	var records []api.RawRecord
+	for _, item := range apiResp.Data {
+		record, err := g.toRawRecord(ctx, item)
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (g *GLEIFSource) get(ctx context.Context, endpoint string, v interface{}) error {
+	resp, err := g.APIClient.MakeRequest(ctx, "GET", endpoint, map[string]string{
+		"Accept": "application/vnd.api+json",
+	})
+	if err != nil {
+		return fmt.Errorf("GLEIF API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return nil
+}
+
+// toRawRecord converts an LEI record, resolving its parents when enabled
+func (g *GLEIFSource) toRawRecord(ctx context.Context, item GLEIFRecord) (api.RawRecord, error) {
+	entity := item.Attributes.Entity
+	lei := item.Attributes.LEI
+	if lei == "" {
+		lei = item.ID
+	}
+
+	otherNames := make([]interface{}, 0, len(entity.OtherNames))
+	for _, name := range entity.OtherNames {
+		otherNames = append(otherNames, name.Name)
+	}
+
+	data := map[string]interface{}{
+		"lei":                       lei,
+		"name":                      entity.LegalName.Name,
+		"other_names":               otherNames,
+		"jurisdiction":              entity.Jurisdiction,
+		"registered_as":             entity.RegisteredAs,
+		"registration_authority_id": entity.RegisteredAt.ID,
+		"legal_form_id":             entity.LegalForm.ID,
+		"legal_form_other":          entity.LegalForm.Other,
+		"entity_category":           entity.Category,
+		"entity_status":             entity.Status,
+		"creation_date":             entity.CreationDate,
+		"registration_status":       item.Attributes.Registration.Status,
+		"initial_registration_date": item.Attributes.Registration.InitialRegistrationDate,
+		"last_update_date":          item.Attributes.Registration.LastUpdateDate,
+		"legal_address":             gleifAddressData(entity.LegalAddress),
+		"headquarters_address":      gleifAddressData(entity.HeadquartersAddress),
+	}
+
+	for _, relation := range []string{"direct-parent", "ultimate-parent"} {
+		key := strings.ReplaceAll(relation, "-", "_")
+		links := item.Relationships[relation].Links
+
+		// Entities without a reported parent link to a reporting exception
+		// instead of a parent record
+		if _, ok := links["reporting-exception"]; ok {
+			data[key+"_exception"] = true
+		}
+
+		if _, ok := links["lei-record"]; !ok || !g.ResolveParents {
+			continue
+		}
+
+		var parent GLEIFSingleResponse
+		if err := g.get(ctx, fmt.Sprintf("/lei-records/%s/%s", url.PathEscape(lei), relation), &parent); err != nil {
+			return api.RawRecord{}, fmt.Errorf("failed to resolve %s of %s: %w", relation, lei, err)
+		}
+		data[key+"_lei"] = parent.Data.Attributes.LEI
+		data[key+"_name"] = parent.Data.Attributes.Entity.LegalName.Name
+	}
+
+	return api.RawRecord{
+		ID:          fmt.Sprintf("gleif_%s", lei),
+		Source:      "gleif",
+		CollectedAt: time.Now(),
+		Data:        data,
+	}, nil
+}
+
+// Validate checks if the data source is properly configured. The GLEIF API
+// is public and needs no key.
+func (g *GLEIFSource) Validate() error {
+	return nil
+}
+
+func gleifAddressData(addr GLEIFAddress) map[string]interface{} {
+	lines := make([]interface{}, len(addr.AddressLines))
+	for i, line := range addr.AddressLines {
+		lines[i] = line
+	}
+
+	return map[string]interface{}{
+		"address_lines":  lines,
+		"address_number": addr.AddressNumber,
+		"city":           addr.City,
+		"region":         addr.Region,
+		"country":        addr.Country,
+		"postal_code":    addr.PostalCode,
+	}
+}
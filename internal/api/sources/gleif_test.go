@@ -0,0 +1,148 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+const gleifTestRecord = `{"type": "lei-records", "id": "213800EXAMPLE0000001",
+	"attributes": {"lei": "213800EXAMPLE0000001",
+		"entity": {"legalName": {"name": "TEST COMPANY LIMITED"},
+			"otherNames": [{"name": "TEST CO", "type": "TRADING_OR_OPERATING_NAME"}],
+			"legalAddress": {"addressLines": ["1 Test Street"], "city": "London", "country": "GB", "postalCode": "EC1A 1BB"},
+			"headquartersAddress": {"addressLines": ["2 Head Street"], "city": "Leeds", "country": "GB", "postalCode": "LS1 1AA"},
+			"registeredAt": {"id": "RA000585"}, "registeredAs": "12345678", "jurisdiction": "GB",
+			"legalForm": {"id": "H0PO"}, "status": "ACTIVE"},
+		"registration": {"status": "ISSUED", "initialRegistrationDate": "2015-03-01T00:00:00Z"}},
+	"relationships": {
+		"direct-parent": {"links": {"lei-record": "https://api.gleif.org/api/v1/lei-records/213800EXAMPLE0000001/direct-parent"}},
+		"ultimate-parent": {"links": {"reporting-exception": "https://api.gleif.org/api/v1/lei-records/213800EXAMPLE0000001/ultimate-parent-reporting-exception"}}}}`
+
+func TestGLEIFSource_LookupRegistration(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+
+		switch r.URL.Path {
+		case "/lei-records":
+			if r.URL.Query().Get("filter[entity.registeredAs]") != "12345678" {
+				t.Errorf("Expected registeredAs filter '12345678', got '%s'", r.URL.Query().Get("filter[entity.registeredAs]"))
+			}
+			if r.URL.Query().Get("filter[entity.jurisdiction]") != "GB" {
+				t.Errorf("Expected jurisdiction filter 'GB', got '%s'", r.URL.Query().Get("filter[entity.jurisdiction]"))
+			}
+			fmt.Fprintf(w, `{"data": [%s], "meta": {"pagination": {"total": 1}}}`, gleifTestRecord)
+		case "/lei-records/213800EXAMPLE0000001/direct-parent":
+			fmt.Fprint(w, `{"data": {"id": "549300PARENT00000001", "attributes": {"lei": "549300PARENT00000001", "entity": {"legalName": {"name": "PARENT HOLDINGS PLC"}}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := NewGLEIFSource()
+	source.APIClient.BaseURL = server.URL
+
+	records, err := source.Collect(context.Background(), api.CollectionParams{
+		Location: "gb",
+		Filters:  map[string]interface{}{"registration_number": "12345678"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	// The ultimate parent is a reporting exception and must not be fetched
+	if len(paths) != 2 {
+		t.Errorf("Expected 2 requests, got %v", paths)
+	}
+
+	record := records[0]
+	if record.ID != "gleif_213800EXAMPLE0000001" || record.Source != "gleif" {
+		t.Errorf("Unexpected record ID '%s' or source '%s'", record.ID, record.Source)
+	}
+	if record.Data["name"] != "TEST COMPANY LIMITED" || record.Data["registered_as"] != "12345678" {
+		t.Errorf("Unexpected entity data %v", record.Data)
+	}
+	if record.Data["direct_parent_lei"] != "549300PARENT00000001" || record.Data["direct_parent_name"] != "PARENT HOLDINGS PLC" {
+		t.Errorf("Expected direct parent 549300PARENT00000001, got %v (%v)", record.Data["direct_parent_lei"], record.Data["direct_parent_name"])
+	}
+	if record.Data["ultimate_parent_exception"] != true {
+		t.Errorf("Expected ultimate parent reporting exception")
+	}
+
+	hq, ok := record.Data["headquarters_address"].(map[string]interface{})
+	if !ok || hq["city"] != "Leeds" || hq["postal_code"] != "LS1 1AA" {
+		t.Errorf("Unexpected headquarters address %v", record.Data["headquarters_address"])
+	}
+}
+
+func TestGLEIFSource_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("filter[fulltext]") != "test company" {
+			t.Errorf("Expected fulltext filter 'test company', got '%s'", query.Get("filter[fulltext]"))
+		}
+		if query.Get("page[size]") != "5" || query.Get("page[number]") != "3" {
+			t.Errorf("Expected page 3 of size 5, got %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"data": [%s]}`, gleifTestRecord)
+	}))
+	defer server.Close()
+
+	source := NewGLEIFSource()
+	source.APIClient.BaseURL = server.URL
+	source.ResolveParents = false
+
+	records, err := source.Collect(context.Background(), api.CollectionParams{Query: "test company", Limit: 5, Offset: 10})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	if _, ok := records[0].Data["direct_parent_lei"]; ok {
+		t.Errorf("Expected parents not to be resolved")
+	}
+}
+
+func TestGLEIFSource_SearchMidPageOffset(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		pages = append(pages, r.URL.Query().Get("page[number]"))
+		var items []string
+		for i := (page - 1) * 5; i < page*5; i++ {
+			items = append(items, fmt.Sprintf(`{"id": "LEI%d", "attributes": {"lei": "LEI%d", "entity": {"legalName": {"name": "COMPANY %d"}}}}`, i, i, i))
+		}
+		fmt.Fprintf(w, `{"data": [%s]}`, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	source := NewGLEIFSource()
+	source.APIClient.BaseURL = server.URL
+	source.ResolveParents = false
+
+	// Offset 7 is the third record of page 2
+	records, err := source.Collect(context.Background(), api.CollectionParams{Query: "company", Limit: 5, Offset: 7})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var leis []string
+	for _, record := range records {
+		leis = append(leis, record.Data["lei"].(string))
+	}
+	if fmt.Sprint(leis) != "[LEI7 LEI8 LEI9 LEI10 LEI11]" || fmt.Sprint(pages) != "[2 3]" {
+		t.Errorf("Expected LEI7 to LEI11 from pages [2 3], got %v from %v", leis, pages)
+	}
+}
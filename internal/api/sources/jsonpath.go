@@ -0,0 +1,142 @@
+package sources
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a JSONPath-style expression: a map key, an
+// array index or the [*] wildcard
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parsePath parses the subset of JSONPath used in source configs: an
+// optional leading "$", dot-separated keys, [n] indices and [*] wildcards,
+// e.g. "$.results.companies[*].company.name"
+func parsePath(path string) ([]pathSegment, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.TrimPrefix(path, ".")
+
+	var segments []pathSegment
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			segments = append(segments, pathSegment{key: key})
+		}
+
+		for rest != "" {
+			inner, after, ok := strings.Cut(rest, "]")
+			if !ok {
+				return nil, fmt.Errorf("unterminated bracket in path %q", path)
+			}
+			if inner == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in path %q", inner, path)
+				}
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			}
+			rest = strings.TrimPrefix(after, "[")
+		}
+	}
+
+	return segments, nil
+}
+
+// lookupPath evaluates a path against decoded JSON. A wildcard yields the
+// list of values found below each element.
+func lookupPath(value interface{}, path string) (interface{}, bool, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result, ok := walkPath(value, segments)
+	return result, ok, nil
+}
+
+func walkPath(value interface{}, segments []pathSegment) (interface{}, bool) {
+	for i, seg := range segments {
+		switch {
+		case seg.wildcard:
+			items, ok := value.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			results := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				if found, ok := walkPath(item, segments[i+1:]); ok {
+					results = append(results, found)
+				}
+			}
+			return results, true
+
+		case seg.isIndex:
+			items, ok := value.([]interface{})
+			if !ok || seg.index < 0 || seg.index >= len(items) {
+				return nil, false
+			}
+			value = items[seg.index]
+
+		default:
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = obj[seg.key]; !ok {
+				return nil, false
+			}
+		}
+	}
+
+	return value, true
+}
+
+// setPath stores value under a dotted key, creating nested maps as needed,
+// so that "address.postal_code" produces {"address": {"postal_code": ...}}
+func setPath(data map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := data[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			data[part] = next
+		}
+		data = next
+	}
+	data[parts[len(parts)-1]] = value
+}
+
+// normalizeNumbers replaces the json.Number values produced by a decoder
+// with UseNumber by int64 where the number is integral and float64 otherwise
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,160 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"golang.org/x/time/rate"
+)
+
+// VIESSource validates EU VAT numbers against the European Commission's
+// VIES REST API and returns the registered name and address of valid ones
+type VIESSource struct {
+	api.BaseDataSource
+}
+
+// VIESResponse represents the check-vat-number response
+type VIESResponse struct {
+	IsValid     bool   `json:"isValid"`
+	RequestDate string `json:"requestDate"`
+	UserError   string `json:"userError"`
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	VATNumber   string `json:"vatNumber"`
+}
+
+// viesUndisclosed is returned in place of names and addresses that a member
+// state does not publish
+const viesUndisclosed = "---"
+
+// NewVIESSource creates a new VIES data source
+func NewVIESSource() *VIESSource {
+	config := api.ClientConfig{
+		APIName:          "VIES",
+		BaseURL:          "https://ec.europa.eu/taxation_customs/vies/rest-api",
+		RateLimit:        rate.Limit(2), // 2 requests per second
+		RateBurst:        2,
+		Timeout:          30 * time.Second,
+		MaxRetries:       3,
+		CircuitThreshold: 5,
+	}
+
+	return &VIESSource{
+		BaseDataSource: api.BaseDataSource{
+			Name:      "VIES",
+			APIClient: api.NewAPIClient(config),
+			RateLimit: config.RateLimit,
+		},
+	}
+}
+
+// Collect validates the VAT number in Filters["vat_number"], or in Query
+// when no filter is given
+func (v *VIESSource) Collect(ctx context.Context, params api.CollectionParams) ([]api.RawRecord, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	vatID := params.Query
+	if filter, ok := params.Filters["vat_number"].(string); ok && filter != "" {
+		vatID = filter
+	}
+
+	record, err := v.CheckVAT(ctx, vatID)
+	if err != nil {
+		return nil, err
+	}
+	return []api.RawRecord{record}, nil
+}
+
+// CheckVAT validates a VAT ID such as "DE 123 456 789". Invalid numbers are
+// returned as records with valid set to false; an error means VIES could
+// not give an answer.
+func (v *VIESSource) CheckVAT(ctx context.Context, vatID string) (api.RawRecord, error) {
+	normalized, err := NormalizeVATID(vatID)
+	if err != nil {
+		return api.RawRecord{}, err
+	}
+	country, number := normalized[:2], normalized[2:]
+
+	endpoint := fmt.Sprintf("/ms/%s/vat/%s", country, url.PathEscape(number))
+	resp, err := v.APIClient.MakeRequest(ctx, "GET", endpoint, map[string]string{
+		"Accept": "application/json",
+	})
+	if err != nil {
+		return api.RawRecord{}, fmt.Errorf("VIES API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return api.RawRecord{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var apiResp VIESResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return api.RawRecord{}, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	// Anything but a definite answer means the member state service could
+	// not be queried and the check should be retried later
+	if apiResp.UserError != "" && apiResp.UserError != "VALID" && apiResp.UserError != "INVALID" {
+		return api.RawRecord{}, fmt.Errorf("VIES check of %s failed with %s: %w", normalized, apiResp.UserError, api.ErrServiceUnavailable)
+	}
+
+	return api.RawRecord{
+		ID:          fmt.Sprintf("vies_%s", normalized),
+		Source:      "vies",
+		CollectedAt: time.Now(),
+		Data: map[string]interface{}{
+			"vat_id":       normalized,
+			"country_code": country,
+			"vat_number":   number,
+			"valid":        apiResp.IsValid,
+			"name":         viesValue(apiResp.Name),
+			"address":      viesValue(apiResp.Address),
+			"request_date": apiResp.RequestDate,
+		},
+	}, nil
+}
+
+// Validate checks if the data source is properly configured. VIES is public
+// and needs no key.
+func (v *VIESSource) Validate() error {
+	return nil
+}
+
+// NormalizeVATID strips separators from a VAT ID and uppercases it, using
+// the VIES prefix EL for Greece, e.g. "gr-123.456.789" becomes "EL123456789"
+func NormalizeVATID(vatID string) (string, error) {
+	normalized := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '-', '/':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(vatID)))
+
+	if len(normalized) < 4 || normalized[0] < 'A' || normalized[0] > 'Z' || normalized[1] < 'A' || normalized[1] > 'Z' {
+		return "", fmt.Errorf("VAT ID %q does not start with a country code", vatID)
+	}
+	if strings.HasPrefix(normalized, "GR") {
+		normalized = "EL" + normalized[2:]
+	}
+
+	return normalized, nil
+}
+
+func viesValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == viesUndisclosed {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,96 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+// newVIESStandIn answers like VIES: DE123456789 is valid, EL094014201 is
+// valid without a published address, FR responds as unavailable and
+// everything else is invalid
+func newVIESStandIn(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/ms/DE/vat/123456789":
+			fmt.Fprint(w, `{"isValid": true, "requestDate": "2024-03-01T10:00:00.000Z", "userError": "VALID",
+				"name": "TEST GMBH", "address": "TESTSTRASSE 1\n10115 BERLIN", "vatNumber": "123456789"}`)
+		case "/ms/EL/vat/094014201":
+			fmt.Fprint(w, `{"isValid": true, "userError": "VALID", "name": "TEST AE", "address": "---", "vatNumber": "094014201"}`)
+		case "/ms/FR/vat/12345678901":
+			fmt.Fprint(w, `{"isValid": false, "userError": "MS_UNAVAILABLE"}`)
+		default:
+			fmt.Fprint(w, `{"isValid": false, "userError": "INVALID", "name": "---", "address": "---"}`)
+		}
+	}))
+}
+
+func TestVIESSource_CheckVAT(t *testing.T) {
+	server := newVIESStandIn(t)
+	defer server.Close()
+
+	source := NewVIESSource()
+	source.APIClient.BaseURL = server.URL
+	ctx := context.Background()
+
+	records, err := source.Collect(ctx, api.CollectionParams{Filters: map[string]interface{}{"vat_number": "de 123.456.789"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	record := records[0]
+	if record.ID != "vies_DE123456789" || record.Data["valid"] != true {
+		t.Errorf("Expected valid record vies_DE123456789, got %s %v", record.ID, record.Data["valid"])
+	}
+	if record.Data["name"] != "TEST GMBH" || record.Data["address"] != "TESTSTRASSE 1\n10115 BERLIN" {
+		t.Errorf("Unexpected name or address in %v", record.Data)
+	}
+
+	record, err = source.CheckVAT(ctx, "GR094014201")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if record.Data["vat_id"] != "EL094014201" || record.Data["address"] != "" {
+		t.Errorf("Expected EL prefix and undisclosed address, got %v", record.Data)
+	}
+
+	record, err = source.CheckVAT(ctx, "NL000000000B01")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if record.Data["valid"] != false {
+		t.Errorf("Expected invalid VAT ID, got %v", record.Data["valid"])
+	}
+
+	if _, err := source.CheckVAT(ctx, "FR12345678901"); !errors.Is(err, api.ErrServiceUnavailable) {
+		t.Errorf("Expected ErrServiceUnavailable, got %v", err)
+	}
+}
+
+func TestNormalizeVATID(t *testing.T) {
+	tests := map[string]string{
+		"DE123456789":     "DE123456789",
+		" de 123-456/789": "DE123456789",
+		"gr094014201":     "EL094014201",
+	}
+	for input, expected := range tests {
+		got, err := NormalizeVATID(input)
+		if err != nil || got != expected {
+			t.Errorf("NormalizeVATID(%q): expected %s, got %s (%v)", input, expected, got, err)
+		}
+	}
+
+	for _, input := range []string{"", "123456789", "D1"} {
+		if _, err := NormalizeVATID(input); err == nil {
+			t.Errorf("NormalizeVATID(%q): expected error", input)
+		}
+	}
+}
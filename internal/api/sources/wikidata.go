@@ -0,0 +1,326 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"golang.org/x/time/rate"
+)
+
+// Wikidata properties used to look companies up by identifier
+const (
+	WikidataLEI             = "P1278"
+	WikidataUKCompanyNumber = "P2622"
+	WikidataCIK             = "P5531"
+)
+
+// WikidataSource looks up company metadata on the Wikidata Query Service
+type WikidataSource struct {
+	api.BaseDataSource
+	// UserAgent identifies the client as the Wikimedia User-Agent policy
+	// requires, e.g. "B2BDataPlatform/1.0 (admin@example.com)"
+	UserAgent string
+}
+
+// SPARQLValue is one bound value in a SPARQL JSON result
+type SPARQLValue struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// SPARQLResponse represents a SPARQL 1.1 JSON result
+type SPARQLResponse struct {
+	Results struct {
+		Bindings []map[string]SPARQLValue `json:"bindings"`
+	} `json:"results"`
+}
+
+// wikidataSelect is the body of every lookup; %s is replaced by the pattern
+// that binds ?item
+const wikidataSelect = `SELECT ?item ?itemLabel ?itemDescription ?inception ?website
+       ?employees ?employeesDate ?industry ?industryLabel ?ticker
+       ?lei ?ukCompanyNumber ?cik WHERE {
+  %s
+  OPTIONAL { ?item wdt:P571 ?inception }
+  OPTIONAL { ?item wdt:P856 ?website }
+  OPTIONAL { ?item p:P1128 ?employeeStatement .
+             ?employeeStatement ps:P1128 ?employees .
+             OPTIONAL { ?employeeStatement pq:P585 ?employeesDate } }
+  OPTIONAL { ?item wdt:P452 ?industry }
+  OPTIONAL { ?item p:P414 ?listing . ?listing pq:P249 ?ticker }
+  OPTIONAL { ?item wdt:P1278 ?lei }
+  OPTIONAL { ?item wdt:P2622 ?ukCompanyNumber }
+  OPTIONAL { ?item wdt:P5531 ?cik }
+  SERVICE wikibase:label { bd:serviceParam wikibase:language "en". }
+}
+LIMIT 500`
+
+// NewWikidataSource creates a new Wikidata data source
+func NewWikidataSource(userAgent string) *WikidataSource {
+	config := api.ClientConfig{
+		APIName:          "Wikidata",
+		BaseURL:          "https://query.wikidata.org/sparql",
+		RateLimit:        rate.Limit(1), // 1 query per second
+		RateBurst:        1,
+		Timeout:          60 * time.Second,
+		MaxRetries:       3,
+		CircuitThreshold: 5,
+	}
+
+	return &WikidataSource{
+		BaseDataSource: api.BaseDataSource{
+			Name:      "Wikidata",
+			APIClient: api.NewAPIClient(config),
+			RateLimit: config.RateLimit,
+		},
+		UserAgent: userAgent,
+	}
+}
+
+// Collect looks companies up by Filters["qid"], Filters["lei"],
+// Filters["uk_company_number"] or Filters["cik"], and otherwise by their
+// English label matching Query
+func (w *WikidataSource) Collect(ctx context.Context, params api.CollectionParams) ([]api.RawRecord, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	if qid, ok := params.Filters["qid"].(string); ok && qid != "" {
+		return w.LookupQID(ctx, qid)
+	}
+
+	lookups := []struct{ filter, property string }{
+		{"lei", WikidataLEI},
+		{"uk_company_number", WikidataUKCompanyNumber},
+		{"cik", WikidataCIK},
+	}
+	for _, lookup := range lookups {
+		if value, ok := params.Filters[lookup.filter].(string); ok && value != "" {
+			return w.LookupByIdentifier(ctx, lookup.property, value)
+		}
+	}
+
+	records, err := w.LookupByName(ctx, params.Query)
+	return truncate(records, params.Limit), err
+}
+
+// LookupQID returns the item with the given QID
+func (w *WikidataSource) LookupQID(ctx context.Context, qid string) ([]api.RawRecord, error) {
+	qid = strings.ToUpper(strings.TrimSpace(qid))
+	if len(qid) < 2 || qid[0] != 'Q' {
+		return nil, fmt.Errorf("invalid Wikidata QID %q", qid)
+	}
+	if _, err := strconv.Atoi(qid[1:]); err != nil {
+		return nil, fmt.Errorf("invalid Wikidata QID %q", qid)
+	}
+
+	return w.query(ctx, fmt.Sprintf("VALUES ?item { wd:%s }", qid))
+}
+
+// LookupByIdentifier returns the items whose property, e.g. WikidataLEI,
+// has the given value
+func (w *WikidataSource) LookupByIdentifier(ctx context.Context, property, value string) ([]api.RawRecord, error) {
+	return w.query(ctx, fmt.Sprintf("?item wdt:%s %s .", property, sparqlString(value)))
+}
+
+// LookupByName returns the items whose English label is exactly name
+func (w *WikidataSource) LookupByName(ctx context.Context, name string) ([]api.RawRecord, error) {
+	return w.query(ctx, fmt.Sprintf("?item rdfs:label %s@en .", sparqlString(strings.TrimSpace(name))))
+}
+
+func (w *WikidataSource) query(ctx context.Context, pattern string) ([]api.RawRecord, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	queryParams := url.Values{}
+	queryParams.Add("query", fmt.Sprintf(wikidataSelect, pattern))
+	queryParams.Add("format", "json")
+
+	resp, err := w.APIClient.MakeRequest(ctx, "GET", "?"+queryParams.Encode(), map[string]string{
+		"Accept":     "application/sparql-results+json",
+		"User-Agent": w.UserAgent,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Wikidata query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var apiResp SPARQLResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	return wikidataRecords(apiResp.Results.Bindings), nil
+}
+
+// Validate checks if the data source is properly configured
+func (w *WikidataSource) Validate() error {
+	if strings.TrimSpace(w.UserAgent) == "" {
+		return api.ErrUserAgentMissing
+	}
+	return nil
+}
+
+// wikidataItem accumulates the result rows of one item; multi-valued
+// properties produce one row per combination of values
+type wikidataItem struct {
+	qid, name, description string
+	inception, website     string
+	employees              int64
+	employeesDate          string
+	hasEmployees           bool
+	industries             map[string]string
+	tickers                map[string]bool
+	lei, ukCompanyNumber   string
+	cik                    string
+}
+
+// wikidataRecords groups result rows by item, keeping the order in which
+// items first appear
+func wikidataRecords(bindings []map[string]SPARQLValue) []api.RawRecord {
+	items := make(map[string]*wikidataItem)
+	var order []string
+
+	for _, row := range bindings {
+		qid := wikidataQID(row["item"].Value)
+		if qid == "" {
+			continue
+		}
+
+		item, ok := items[qid]
+		if !ok {
+			item = &wikidataItem{
+				qid:         qid,
+				name:        row["itemLabel"].Value,
+				description: row["itemDescription"].Value,
+				industries:  make(map[string]string),
+				tickers:     make(map[string]bool),
+			}
+			items[qid] = item
+			order = append(order, qid)
+		}
+
+		setOnce(&item.inception, row["inception"].Value)
+		setOnce(&item.website, row["website"].Value)
+		setOnce(&item.lei, row["lei"].Value)
+		setOnce(&item.ukCompanyNumber, row["ukCompanyNumber"].Value)
+		setOnce(&item.cik, row["cik"].Value)
+
+		if industry := wikidataQID(row["industry"].Value); industry != "" {
+			item.industries[industry] = row["industryLabel"].Value
+		}
+		if ticker := row["ticker"].Value; ticker != "" {
+			item.tickers[ticker] = true
+		}
+
+		// Employee counts are reported over time; keep the most recent one
+		if value, ok := row["employees"]; ok {
+			employees, err := strconv.ParseFloat(value.Value, 64)
+			date := row["employeesDate"].Value
+			if err == nil && (!item.hasEmployees || date > item.employeesDate) {
+				item.employees = int64(employees)
+				item.employeesDate = date
+				item.hasEmployees = true
+			}
+		}
+	}
+
+	records := make([]api.RawRecord, 0, len(order))
+	for _, qid := range order {
+		records = append(records, items[qid].toRawRecord())
+	}
+	return records
+}
+
+func (item *wikidataItem) toRawRecord() api.RawRecord {
+	data := map[string]interface{}{
+		"qid":               item.qid,
+		"name":              item.name,
+		"description":       item.description,
+		"website":           item.website,
+		"lei":               item.lei,
+		"uk_company_number": item.ukCompanyNumber,
+		"cik":               item.cik,
+	}
+
+	// Dates are xsd:dateTime such as "1976-04-01T00:00:00Z"; negative
+	// years and unknown values are left out
+	if len(item.inception) >= 4 && item.inception[0] != '-' {
+		if year, err := strconv.Atoi(item.inception[:4]); err == nil {
+			data["founded_year"] = year
+		}
+	}
+	if item.hasEmployees {
+		data["employee_count"] = item.employees
+	}
+
+	industryQIDs := make([]string, 0, len(item.industries))
+	for qid := range item.industries {
+		industryQIDs = append(industryQIDs, qid)
+	}
+	sort.Strings(industryQIDs)
+
+	industries := make([]interface{}, 0, len(industryQIDs))
+	for _, qid := range industryQIDs {
+		industries = append(industries, map[string]interface{}{
+			"qid":   qid,
+			"label": item.industries[qid],
+		})
+	}
+	data["industries"] = industries
+
+	tickers := make([]string, 0, len(item.tickers))
+	for ticker := range item.tickers {
+		tickers = append(tickers, ticker)
+	}
+	sort.Strings(tickers)
+
+	tickerValues := make([]interface{}, len(tickers))
+	for i, ticker := range tickers {
+		tickerValues[i] = ticker
+	}
+	data["tickers"] = tickerValues
+
+	return api.RawRecord{
+		ID:          fmt.Sprintf("wikidata_%s", item.qid),
+		Source:      "wikidata",
+		CollectedAt: time.Now(),
+		Data:        data,
+	}
+}
+
+// wikidataQID extracts the QID from an entity URI such as
+// "http://www.wikidata.org/entity/Q312"
+func wikidataQID(uri string) string {
+	const prefix = "http://www.wikidata.org/entity/"
+	if !strings.HasPrefix(uri, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(uri, prefix)
+}
+
+// sparqlString quotes s as a SPARQL string literal
+func sparqlString(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(s) + `"`
+}
+
+func setOnce(target *string, value string) {
+	if *target == "" {
+		*target = value
+	}
+}
@@ -0,0 +1,90 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+)
+
+// wikidataTestRows are the rows WDQS returns for Apple: one per combination
+// of employee statement, industry and ticker
+const wikidataTestRows = `{"results": {"bindings": [
+	{"item": {"type": "uri", "value": "http://www.wikidata.org/entity/Q312"},
+	 "itemLabel": {"type": "literal", "value": "Apple Inc."},
+	 "itemDescription": {"type": "literal", "value": "American technology company"},
+	 "inception": {"type": "literal", "value": "1976-04-01T00:00:00Z"},
+	 "website": {"type": "uri", "value": "https://www.apple.com/"},
+	 "employees": {"type": "literal", "value": "154000"},
+	 "employeesDate": {"type": "literal", "value": "2021-01-01T00:00:00Z"},
+	 "industry": {"type": "uri", "value": "http://www.wikidata.org/entity/Q11661"},
+	 "industryLabel": {"type": "literal", "value": "information technology"},
+	 "ticker": {"type": "literal", "value": "AAPL"},
+	 "cik": {"type": "literal", "value": "0000320193"}},
+	{"item": {"type": "uri", "value": "http://www.wikidata.org/entity/Q312"},
+	 "itemLabel": {"type": "literal", "value": "Apple Inc."},
+	 "employees": {"type": "literal", "value": "164000"},
+	 "employeesDate": {"type": "literal", "value": "2023-01-01T00:00:00Z"},
+	 "industry": {"type": "uri", "value": "http://www.wikidata.org/entity/Q1637412"},
+	 "industryLabel": {"type": "literal", "value": "consumer electronics"},
+	 "ticker": {"type": "literal", "value": "AAPL"}}
+]}}`
+
+func TestWikidataSource_LookupByIdentifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "TestAgent/1.0 (test@example.com)" {
+			t.Errorf("Unexpected User-Agent '%s'", r.Header.Get("User-Agent"))
+		}
+		query := r.URL.Query().Get("query")
+		if !strings.Contains(query, `?item wdt:P5531 "0000320193" .`) {
+			t.Errorf("Expected CIK pattern in query, got %s", query)
+		}
+		fmt.Fprint(w, wikidataTestRows)
+	}))
+	defer server.Close()
+
+	source := NewWikidataSource("TestAgent/1.0 (test@example.com)")
+	source.APIClient.BaseURL = server.URL
+
+	records, err := source.Collect(context.Background(), api.CollectionParams{Filters: map[string]interface{}{"cik": "0000320193"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected rows grouped into 1 record, got %d", len(records))
+	}
+
+	data := records[0].Data
+	if records[0].ID != "wikidata_Q312" || data["qid"] != "Q312" {
+		t.Errorf("Unexpected record ID '%s'", records[0].ID)
+	}
+	if data["founded_year"] != 1976 || data["website"] != "https://www.apple.com/" {
+		t.Errorf("Unexpected founded year or website in %v", data)
+	}
+	if data["employee_count"] != int64(164000) {
+		t.Errorf("Expected latest employee count 164000, got %v", data["employee_count"])
+	}
+	if industries := data["industries"].([]interface{}); len(industries) != 2 {
+		t.Errorf("Expected 2 industries, got %v", industries)
+	} else if first := industries[0].(map[string]interface{}); first["qid"] != "Q11661" || first["label"] != "information technology" {
+		t.Errorf("Unexpected first industry %v", first)
+	}
+	if tickers := fmt.Sprint(data["tickers"]); tickers != "[AAPL]" {
+		t.Errorf("Expected tickers [AAPL], got %s", tickers)
+	}
+}
+
+func TestWikidataSource_Validate(t *testing.T) {
+	source := NewWikidataSource("")
+	if _, err := source.LookupByName(context.Background(), "Apple Inc."); err != api.ErrUserAgentMissing {
+		t.Errorf("Expected ErrUserAgentMissing, got %v", err)
+	}
+
+	if quoted := sparqlString(`Say "hi"\n`); quoted != `"Say \"hi\"\\n"` {
+		t.Errorf("Unexpected escaping %s", quoted)
+	}
+}
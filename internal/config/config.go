@@ -1,60 +1,180 @@
 package config
 
 import (
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server      ServerConfig
-	Database    DatabaseConfig
-	DataSources DataSourcesConfig
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	DataSources DataSourcesConfig `mapstructure:"datasources"`
+	Resolution  ResolutionConfig  `mapstructure:"resolution"`
+	Geocoding   GeocodingConfig   `mapstructure:"geocoding"`
+	Email       EmailConfig       `mapstructure:"email"`
 }
 
 type ServerConfig struct {
-	Port int
+	Port int `mapstructure:"port"`
 }
 
 type DatabaseConfig struct {
-	Path string
+	Path string `mapstructure:"path"`
 }
 
 type DataSourcesConfig struct {
-	OpenCorporates OpenCorporatesConfig
-	CompaniesHouse CompaniesHouseConfig
+	OpenCorporates OpenCorporatesConfig `mapstructure:"opencorporates"`
+	CompaniesHouse CompaniesHouseConfig `mapstructure:"companieshouse"`
+	GLEIF          GLEIFConfig          `mapstructure:"gleif"`
+	EDGAR          EDGARConfig          `mapstructure:"edgar"`
+	VIES           VIESConfig           `mapstructure:"vies"`
+	Wikidata       WikidataConfig       `mapstructure:"wikidata"`
+	// GenericPath is a YAML file or directory of YAML files describing
+	// REST APIs collected through sources.GenericRESTSource
+	GenericPath string `mapstructure:"generic_path"`
 }
 
 type OpenCorporatesConfig struct {
-	APIKey    string
-	Enabled   bool
-	RateLimit int
+	APIKey    string `mapstructure:"api_key"`
+	Enabled   bool   `mapstructure:"enabled"`
+	RateLimit int    `mapstructure:"rate_limit"`
 }
 
 type CompaniesHouseConfig struct {
-	APIKey    string
-	Enabled   bool
-	RateLimit int
+	APIKey    string `mapstructure:"api_key"`
+	StreamKey string `mapstructure:"stream_key"`
+	Enabled   bool   `mapstructure:"enabled"`
+	RateLimit int    `mapstructure:"rate_limit"`
+}
+
+type GLEIFConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+type EDGARConfig struct {
+	UserAgent string `mapstructure:"user_agent"`
+	Enabled   bool   `mapstructure:"enabled"`
+}
+
+type VIESConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+type WikidataConfig struct {
+	UserAgent string `mapstructure:"user_agent"`
+	Enabled   bool   `mapstructure:"enabled"`
+}
+
+// GeocodingConfig holds the HTTP geocoder used when postcode centroids
+// cannot place an address; it is only used when NominatimURL is set
+type GeocodingConfig struct {
+	NominatimURL string `mapstructure:"nominatim_url"`
+	UserAgent    string `mapstructure:"user_agent"`
+}
+
+// EmailConfig holds the settings of email verification. HeloName and
+// MailFrom identify the verifier to mail servers during SMTP checks.
+type EmailConfig struct {
+	SMTPCheck bool          `mapstructure:"smtp_check"`
+	HeloName  string        `mapstructure:"helo_name"`
+	MailFrom  string        `mapstructure:"mail_from"`
+	CacheTTL  time.Duration `mapstructure:"cache_ttl"`
 }
 
-func LoadConfig() (config Config, err error) {
-	viper.AddConfigPath("./internal/config")
-	viper.SetConfigName("config")
-	viper.SetConfigType("yml")
+// ResolutionConfig holds the score thresholds of entity resolution: pairs
+// scoring at least MatchThreshold are linked, pairs scoring at least
+// ReviewThreshold are queued for review
+type ResolutionConfig struct {
+	MatchThreshold  float64            `mapstructure:"match_threshold"`
+	ReviewThreshold float64            `mapstructure:"review_threshold"`
+	Survivorship    SurvivorshipConfig `mapstructure:"survivorship"`
+}
+
+// SurvivorshipConfig overrides the rules that choose golden record values:
+// a default rule and rules per column such as "employee_count"
+type SurvivorshipConfig struct {
+	Default SurvivorshipRule            `mapstructure:"default"`
+	Fields  map[string]SurvivorshipRule `mapstructure:"fields"`
+}
+
+// SurvivorshipRule is a strategy ("source_priority", "most_recent" or
+// "most_complete") with the source priority used to break ties
+type SurvivorshipRule struct {
+	Strategy string   `mapstructure:"strategy"`
+	Sources  []string `mapstructure:"sources"`
+}
+
+// envBindings are the environment variables documented in .env.example,
+// by the key they set. They override config.yml, which can also refer to
+// them as "${NAME}".
+var envBindings = map[string]string{
+	"datasources.opencorporates.api_key":    "OPENCORPORATES_API_KEY",
+	"datasources.companieshouse.api_key":    "COMPANIES_HOUSE_API_KEY",
+	"datasources.companieshouse.stream_key": "COMPANIES_HOUSE_STREAM_KEY",
+	"datasources.edgar.user_agent":          "SEC_EDGAR_USER_AGENT",
+	"datasources.wikidata.user_agent":       "WIKIDATA_USER_AGENT",
+	"geocoding.user_agent":                  "NOMINATIM_USER_AGENT",
+}
 
+func LoadConfig() (Config, error) {
+	v := viper.New()
+	v.AddConfigPath("./internal/config")
+	v.SetConfigName("config")
+	v.SetConfigType("yml")
+	return load(v)
+}
+
+// load reads the config file set on v, with defaults and environment
+// variables applied
+func load(v *viper.Viper) (config Config, err error) {
 	// Set defaults
-	viper.SetDefault("server.port", 8080)
-	viper.SetDefault("database.path", "b2b.db")
-	viper.SetDefault("datasources.opencorporates.enabled", false)
-	viper.SetDefault("datasources.opencorporates.ratelimit", 5)
-	viper.SetDefault("datasources.companieshouse.enabled", false)
-	viper.SetDefault("datasources.companieshouse.ratelimit", 10)
+	v.SetDefault("server.port", 8080)
+	v.SetDefault("database.path", "b2b.db")
+	v.SetDefault("datasources.opencorporates.enabled", false)
+	v.SetDefault("datasources.opencorporates.rate_limit", 5)
+	v.SetDefault("datasources.companieshouse.enabled", false)
+	v.SetDefault("datasources.companieshouse.rate_limit", 10)
+	v.SetDefault("datasources.gleif.enabled", false)
+	v.SetDefault("datasources.edgar.enabled", false)
+	v.SetDefault("datasources.vies.enabled", false)
+	v.SetDefault("datasources.vies.cache_ttl", "24h")
+	v.SetDefault("datasources.wikidata.enabled", false)
+	v.SetDefault("datasources.generic_path", "./internal/config/sources")
+	v.SetDefault("resolution.match_threshold", 0.92)
+	v.SetDefault("resolution.review_threshold", 0.80)
+	v.SetDefault("email.smtp_check", true)
+	v.SetDefault("email.cache_ttl", "168h")
 
-	viper.AutomaticEnv()
+	for key, env := range envBindings {
+		if err = v.BindEnv(key, env); err != nil {
+			return
+		}
+	}
+	v.AutomaticEnv()
 
-	err = viper.ReadInConfig()
+	err = v.ReadInConfig()
 	if err != nil {
 		return
 	}
 
-	err = viper.Unmarshal(&config)
+	err = v.Unmarshal(&config, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		expandEnvHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)))
 	return
 }
+
+// expandEnvHook replaces ${NAME} in string values with the environment
+// variable, so that secrets stay out of config.yml
+func expandEnvHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if s, ok := data.(string); ok && from.Kind() == reflect.String {
+		return os.ExpandEnv(s), nil
+	}
+	return data, nil
+}
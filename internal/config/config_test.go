@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// loadYAML loads a config file with the given content
+func loadYAML(t *testing.T, content string) Config {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	cfg, err := load(v)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return cfg
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("COMPANIES_HOUSE_API_KEY", "ch-key")
+	t.Setenv("SEC_EDGAR_USER_AGENT", "Example Ltd admin@example.com")
+	t.Setenv("WIKIDATA_USER_AGENT", "ExampleBot/1.0 (admin@example.com)")
+	t.Setenv("NOMINATIM_USER_AGENT", "ExampleGeo/1.0 (admin@example.com)")
+
+	cfg := loadYAML(t, `
+server:
+  port: 9090
+datasources:
+  opencorporates:
+    api_key: "oc-key"
+    enabled: true
+    rate_limit: 2
+  companieshouse:
+    api_key: "${COMPANIES_HOUSE_API_KEY}"
+    enabled: true
+  edgar:
+    user_agent: "from config.yml"
+    enabled: true
+  vies:
+    cache_ttl: 12h
+  generic_path: "/etc/b2b/sources"
+resolution:
+  match_threshold: 0.95
+  survivorship:
+    fields:
+      employee_count:
+        strategy: most_recent
+        sources: [companies_house, gleif]
+geocoding:
+  nominatim_url: "http://localhost:8088"
+email:
+  smtp_check: false
+  helo_name: "verify.example.com"
+  mail_from: "verify@example.com"
+`)
+
+	if cfg.Server.Port != 9090 || cfg.Database.Path != "b2b.db" {
+		t.Errorf("Expected port 9090 and the default database path, got %+v, %+v", cfg.Server, cfg.Database)
+	}
+	oc := cfg.DataSources.OpenCorporates
+	if oc.APIKey != "oc-key" || !oc.Enabled || oc.RateLimit != 2 {
+		t.Errorf("Expected the OpenCorporates settings, got %+v", oc)
+	}
+	ch := cfg.DataSources.CompaniesHouse
+	if ch.APIKey != "ch-key" || ch.RateLimit != 10 {
+		t.Errorf("Expected the expanded API key and default rate limit, got %+v", ch)
+	}
+	if cfg.DataSources.VIES.CacheTTL != 12*time.Hour {
+		t.Errorf("Expected a VIES cache TTL of 12h, got %v", cfg.DataSources.VIES.CacheTTL)
+	}
+	if cfg.DataSources.GenericPath != "/etc/b2b/sources" {
+		t.Errorf("Expected the generic source path from config.yml, got '%s'", cfg.DataSources.GenericPath)
+	}
+	res := cfg.Resolution
+	if res.MatchThreshold != 0.95 || res.ReviewThreshold != 0.80 {
+		t.Errorf("Expected thresholds 0.95 and the default 0.80, got %v and %v", res.MatchThreshold, res.ReviewThreshold)
+	}
+	if rule := res.Survivorship.Fields["employee_count"]; rule.Strategy != "most_recent" || len(rule.Sources) != 2 {
+		t.Errorf("Expected the employee_count survivorship rule, got %+v", rule)
+	}
+	email := cfg.Email
+	if email.SMTPCheck || email.HeloName != "verify.example.com" || email.MailFrom != "verify@example.com" || email.CacheTTL != 168*time.Hour {
+		t.Errorf("Expected the email settings with the default cache TTL, got %+v", email)
+	}
+	// Environment variables override config.yml
+	if cfg.DataSources.EDGAR.UserAgent != "Example Ltd admin@example.com" {
+		t.Errorf("Expected the EDGAR user agent from the environment, got '%s'", cfg.DataSources.EDGAR.UserAgent)
+	}
+	if cfg.DataSources.Wikidata.UserAgent != "ExampleBot/1.0 (admin@example.com)" {
+		t.Errorf("Expected the Wikidata user agent from the environment, got '%s'", cfg.DataSources.Wikidata.UserAgent)
+	}
+	if cfg.Geocoding.NominatimURL != "http://localhost:8088" || cfg.Geocoding.UserAgent != "ExampleGeo/1.0 (admin@example.com)" {
+		t.Errorf("Expected the Nominatim URL and user agent, got %+v", cfg.Geocoding)
+	}
+}
+
+func TestLoad_ConfigFile(t *testing.T) {
+	t.Setenv("COMPANIES_HOUSE_API_KEY", "ch-key")
+	t.Setenv("COMPANIES_HOUSE_STREAM_KEY", "stream-key")
+
+	v := viper.New()
+	v.SetConfigFile("config.yml")
+	cfg, err := load(v)
+	if err != nil {
+		t.Fatalf("Expected the shipped config.yml to load, got %v", err)
+	}
+	ch := cfg.DataSources.CompaniesHouse
+	if ch.APIKey != "ch-key" || ch.StreamKey != "stream-key" || ch.Enabled {
+		t.Errorf("Expected Companies House disabled with the keys from the environment, got %+v", ch)
+	}
+	if cfg.DataSources.VIES.CacheTTL != 24*time.Hour {
+		t.Errorf("Expected the default VIES cache TTL of 24h, got %v", cfg.DataSources.VIES.CacheTTL)
+	}
+}
@@ -21,3 +21,11 @@ func NewDatabaseConnection(path string) (*sqlx.DB, error) {
 	log.Println("Database connection established")
 	return db, nil
 }
+
+// HasFTS5 reports whether SQLite was compiled with FTS5, which go-sqlite3
+// only includes when built with the sqlite_fts5 tag
+func HasFTS5(db *sqlx.DB) bool {
+	var enabled bool
+	err := db.Get(&enabled, "SELECT sqlite_compileoption_used('ENABLE_FTS5')")
+	return err == nil && enabled
+}
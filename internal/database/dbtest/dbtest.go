@@ -0,0 +1,73 @@
+// Package dbtest provides a migrated SQLite database for package tests
+package dbtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jmoiron/sqlx"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database"
+)
+
+// New creates a database in a temporary directory, applies all migrations
+// and returns a connection that is closed when the test finishes
+func New(t testing.TB) *sqlx.DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	m, err := migrate.New("file://"+migrationsDir(t), "sqlite3://"+path)
+	if err != nil {
+		t.Fatalf("failed to create migrate instance: %v", err)
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	m.Close()
+
+	db, err := database.NewDatabaseConnection(path)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// The search migrations need FTS5, so tests built without the
+	// sqlite_fts5 tag get a database without company_search
+	if database.HasFTS5(db) {
+		m, err := migrate.New("file://"+filepath.Join(migrationsDir(t), "fts5"), "sqlite3://"+path+"?x-migrations-table=schema_migrations_fts5")
+		if err != nil {
+			t.Fatalf("failed to create migrate instance: %v", err)
+		}
+		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+			t.Fatalf("failed to apply search migrations: %v", err)
+		}
+		m.Close()
+	}
+
+	return db
+}
+
+// migrationsDir walks up from the working directory to the module root
+func migrationsDir(t testing.TB) string {
+	t.Helper()
+
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get working directory: %v", err)
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return filepath.Join(dir, "migrations")
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			t.Fatal("module root not found")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,67 @@
+package email
+
+import (
+	"context"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// ContactStats counts the outcome of verifying contact emails
+type ContactStats struct {
+	Checked  int
+	Verified int
+	Risky    int
+	Invalid  int
+}
+
+// ContactStatus maps a result to the verification status of contacts:
+// deliverable addresses are verified, other valid ones risky
+func ContactStatus(result EmailVerificationResult) string {
+	switch {
+	case result.IsDeliverable:
+		return repository.ContactVerified
+	case result.IsValid && result.Checks.Mailbox != MailboxRejected:
+		return repository.ContactRisky
+	default:
+		return repository.ContactInvalid
+	}
+}
+
+// VerifyContacts verifies up to limit unverified contact email addresses and
+// records the outcome on every contact using them
+func (v *Verifier) VerifyContacts(ctx context.Context, limit int) (ContactStats, error) {
+	var stats ContactStats
+
+	contacts := repository.NewContactRepository(v.db)
+	emails, err := contacts.ListUnverifiedEmails(ctx, limit)
+	if err != nil {
+		return stats, err
+	}
+
+	results, err := v.VerifyAll(ctx, emails)
+	for i, result := range results {
+		if result.VerifiedAt.IsZero() {
+			// Not verified because the run stopped
+			continue
+		}
+		stats.Checked++
+
+		status := ContactStatus(result)
+		switch status {
+		case repository.ContactVerified:
+			stats.Verified++
+		case repository.ContactRisky:
+			stats.Risky++
+		default:
+			stats.Invalid++
+		}
+
+		// Contacts keep the address as stored, which may differ from the
+		// normalized one of the result
+		if _, err := contacts.SetVerification(ctx, emails[i], status, result.Score, result.VerifiedAt); err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, err
+}
@@ -0,0 +1,72 @@
+package email
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestVerifier_VerifyContacts(t *testing.T) {
+	v, _ := newTestVerifier(t)
+	ctx := context.Background()
+	contacts := repository.NewContactRepository(v.db)
+
+	for _, c := range []repository.Contact{
+		{ExternalID: "manual_1:john@example.com", Kind: "person", Name: repository.NullString("John"), Email: repository.NullString("john@example.com")},
+		{ExternalID: "manual_1:nobody@example.com", Kind: "person", Name: repository.NullString("Nobody"), Email: repository.NullString("nobody@example.com")},
+		{ExternalID: "manual_1:info@catchall.test", Kind: "role", Email: repository.NullString("info@catchall.test")},
+		{ExternalID: "manual_1:temp@mailinator.com", Kind: "person", Name: repository.NullString("Temp"), Email: repository.NullString("temp@mailinator.com")},
+		{ExternalID: "manual_1:/+15550100", Kind: "role", Phone: repository.NullString("+15550100")},
+	} {
+		c.CompanyID = 1
+		c.DataSource = "manual"
+		if err := contacts.Upsert(ctx, &c); err != nil {
+			t.Fatalf("Failed to save contact: %v", err)
+		}
+	}
+
+	stats, err := v.VerifyContacts(ctx, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Checked != 4 || stats.Verified != 2 || stats.Risky != 1 || stats.Invalid != 1 {
+		t.Errorf("Expected 4 checked, 2 verified, 1 risky and 1 invalid, got %+v", stats)
+	}
+
+	// Saving a contact again keeps its verification while the email is
+	// unchanged
+	john := repository.Contact{ExternalID: "manual_1:john@example.com", CompanyID: 1, Kind: "person", Name: repository.NullString("John Smith"), Email: repository.NullString("john@example.com"), DataSource: "manual"}
+	if err := contacts.Upsert(ctx, &john); err != nil {
+		t.Fatalf("Failed to save contact: %v", err)
+	}
+
+	if _, err := contacts.OptOut(ctx, "Info@catchall.test", "requested"); err != nil {
+		t.Fatalf("Failed to opt out: %v", err)
+	}
+	if err := contacts.Suppress(ctx, "mailinator.com", "disposable"); err != nil {
+		t.Fatalf("Failed to suppress domain: %v", err)
+	}
+	if suppressed, _ := contacts.IsSuppressed(ctx, "anyone@Mailinator.com"); !suppressed {
+		t.Errorf("Expected addresses on a suppressed domain to be suppressed")
+	}
+
+	exportable, err := contacts.ListExportable(ctx, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var exported []string
+	for _, c := range exportable {
+		exported = append(exported, c.ExternalID)
+	}
+	if len(exportable) != 2 || exportable[0].ExternalID != "manual_1:john@example.com" || exportable[1].ExternalID != "manual_1:/+15550100" {
+		t.Fatalf("Expected John and the phone contact to be exportable, got %v", exported)
+	}
+	if exportable[0].VerificationStatus != repository.ContactVerified || exportable[0].Name.String != "John Smith" {
+		t.Errorf("Expected John to stay verified after the update, got %+v", exportable[0])
+	}
+
+	if remaining, _ := contacts.ListUnverifiedEmails(ctx, 10); len(remaining) != 0 {
+		t.Errorf("Expected no unverified emails left, got %v", remaining)
+	}
+}
@@ -0,0 +1,134 @@
+// Package email verifies email addresses: their syntax, the mail servers of
+// their domain, whether those servers accept the mailbox, and whether the
+// address is disposable or belongs to a role rather than a person.
+package email
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+// Mailbox statuses reported by the SMTP check
+const (
+	MailboxAccepted = "accepted"
+	MailboxRejected = "rejected"
+	MailboxUnknown  = "unknown"
+)
+
+// DeliverableScore is the lowest score of an address considered deliverable
+const DeliverableScore = 70
+
+// ErrInvalid is returned for values that are not email addresses
+var ErrInvalid = errors.New("invalid email address")
+
+// EmailVerificationResult is the outcome of verifying an address
+type EmailVerificationResult struct {
+	Email         string      `json:"email"`
+	IsValid       bool        `json:"is_valid"`
+	IsDeliverable bool        `json:"is_deliverable"`
+	Score         float64     `json:"score"` // 0-100
+	Checks        EmailChecks `json:"checks"`
+	VerifiedAt    time.Time   `json:"verified_at"`
+}
+
+// EmailChecks holds the individual checks behind a result
+type EmailChecks struct {
+	SyntaxValid     bool `json:"syntax_valid"`
+	DomainExists    bool `json:"domain_exists"`
+	MXRecordExists  bool `json:"mx_record_exists"`
+	SMTPConnectable bool `json:"smtp_connectable"`
+	// Mailbox is the answer of the mail server to the address, one of the
+	// Mailbox statuses; IsCatchAll is set when it also accepts a made-up one
+	Mailbox       string `json:"mailbox"`
+	IsCatchAll    bool   `json:"is_catch_all"`
+	IsDisposable  bool   `json:"is_disposable"`
+	IsRoleAccount bool   `json:"is_role_account"`
+}
+
+// Address is an email address split into its parts
+type Address struct {
+	// Email is the address with the domain lowercased and in ASCII form,
+	// e.g. "Info@xn--mller-kva.de"
+	Email  string
+	Local  string
+	Domain string
+}
+
+// Parse checks the syntax of an address as RFC 5322 addr-spec, without
+// display names or comments, within the length limits of RFC 5321
+func Parse(raw string) (Address, error) {
+	raw = strings.TrimSpace(raw)
+	at := strings.LastIndex(raw, "@")
+	if at <= 0 || at == len(raw)-1 || len(raw) > 254 {
+		return Address{}, ErrInvalid
+	}
+
+	local := raw[:at]
+	domain, err := idna.Lookup.ToASCII(strings.TrimSuffix(strings.ToLower(raw[at+1:]), "."))
+	if err != nil || !validDomain(domain) || len(local) > 64 {
+		return Address{}, ErrInvalid
+	}
+
+	parsed, err := mail.ParseAddress(local + "@" + domain)
+	if err != nil || parsed.Name != "" || parsed.Address != local+"@"+domain {
+		return Address{}, ErrInvalid
+	}
+
+	return Address{Email: parsed.Address, Local: local, Domain: domain}, nil
+}
+
+// validDomain reports whether an ASCII domain has at least two labels of
+// letters, digits and inner hyphens, and a TLD that is not all digits
+func validDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 || len(domain) > 253 {
+		return false
+	}
+
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return strings.Trim(labels[len(labels)-1], "0123456789") != ""
+}
+
+// Score rates the deliverability of an address from its checks: 20 points
+// for a valid address on an existing domain, 25 for an MX record, 25 for a
+// mailbox the mail server accepts (10 when it cannot tell), 25 for a
+// domain that is not disposable and 5 for a personal address. Mailboxes the
+// server rejects score 10.
+func Score(checks EmailChecks) float64 {
+	if !checks.SyntaxValid || !checks.DomainExists {
+		return 0
+	}
+	if checks.Mailbox == MailboxRejected {
+		return 10
+	}
+
+	score := 20.0
+	if checks.MXRecordExists {
+		score += 25
+	}
+	if checks.Mailbox == MailboxAccepted && !checks.IsCatchAll {
+		score += 25
+	} else {
+		score += 10
+	}
+	if !checks.IsDisposable {
+		score += 25
+	}
+	if !checks.IsRoleAccount {
+		score += 5
+	}
+	return score
+}
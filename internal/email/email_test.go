@@ -0,0 +1,93 @@
+package email
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw   string
+		email string
+		valid bool
+	}{
+		{"john.smith@example.com", "john.smith@example.com", true},
+		{" Info@Example.COM ", "Info@example.com", true},
+		{"anna+sales@müller.de", "anna+sales@xn--mller-kva.de", true},
+		{"john.smith@example.com.", "john.smith@example.com", true},
+		{"john.smith", "", false},
+		{"@example.com", "", false},
+		{"john@", "", false},
+		{"john smith@example.com", "", false},
+		{"john..smith@example.com", "", false},
+		{"John <john@example.com>", "", false},
+		{"john@localhost", "", false},
+		{"john@-example.com", "", false},
+		{"john@example.123", "", false},
+		{"john@exa_mple.com", "", false},
+	}
+
+	for _, tt := range tests {
+		addr, err := Parse(tt.raw)
+		if tt.valid && err != nil {
+			t.Errorf("Expected '%s' to be valid, got %v", tt.raw, err)
+			continue
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("Expected '%s' to be invalid, got %+v", tt.raw, addr)
+			continue
+		}
+		if addr.Email != tt.email {
+			t.Errorf("Expected '%s' to parse as '%s', got '%s'", tt.raw, tt.email, addr.Email)
+		}
+	}
+}
+
+func TestLists(t *testing.T) {
+	if !IsDisposable("mailinator.com") || !IsDisposable("eu.Mailinator.com") {
+		t.Errorf("Expected mailinator.com and its subdomains to be disposable")
+	}
+	if IsDisposable("example.com") || IsDisposable("com") {
+		t.Errorf("Expected example.com not to be disposable")
+	}
+
+	if !IsRoleAccount("Info") || !IsRoleAccount("sales+uk") {
+		t.Errorf("Expected info and sales+uk to be role accounts")
+	}
+	if IsRoleAccount("john.smith") {
+		t.Errorf("Expected john.smith not to be a role account")
+	}
+}
+
+func TestScore(t *testing.T) {
+	valid := EmailChecks{SyntaxValid: true, DomainExists: true, MXRecordExists: true, SMTPConnectable: true, Mailbox: MailboxAccepted}
+
+	catchAll := valid
+	catchAll.IsCatchAll = true
+	unknown := valid
+	unknown.SMTPConnectable, unknown.Mailbox = false, MailboxUnknown
+	role := valid
+	role.IsRoleAccount = true
+	disposable := valid
+	disposable.IsDisposable = true
+	rejected := valid
+	rejected.Mailbox = MailboxRejected
+
+	tests := []struct {
+		name   string
+		checks EmailChecks
+		score  float64
+	}{
+		{"accepted", valid, 100},
+		{"catch-all", catchAll, 85},
+		{"unknown mailbox", unknown, 85},
+		{"role account", role, 95},
+		{"disposable", disposable, 75},
+		{"rejected", rejected, 10},
+		{"no domain", EmailChecks{SyntaxValid: true}, 0},
+		{"invalid", EmailChecks{}, 0},
+	}
+
+	for _, tt := range tests {
+		if score := Score(tt.checks); score != tt.score {
+			t.Errorf("%s: expected score %.0f, got %.0f", tt.name, tt.score, score)
+		}
+	}
+}
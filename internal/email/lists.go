@@ -0,0 +1,125 @@
+package email
+
+import "strings"
+
+// disposableDomains are domains of throwaway mailbox services
+var disposableDomains = map[string]bool{
+	"10minutemail.com":       true,
+	"10minutemail.net":       true,
+	"20minutemail.com":       true,
+	"33mail.com":             true,
+	"anonaddy.me":            true,
+	"burnermail.io":          true,
+	"discard.email":          true,
+	"dispostable.com":        true,
+	"emailondeck.com":        true,
+	"fakeinbox.com":          true,
+	"getairmail.com":         true,
+	"getnada.com":            true,
+	"guerrillamail.biz":      true,
+	"guerrillamail.com":      true,
+	"guerrillamail.de":       true,
+	"guerrillamail.info":     true,
+	"guerrillamail.net":      true,
+	"guerrillamail.org":      true,
+	"guerrillamailblock.com": true,
+	"harakirimail.com":       true,
+	"inboxkitten.com":        true,
+	"incognitomail.org":      true,
+	"jetable.org":            true,
+	"mailcatch.com":          true,
+	"maildrop.cc":            true,
+	"mailinator.com":         true,
+	"mailinator.net":         true,
+	"mailnesia.com":          true,
+	"mintemail.com":          true,
+	"moakt.com":              true,
+	"mohmal.com":             true,
+	"mytemp.email":           true,
+	"nada.email":             true,
+	"sharklasers.com":        true,
+	"spam4.me":               true,
+	"spambox.us":             true,
+	"spamgourmet.com":        true,
+	"temp-mail.io":           true,
+	"temp-mail.org":          true,
+	"tempail.com":            true,
+	"tempmail.com":           true,
+	"tempmail.net":           true,
+	"tempmailo.com":          true,
+	"tempr.email":            true,
+	"throwawaymail.com":      true,
+	"trashmail.com":          true,
+	"trashmail.de":           true,
+	"trashmail.net":          true,
+	"yopmail.com":            true,
+	"yopmail.fr":             true,
+	"yopmail.net":            true,
+}
+
+// roleAccounts are local parts of mailboxes that belong to a function or a
+// team rather than a person
+var roleAccounts = map[string]bool{
+	"abuse":           true,
+	"accounting":      true,
+	"accounts":        true,
+	"admin":           true,
+	"administrator":   true,
+	"billing":         true,
+	"careers":         true,
+	"contact":         true,
+	"customerservice": true,
+	"enquiries":       true,
+	"feedback":        true,
+	"finance":         true,
+	"hello":           true,
+	"help":            true,
+	"hostmaster":      true,
+	"hr":              true,
+	"info":            true,
+	"inquiries":       true,
+	"invoices":        true,
+	"jobs":            true,
+	"legal":           true,
+	"mail":            true,
+	"marketing":       true,
+	"media":           true,
+	"noreply":         true,
+	"no-reply":        true,
+	"office":          true,
+	"orders":          true,
+	"postmaster":      true,
+	"press":           true,
+	"privacy":         true,
+	"recruitment":     true,
+	"reception":       true,
+	"sales":           true,
+	"security":        true,
+	"service":         true,
+	"support":         true,
+	"team":            true,
+	"webmaster":       true,
+}
+
+// IsDisposable reports whether a domain, or a domain it is a subdomain of,
+// belongs to a disposable mailbox service
+func IsDisposable(domain string) bool {
+	domain = strings.ToLower(domain)
+	for {
+		if disposableDomains[domain] {
+			return true
+		}
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok || !strings.Contains(parent, ".") {
+			return false
+		}
+		domain = parent
+	}
+}
+
+// IsRoleAccount reports whether a local part names a role, ignoring case and
+// "+tag" suffixes
+func IsRoleAccount(local string) bool {
+	local, _, _ = strings.Cut(strings.ToLower(local), "+")
+	return roleAccounts[local]
+}
@@ -0,0 +1,389 @@
+package email
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+	"golang.org/x/time/rate"
+)
+
+// DefaultCacheTTL is how long a verification is reused before the address
+// is checked again
+const DefaultCacheTTL = 7 * 24 * time.Hour
+
+// smtpTimeout bounds a whole SMTP session
+const smtpTimeout = 30 * time.Second
+
+// maxMXAttempts is the number of mail servers tried before the mailbox is
+// reported as unknown
+const maxMXAttempts = 2
+
+// Resolver looks up the DNS records used to verify a domain; *net.Resolver
+// implements it
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Dialer opens connections to mail servers; *net.Dialer implements it
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// Verifier verifies email addresses, caching results in the
+// email_verifications table. SMTP sessions with a domain's mail servers are
+// throttled to DomainRate per domain.
+type Verifier struct {
+	db       *sqlx.DB
+	Resolver Resolver
+	Dialer   Dialer
+	// SMTPCheck enables asking the mail servers whether they accept the
+	// mailbox; many networks block outgoing connections to port 25
+	SMTPCheck bool
+	SMTPPort  string
+	// HeloName and MailFrom identify the verifier to mail servers; they
+	// should be a host and an address of a domain it is allowed to send from
+	HeloName    string
+	MailFrom    string
+	CacheTTL    time.Duration
+	DomainRate  rate.Limit
+	Concurrency int
+
+	now    func() time.Time
+	logger *logrus.Logger
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	// saveMu serializes cache writes of concurrent verifications
+	saveMu sync.Mutex
+}
+
+// NewVerifier creates a verifier caching results in db
+func NewVerifier(db *sqlx.DB) *Verifier {
+	return &Verifier{
+		db:          db,
+		Resolver:    net.DefaultResolver,
+		Dialer:      &net.Dialer{Timeout: 10 * time.Second},
+		SMTPCheck:   true,
+		SMTPPort:    "25",
+		HeloName:    "localhost",
+		MailFrom:    "verify@localhost",
+		CacheTTL:    DefaultCacheTTL,
+		DomainRate:  rate.Every(2 * time.Second),
+		Concurrency: 4,
+		now:         time.Now,
+		logger:      logrus.New(),
+		limiters:    make(map[string]*rate.Limiter),
+	}
+}
+
+// Verify verifies an address, reusing an unexpired cached result. Addresses
+// with invalid syntax get a zero result rather than an error; errors are
+// only returned when DNS cannot be queried, and such results are not
+// cached.
+func (v *Verifier) Verify(ctx context.Context, raw string) (EmailVerificationResult, error) {
+	now := v.now()
+	addr, err := Parse(raw)
+	if err != nil {
+		return EmailVerificationResult{Email: strings.TrimSpace(raw), VerifiedAt: now}, nil
+	}
+
+	verifications := repository.NewEmailVerificationRepository(v.db)
+	cached, ok, err := verifications.GetFresh(ctx, addr.Email, now)
+	if err != nil {
+		return EmailVerificationResult{}, err
+	}
+	if ok {
+		return resultFromRow(cached), nil
+	}
+
+	checks := EmailChecks{
+		SyntaxValid:   true,
+		Mailbox:       MailboxUnknown,
+		IsDisposable:  IsDisposable(addr.Domain),
+		IsRoleAccount: IsRoleAccount(addr.Local),
+	}
+
+	hosts, err := v.mailHosts(ctx, addr.Domain, &checks)
+	if err != nil {
+		return EmailVerificationResult{}, err
+	}
+	if len(hosts) > 0 && v.SMTPCheck {
+		if err := v.throttle(ctx, addr.Domain); err != nil {
+			return EmailVerificationResult{}, err
+		}
+		v.probe(ctx, hosts, addr, &checks)
+	}
+
+	result := EmailVerificationResult{
+		Email:      addr.Email,
+		IsValid:    len(hosts) > 0,
+		Checks:     checks,
+		VerifiedAt: now,
+	}
+	if result.IsValid {
+		result.Score = Score(checks)
+	}
+	result.IsDeliverable = result.IsValid && !checks.IsDisposable &&
+		checks.Mailbox != MailboxRejected && result.Score >= DeliverableScore
+
+	v.saveMu.Lock()
+	err = verifications.Save(ctx, rowFromResult(addr.Domain, result, now.Add(v.CacheTTL)))
+	v.saveMu.Unlock()
+	if err != nil {
+		return result, err
+	}
+
+	v.logger.WithFields(logrus.Fields{
+		"email":       result.Email,
+		"score":       result.Score,
+		"deliverable": result.IsDeliverable,
+	}).Debug("Email address verified")
+
+	return result, nil
+}
+
+// VerifyAll verifies addresses with up to Concurrency verifications at a
+// time, returning results in the order of emails. It stops at the first
+// error.
+func (v *Verifier) VerifyAll(ctx context.Context, emails []string) ([]EmailVerificationResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]EmailVerificationResult, len(emails))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	workers := max(v.Concurrency, 1)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := v.Verify(ctx, emails[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = fmt.Errorf("failed to verify %s: %w", emails[i], err)
+						cancel()
+					})
+					continue
+				}
+				results[i] = result
+			}
+		}()
+	}
+
+	for i := range emails {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, ctx.Err()
+}
+
+// mailHosts returns the mail servers of a domain in order of preference,
+// or the domain itself when it has an address but no MX record (RFC 5321
+// section 5.1). It returns none for domains that do not exist and for
+// domains publishing a null MX record (RFC 7505).
+func (v *Verifier) mailHosts(ctx context.Context, domain string, checks *EmailChecks) ([]string, error) {
+	records, err := v.Resolver.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("failed to look up MX records of %s: %w", domain, err)
+	}
+
+	if len(records) > 0 {
+		checks.DomainExists = true
+		if len(records) == 1 && strings.TrimSuffix(records[0].Host, ".") == "" {
+			return nil, nil
+		}
+
+		sort.SliceStable(records, func(i, j int) bool { return records[i].Pref < records[j].Pref })
+		hosts := make([]string, 0, len(records))
+		for _, record := range records {
+			if host := strings.TrimSuffix(record.Host, "."); host != "" {
+				hosts = append(hosts, host)
+			}
+		}
+		checks.MXRecordExists = true
+		return hosts, nil
+	}
+
+	addrs, err := v.Resolver.LookupHost(ctx, domain)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up %s: %w", domain, err)
+	}
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+	checks.DomainExists = true
+	return []string{domain}, nil
+}
+
+// isNotFound reports whether a DNS lookup failed because the name has no
+// records
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// throttle waits until the domain may be contacted again
+func (v *Verifier) throttle(ctx context.Context, domain string) error {
+	v.mu.Lock()
+	limiter, ok := v.limiters[domain]
+	if !ok {
+		limiter = rate.NewLimiter(v.DomainRate, 1)
+		v.limiters[domain] = limiter
+	}
+	v.mu.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// probe asks the first mail servers that answer whether they accept the
+// address, leaving the mailbox unknown when none answers
+func (v *Verifier) probe(ctx context.Context, hosts []string, addr Address, checks *EmailChecks) {
+	for i, host := range hosts {
+		if i == maxMXAttempts {
+			break
+		}
+
+		mailbox, catchAll, err := v.session(ctx, host, addr)
+		if err != nil {
+			v.logger.WithFields(logrus.Fields{
+				"host":  host,
+				"error": err,
+			}).Debug("Mail server not reachable")
+			continue
+		}
+
+		checks.SMTPConnectable = true
+		checks.Mailbox = mailbox
+		checks.IsCatchAll = catchAll
+		return
+	}
+}
+
+// session runs an SMTP dialogue up to RCPT TO without sending a message.
+// It only fails when the server cannot be reached or does not greet;
+// errors later in the dialogue leave the mailbox unknown.
+func (v *Verifier) session(ctx context.Context, host string, addr Address) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	conn, err := v.Dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, v.SMTPPort))
+	if err != nil {
+		return "", false, err
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return "", false, err
+	}
+	defer client.Close()
+
+	if err := client.Hello(v.HeloName); err != nil {
+		return MailboxUnknown, false, nil
+	}
+	if err := client.Mail(v.MailFrom); err != nil {
+		return MailboxUnknown, false, nil
+	}
+
+	mailbox := rcptStatus(client.Rcpt(addr.Email))
+	catchAll := false
+	if mailbox == MailboxAccepted {
+		// A server accepting a made-up address accepts every address
+		probe := strings.ToLower(rand.Text()) + "@" + addr.Domain
+		catchAll = rcptStatus(client.Rcpt(probe)) == MailboxAccepted
+	}
+
+	client.Quit()
+	return mailbox, catchAll, nil
+}
+
+// rcptStatus maps the reply to RCPT TO to a mailbox status. Only replies
+// saying the mailbox does not exist count as rejections; policy blocks and
+// temporary failures such as greylisting leave it unknown.
+func rcptStatus(err error) string {
+	if err == nil {
+		return MailboxAccepted
+	}
+
+	var reply *textproto.Error
+	if errors.As(err, &reply) {
+		switch reply.Code {
+		case 550, 551, 553:
+			return MailboxRejected
+		}
+	}
+	return MailboxUnknown
+}
+
+// resultFromRow converts a cached verification
+func resultFromRow(row repository.EmailVerification) EmailVerificationResult {
+	return EmailVerificationResult{
+		Email:         row.Email,
+		IsValid:       row.IsValid,
+		IsDeliverable: row.IsDeliverable,
+		Score:         row.Score,
+		Checks: EmailChecks{
+			SyntaxValid:     row.SyntaxValid,
+			DomainExists:    row.DomainExists,
+			MXRecordExists:  row.MXRecordExists,
+			SMTPConnectable: row.SMTPConnectable,
+			Mailbox:         row.Mailbox,
+			IsCatchAll:      row.IsCatchAll,
+			IsDisposable:    row.IsDisposable,
+			IsRoleAccount:   row.IsRoleAccount,
+		},
+		VerifiedAt: row.VerifiedAt,
+	}
+}
+
+// rowFromResult converts a result for the cache
+func rowFromResult(domain string, result EmailVerificationResult, expiresAt time.Time) repository.EmailVerification {
+	return repository.EmailVerification{
+		Email:           result.Email,
+		Domain:          domain,
+		IsValid:         result.IsValid,
+		IsDeliverable:   result.IsDeliverable,
+		Score:           result.Score,
+		SyntaxValid:     result.Checks.SyntaxValid,
+		DomainExists:    result.Checks.DomainExists,
+		MXRecordExists:  result.Checks.MXRecordExists,
+		SMTPConnectable: result.Checks.SMTPConnectable,
+		Mailbox:         result.Checks.Mailbox,
+		IsCatchAll:      result.Checks.IsCatchAll,
+		IsDisposable:    result.Checks.IsDisposable,
+		IsRoleAccount:   result.Checks.IsRoleAccount,
+		VerifiedAt:      result.VerifiedAt,
+		ExpiresAt:       expiresAt,
+	}
+}
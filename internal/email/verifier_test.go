@@ -0,0 +1,225 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"golang.org/x/time/rate"
+)
+
+// fakeResolver answers from maps, reporting other names as not found
+type fakeResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+}
+
+func (r fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if name == "timeout.example" {
+		return nil, &net.DNSError{Err: "i/o timeout", Name: name, IsTimeout: true}
+	}
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+// fakeDialer connects every mail server listed in servers to its local
+// stand-in and refuses the others
+type fakeDialer struct {
+	servers map[string]string
+}
+
+func (d fakeDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, _, _ := net.SplitHostPort(address)
+	local, ok := d.servers[host]
+	if !ok {
+		return nil, errors.New("connection refused")
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, local)
+}
+
+// startSMTP runs a mail server accepting the given recipients, or every
+// recipient when catchAll is set, and counts its sessions
+func startSMTP(t *testing.T, mailboxes map[string]bool, catchAll bool) (string, *int32) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var sessions int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&sessions, 1)
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				fmt.Fprint(conn, "220 mx.test ESMTP\r\n")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					command := strings.ToUpper(strings.TrimSpace(line))
+					switch {
+					case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+						fmt.Fprint(conn, "250 mx.test\r\n")
+					case strings.HasPrefix(command, "MAIL FROM"):
+						fmt.Fprint(conn, "250 OK\r\n")
+					case strings.HasPrefix(command, "RCPT TO"):
+						rcpt := strings.ToLower(strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+						if catchAll || mailboxes[rcpt] {
+							fmt.Fprint(conn, "250 OK\r\n")
+						} else {
+							fmt.Fprint(conn, "550 5.1.1 No such user\r\n")
+						}
+					case strings.HasPrefix(command, "QUIT"):
+						fmt.Fprint(conn, "221 Bye\r\n")
+						return
+					default:
+						fmt.Fprint(conn, "502 Not implemented\r\n")
+					}
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), &sessions
+}
+
+func newTestVerifier(t *testing.T) (*Verifier, *int32) {
+	t.Helper()
+
+	server, sessions := startSMTP(t, map[string]bool{"john@example.com": true, "temp@mailinator.com": true}, false)
+	catchAll, _ := startSMTP(t, nil, true)
+
+	v := NewVerifier(dbtest.New(t))
+	v.Resolver = fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com":    {{Host: "mx2.example.com.", Pref: 20}, {Host: "mx1.example.com.", Pref: 10}},
+			"catchall.test":  {{Host: "mx.catchall.test.", Pref: 10}},
+			"mailinator.com": {{Host: "mx2.example.com.", Pref: 10}},
+			"nullmx.test":    {{Host: ".", Pref: 0}},
+		},
+		hosts: map[string][]string{"nomx.test": {"192.0.2.1"}},
+	}
+	// mx1 is down, so sessions go to mx2
+	v.Dialer = fakeDialer{servers: map[string]string{"mx2.example.com": server, "mx.catchall.test": catchAll}}
+	v.DomainRate = rate.Inf
+	return v, sessions
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	v, _ := newTestVerifier(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		email       string
+		valid       bool
+		deliverable bool
+		score       float64
+		checks      EmailChecks
+	}{
+		{"john@example.com", true, true, 100, EmailChecks{SyntaxValid: true, DomainExists: true, MXRecordExists: true, SMTPConnectable: true, Mailbox: MailboxAccepted}},
+		{"nobody@example.com", true, false, 10, EmailChecks{SyntaxValid: true, DomainExists: true, MXRecordExists: true, SMTPConnectable: true, Mailbox: MailboxRejected}},
+		{"info@catchall.test", true, true, 80, EmailChecks{SyntaxValid: true, DomainExists: true, MXRecordExists: true, SMTPConnectable: true, Mailbox: MailboxAccepted, IsCatchAll: true, IsRoleAccount: true}},
+		{"temp@mailinator.com", true, false, 75, EmailChecks{SyntaxValid: true, DomainExists: true, MXRecordExists: true, SMTPConnectable: true, Mailbox: MailboxAccepted, IsDisposable: true}},
+		{"jane@nomx.test", true, false, 60, EmailChecks{SyntaxValid: true, DomainExists: true, Mailbox: MailboxUnknown}},
+		{"jane@nullmx.test", false, false, 0, EmailChecks{SyntaxValid: true, DomainExists: true, Mailbox: MailboxUnknown}},
+		{"jane@missing.test", false, false, 0, EmailChecks{SyntaxValid: true, Mailbox: MailboxUnknown}},
+		{"not an email", false, false, 0, EmailChecks{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result, err := v.Verify(ctx, tt.email)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.IsValid != tt.valid || result.IsDeliverable != tt.deliverable || result.Score != tt.score {
+				t.Errorf("Expected valid=%v deliverable=%v score=%.0f, got %+v", tt.valid, tt.deliverable, tt.score, result)
+			}
+			if result.Checks != tt.checks {
+				t.Errorf("Expected checks %+v, got %+v", tt.checks, result.Checks)
+			}
+		})
+	}
+
+	if _, err := v.Verify(ctx, "jane@timeout.example"); err == nil {
+		t.Errorf("Expected an error when DNS cannot be queried")
+	}
+}
+
+func TestVerifier_Cache(t *testing.T) {
+	v, sessions := newTestVerifier(t)
+	ctx := context.Background()
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	v.now = func() time.Time { return now }
+
+	first, err := v.Verify(ctx, "john@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := v.Verify(ctx, "john@example.com")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if atomic.LoadInt32(sessions) != 1 {
+		t.Errorf("Expected the second verification to be cached, got %d sessions", atomic.LoadInt32(sessions))
+	}
+	if second.Score != first.Score || second.Checks != first.Checks || !second.VerifiedAt.Equal(first.VerifiedAt) {
+		t.Errorf("Expected the cached result %+v, got %+v", first, second)
+	}
+
+	now = now.Add(DefaultCacheTTL + time.Minute)
+	if _, err := v.Verify(ctx, "john@example.com"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if atomic.LoadInt32(sessions) != 2 {
+		t.Errorf("Expected an expired result to be verified again, got %d sessions", atomic.LoadInt32(sessions))
+	}
+}
+
+func TestVerifier_VerifyAll(t *testing.T) {
+	v, _ := newTestVerifier(t)
+
+	emails := []string{"john@example.com", "nobody@example.com", "info@catchall.test", "jane@missing.test"}
+	results, err := v.VerifyAll(context.Background(), emails)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	deliverable := []bool{true, false, true, false}
+	for i, result := range results {
+		if result.Email != emails[i] || result.IsDeliverable != deliverable[i] {
+			t.Errorf("Expected result %d for %s (deliverable=%v), got %+v", i, emails[i], deliverable[i], result)
+		}
+	}
+
+	if _, err := v.VerifyAll(context.Background(), []string{"john@example.com", "jane@timeout.example"}); err == nil {
+		t.Errorf("Expected the DNS error to be returned")
+	}
+}
@@ -0,0 +1,74 @@
+// Package geocode assigns coordinates to addresses. Postcode centroid
+// datasets loaded into SQLite answer most queries offline; an HTTP geocoder
+// can be chained behind them as a fallback.
+package geocode
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Precision levels, from most to least precise
+const (
+	PrecisionAddress          = "address"
+	PrecisionStreet           = "street"
+	PrecisionPostcode         = "postcode"
+	PrecisionPostcodeDistrict = "postcode_district"
+	PrecisionCity             = "city"
+	PrecisionRegion           = "region"
+)
+
+// ErrNotFound is returned by providers that cannot place an address
+var ErrNotFound = errors.New("address not found")
+
+// Query is an address to geocode. Country is an ISO 3166 code.
+type Query struct {
+	Line1      string
+	Line2      string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
+}
+
+// Result is the position of an address and the level it is accurate to
+type Result struct {
+	Latitude  float64
+	Longitude float64
+	Precision string
+	// Source names the dataset or service that placed the address
+	Source string
+}
+
+// Provider geocodes addresses, returning ErrNotFound for addresses it
+// cannot place
+type Provider interface {
+	Geocode(ctx context.Context, query Query) (Result, error)
+}
+
+// Chain tries providers in order and returns the first match
+type Chain []Provider
+
+// Geocode returns the result of the first provider that places the address
+func (c Chain) Geocode(ctx context.Context, query Query) (Result, error) {
+	for _, provider := range c {
+		result, err := provider.Geocode(ctx, query)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		return result, err
+	}
+	return Result{}, ErrNotFound
+}
+
+// NormalizePostcode uppercases a postcode and removes spaces and dashes, as
+// postcodes are stored in postcode_centroids. US ZIP+4 codes are cut to
+// the ZIP code.
+func NormalizePostcode(country, postcode string) string {
+	postcode = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(postcode)))
+	if country == "US" && len(postcode) > 5 {
+		postcode = postcode[:5]
+	}
+	return postcode
+}
@@ -0,0 +1,213 @@
+package geocode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+const geoNamesSample = "US\t94043\tMountain View\tCalifornia\tCA\tSanta Clara\t085\t\t\t37.4056\t-122.0775\t4\n" +
+	"US\t95014\tCupertino\tCalifornia\tCA\tSanta Clara\t085\t\t\t37.318\t-122.0449\t4\n" +
+	"GB\tM1\tManchester\tEngland\tENG\tGreater Manchester\t\t\t\t53.4807\t-2.2344\t4\n" +
+	"GB\tbad\tNowhere\t\t\t\t\t\t\tnorth\twest\t1\n"
+
+const onspdSample = "pcd,pcds,lat,long\n" +
+	"EC1A1BB,EC1A 1BB,51.520180,-0.097440\n" +
+	"EC1A1BE,EC1A 1BE,51.518000,-0.099000\n" +
+	"ZZ991ZZ,ZZ99 1ZZ,99.999999,0.000000\n"
+
+func loadSamples(t *testing.T, db *sqlx.DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	stats, err := Load(ctx, db, strings.NewReader(geoNamesSample), FormatGeoNames)
+	if err != nil {
+		t.Fatalf("Expected no error loading GeoNames, got %v", err)
+	}
+	if stats.Loaded != 3 || stats.Skipped != 1 {
+		t.Errorf("Expected 3 GeoNames centroids loaded and 1 skipped, got %+v", stats)
+	}
+
+	stats, err = Load(ctx, db, strings.NewReader(onspdSample), FormatONSPD)
+	if err != nil {
+		t.Fatalf("Expected no error loading ONSPD, got %v", err)
+	}
+	if stats.Loaded != 2 || stats.Skipped != 1 {
+		t.Errorf("Expected 2 ONSPD centroids loaded and 1 skipped, got %+v", stats)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	db := dbtest.New(t)
+	loadSamples(t, db)
+
+	c, err := repository.NewLocationRepository(db).Centroid(context.Background(), "GB", "EC1A1BB")
+	if err != nil {
+		t.Fatalf("Expected EC1A 1BB to be loaded, got %v", err)
+	}
+	if c.Latitude != 51.52018 || c.Longitude != -0.09744 || c.Source != FormatONSPD {
+		t.Errorf("Expected EC1A 1BB at 51.52018,-0.09744 from onspd, got %+v", c)
+	}
+
+	if _, err := Load(context.Background(), db, strings.NewReader(""), "csv"); err == nil {
+		t.Errorf("Expected an error for an unknown format")
+	}
+}
+
+func TestPostcodeProvider_Geocode(t *testing.T) {
+	db := dbtest.New(t)
+	loadSamples(t, db)
+	provider := NewPostcodeProvider(db)
+
+	tests := []struct {
+		name      string
+		query     Query
+		precision string
+		latitude  float64
+	}{
+		{"postcode", Query{PostalCode: "ec1a 1bb", Country: "GB"}, PrecisionPostcode, 51.52018},
+		{"zip+4", Query{PostalCode: "94043-1351", Country: "US"}, PrecisionPostcode, 37.4056},
+		{"district listed", Query{PostalCode: "M1 1AE", Country: "GB"}, PrecisionPostcodeDistrict, 53.4807},
+		{"district averaged", Query{PostalCode: "EC1A 9ZZ", Country: "GB"}, PrecisionPostcodeDistrict, 51.51909},
+		{"city", Query{City: "cupertino", PostalCode: "95099", Country: "US"}, PrecisionCity, 37.318},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := provider.Geocode(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Precision != tt.precision {
+				t.Errorf("Expected precision %s, got %s", tt.precision, result.Precision)
+			}
+			if fmt.Sprintf("%.5f", result.Latitude) != fmt.Sprintf("%.5f", tt.latitude) {
+				t.Errorf("Expected latitude %.5f, got %.5f", tt.latitude, result.Latitude)
+			}
+		})
+	}
+
+	if _, err := provider.Geocode(context.Background(), Query{PostalCode: "10115", City: "Berlin", Country: "DE"}); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound for an unknown postcode, got %v", err)
+	}
+}
+
+func TestChain_NominatimFallback(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/search" || r.Header.Get("User-Agent") == "" {
+			t.Errorf("Unexpected request %s with User-Agent '%s'", r.URL, r.Header.Get("User-Agent"))
+		}
+		if r.URL.Query().Get("postalcode") == "98052" {
+			fmt.Fprint(w, `[{"lat": "47.6396", "lon": "-122.1283", "addresstype": "building", "place_rank": 30}]`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	db := dbtest.New(t)
+	loadSamples(t, db)
+
+	nominatim := NewNominatimProvider(server.URL, "TestAgent/1.0 (test@example.com)")
+	nominatim.APIClient.RateLimiter.SetLimit(100)
+	chain := Chain{NewPostcodeProvider(db), nominatim}
+
+	result, err := chain.Geocode(context.Background(), Query{Line1: "1 Microsoft Way", City: "Redmond", Region: "WA", PostalCode: "98052", Country: "US"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Latitude != 47.6396 || result.Precision != PrecisionAddress || result.Source != SourceNominatim {
+		t.Errorf("Expected an address-level Nominatim result, got %+v", result)
+	}
+
+	result, err = chain.Geocode(context.Background(), Query{PostalCode: "94043", Country: "US"})
+	if err != nil || result.Source != FormatGeoNames {
+		t.Errorf("Expected 94043 from the postcode centroids, got %+v, %v", result, err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 Nominatim request, got %d", requests)
+	}
+}
+
+func TestNominatimProvider_Geocode(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("city") {
+		case "Nowhere":
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+		case "Blocked":
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		default:
+			fmt.Fprint(w, `[]`)
+		}
+	}))
+	defer server.Close()
+
+	nominatim := NewNominatimProvider(server.URL, "TestAgent/1.0 (test@example.com)")
+	nominatim.APIClient.RateLimiter.SetLimit(100)
+	ctx := context.Background()
+
+	// Only a country or region is not looked up
+	for _, query := range []Query{{Country: "GB"}, {Region: "WA", Country: "US"}, {City: " ", Country: "US"}} {
+		if _, err := nominatim.Geocode(ctx, query); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for %+v, got %v", query, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+
+	// A rejected query is not found, a blocked client is an error
+	for range 6 {
+		if _, err := nominatim.Geocode(ctx, Query{City: "Nowhere", Country: "GB"}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Expected ErrNotFound for a 400 answer, got %v", err)
+		}
+	}
+	if _, err := nominatim.Geocode(ctx, Query{City: "Blocked", Country: "GB"}); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected an error for a 403 answer, got %v", err)
+	}
+}
+
+func TestGeocoder_Run(t *testing.T) {
+	db := dbtest.New(t)
+	loadSamples(t, db)
+	ctx := context.Background()
+
+	// The seed addresses are in Mountain View, Cupertino and Redmond; only
+	// the first two are in the sample
+	stats, err := NewGeocoder(db, NewPostcodeProvider(db)).Run(ctx, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Checked != 3 || stats.Geocoded != 2 || stats.Unmatched != 1 {
+		t.Errorf("Expected 3 checked, 2 geocoded and 1 unmatched, got %+v", stats)
+	}
+
+	var addr repository.Address
+	if err := db.Get(&addr, "SELECT * FROM addresses WHERE postal_code = '94043'"); err != nil {
+		t.Fatalf("Failed to load address: %v", err)
+	}
+	if addr.Latitude.Float64 != 37.4056 || addr.GeocodePrecision.String != PrecisionPostcode || addr.GeocodeSource.String != FormatGeoNames || !addr.GeocodedAt.Valid {
+		t.Errorf("Expected 94043 geocoded to its postcode centroid, got %+v", addr)
+	}
+
+	// Unmatched addresses are not retried
+	stats, err = NewGeocoder(db, NewPostcodeProvider(db)).Run(ctx, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Checked != 0 {
+		t.Errorf("Expected nothing left to geocode, got %+v", stats)
+	}
+}
@@ -0,0 +1,92 @@
+package geocode
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/address"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// Stats counts the outcome of a geocoding run
+type Stats struct {
+	Checked   int
+	Geocoded  int
+	Unmatched int
+}
+
+// Geocoder assigns coordinates to stored addresses that have none
+type Geocoder struct {
+	db       *sqlx.DB
+	provider Provider
+	logger   *logrus.Logger
+}
+
+// NewGeocoder creates a geocoder placing addresses with provider
+func NewGeocoder(db *sqlx.DB, provider Provider) *Geocoder {
+	return &Geocoder{
+		db:       db,
+		provider: provider,
+		logger:   logrus.New(),
+	}
+}
+
+// Run geocodes up to limit addresses. Addresses no provider can place are
+// recorded as attempted and not retried; other provider errors stop the run
+// and leave the address to the next one.
+func (g *Geocoder) Run(ctx context.Context, limit int) (Stats, error) {
+	var stats Stats
+
+	repo := repository.NewLocationRepository(g.db)
+	addresses, err := repo.ListUngeocoded(ctx, limit)
+	if err != nil {
+		return stats, err
+	}
+
+	for _, addr := range addresses {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		stats.Checked++
+
+		country, _ := address.CountryCode(addr.Country.String)
+		result, err := g.provider.Geocode(ctx, Query{
+			Line1:      addr.AddressLine1.String,
+			Line2:      addr.AddressLine2.String,
+			City:       addr.City.String,
+			Region:     addr.State.String,
+			PostalCode: addr.PostalCode.String,
+			Country:    country,
+		})
+		if errors.Is(err, ErrNotFound) {
+			stats.Unmatched++
+			g.logger.WithFields(logrus.Fields{
+				"address_id": addr.ID,
+				"country":    addr.Country.String,
+				"postcode":   addr.PostalCode.String,
+			}).Debug("Address not found")
+			if err := repo.SetLocation(ctx, addr.ID, sql.NullFloat64{}, sql.NullFloat64{}, "", ""); err != nil {
+				return stats, err
+			}
+			continue
+		}
+		if err != nil {
+			return stats, err
+		}
+
+		err = repo.SetLocation(ctx, addr.ID,
+			sql.NullFloat64{Float64: result.Latitude, Valid: true},
+			sql.NullFloat64{Float64: result.Longitude, Valid: true},
+			result.Precision, result.Source,
+		)
+		if err != nil {
+			return stats, err
+		}
+		stats.Geocoded++
+	}
+
+	return stats, nil
+}
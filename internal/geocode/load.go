@@ -0,0 +1,234 @@
+package geocode
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// Centroid dataset formats, also recorded as the source of their centroids
+const (
+	// FormatGeoNames is the tab-separated GeoNames postal code dump, e.g.
+	// allCountries.txt or GB_full.txt
+	FormatGeoNames = "geonames"
+	// FormatONSPD is the CSV of the ONS Postcode Directory
+	FormatONSPD = "onspd"
+)
+
+// SourcePostcodes is the source of results averaged over several centroids
+const SourcePostcodes = "postcode_centroids"
+
+// loadBatchSize is the number of centroids saved per transaction
+const loadBatchSize = 5000
+
+// onspdNoLocation is the latitude ONSPD gives postcodes without a grid
+// reference
+const onspdNoLocation = 99.999999
+
+// LoadStats counts the outcome of loading a dataset
+type LoadStats struct {
+	Loaded  int
+	Skipped int
+}
+
+// LoadFile loads a centroid dataset from a file, or from the data file
+// inside a zip archive as distributed by GeoNames and ONS
+func LoadFile(ctx context.Context, db *sqlx.DB, name, format string) (LoadStats, error) {
+	if strings.EqualFold(path.Ext(name), ".zip") {
+		archive, err := zip.OpenReader(name)
+		if err != nil {
+			return LoadStats{}, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer archive.Close()
+
+		entry, err := dataEntry(archive.File, format)
+		if err != nil {
+			return LoadStats{}, fmt.Errorf("failed to find %s data in %s: %w", format, name, err)
+		}
+		r, err := entry.Open()
+		if err != nil {
+			return LoadStats{}, fmt.Errorf("failed to open %s in %s: %w", entry.Name, name, err)
+		}
+		defer r.Close()
+
+		return Load(ctx, db, r, format)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return LoadStats{}, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+
+	return Load(ctx, db, f, format)
+}
+
+// dataEntry picks the data file of a dataset archive: the .txt file of a
+// GeoNames archive (not its readme) or the ONSPD CSV under Data/
+func dataEntry(files []*zip.File, format string) (*zip.File, error) {
+	for _, f := range files {
+		name := path.Base(f.Name)
+		switch format {
+		case FormatGeoNames:
+			if strings.HasSuffix(name, ".txt") && !strings.EqualFold(name, "readme.txt") {
+				return f, nil
+			}
+		case FormatONSPD:
+			if strings.HasPrefix(strings.ToUpper(name), "ONSPD") && strings.HasSuffix(strings.ToLower(name), ".csv") {
+				return f, nil
+			}
+		}
+	}
+	return nil, errors.New("no data file")
+}
+
+// Load reads a centroid dataset and saves its centroids, replacing known
+// postcodes
+func Load(ctx context.Context, db *sqlx.DB, r io.Reader, format string) (LoadStats, error) {
+	var read func(*csv.Reader) (func() (repository.PostcodeCentroid, bool, error), error)
+	switch format {
+	case FormatGeoNames:
+		read = readGeoNames
+	case FormatONSPD:
+		read = readONSPD
+	default:
+		return LoadStats{}, fmt.Errorf("unknown postcode dataset format %q", format)
+	}
+
+	reader := csv.NewReader(r)
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	next, err := read(reader)
+	if err != nil {
+		return LoadStats{}, err
+	}
+
+	var stats LoadStats
+	batch := make([]repository.PostcodeCentroid, 0, loadBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := repository.Transact(ctx, db, func(tx *sqlx.Tx) error {
+			return repository.NewLocationRepository(tx).SaveCentroids(ctx, batch)
+		})
+		if err != nil {
+			return err
+		}
+		stats.Loaded += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		centroid, ok, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("failed to read %s dataset: %w", format, err)
+		}
+		if !ok {
+			stats.Skipped++
+			continue
+		}
+
+		batch = append(batch, centroid)
+		if len(batch) == loadBatchSize {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	return stats, flush()
+}
+
+// readGeoNames reads the GeoNames layout: country code, postal code, place
+// name, admin names and codes, latitude, longitude and accuracy, with no
+// header
+func readGeoNames(r *csv.Reader) (func() (repository.PostcodeCentroid, bool, error), error) {
+	r.Comma = '\t'
+	return func() (repository.PostcodeCentroid, bool, error) {
+		row, err := r.Read()
+		if err != nil {
+			return repository.PostcodeCentroid{}, false, err
+		}
+		if len(row) < 11 {
+			return repository.PostcodeCentroid{}, false, nil
+		}
+
+		country := strings.ToUpper(strings.TrimSpace(row[0]))
+		centroid, ok := centroid(country, row[1], row[9], row[10], FormatGeoNames)
+		centroid.PlaceName = repository.NullString(strings.TrimSpace(row[2]))
+		centroid.Region = repository.NullString(strings.TrimSpace(row[3]))
+		return centroid, ok, nil
+	}, nil
+}
+
+// readONSPD reads the ONS Postcode Directory CSV by its header names
+func readONSPD(r *csv.Reader) (func() (repository.PostcodeCentroid, bool, error), error) {
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ONSPD header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"pcds", "lat", "long"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("ONSPD header has no %s column", name)
+		}
+	}
+
+	return func() (repository.PostcodeCentroid, bool, error) {
+		row, err := r.Read()
+		if err != nil {
+			return repository.PostcodeCentroid{}, false, err
+		}
+		if len(row) < len(header) {
+			return repository.PostcodeCentroid{}, false, nil
+		}
+
+		centroid, ok := centroid("GB", row[columns["pcds"]], row[columns["lat"]], row[columns["long"]], FormatONSPD)
+		if centroid.Latitude == onspdNoLocation {
+			ok = false
+		}
+		return centroid, ok, nil
+	}, nil
+}
+
+// centroid builds a centroid from text fields, reporting whether they hold
+// a postcode and valid coordinates
+func centroid(country, postcode, lat, lon, source string) (repository.PostcodeCentroid, bool) {
+	c := repository.PostcodeCentroid{
+		Country:  country,
+		Postcode: NormalizePostcode(country, postcode),
+		Source:   source,
+	}
+
+	var latErr, lonErr error
+	c.Latitude, latErr = strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	c.Longitude, lonErr = strconv.ParseFloat(strings.TrimSpace(lon), 64)
+
+	ok := len(c.Country) == 2 && c.Postcode != "" && latErr == nil && lonErr == nil &&
+		c.Latitude >= -90 && c.Latitude <= 90 && c.Longitude >= -180 && c.Longitude <= 180
+	return c, ok
+}
@@ -0,0 +1,148 @@
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"golang.org/x/time/rate"
+)
+
+// SourceNominatim is the source of results from a Nominatim server
+const SourceNominatim = "nominatim"
+
+// DefaultNominatimURL is the public OpenStreetMap Nominatim instance
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+// NominatimProvider geocodes addresses with the structured search of a
+// Nominatim server or any service with a compatible /search endpoint
+type NominatimProvider struct {
+	APIClient *api.APIClient
+	// UserAgent identifies the client as the Nominatim usage policy
+	// requires, e.g. "B2BDataPlatform/1.0 (admin@example.com)"
+	UserAgent string
+}
+
+// nominatimPlace is one result of a jsonv2 search
+type nominatimPlace struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	AddressType string `json:"addresstype"`
+	PlaceRank   int    `json:"place_rank"`
+}
+
+// NewNominatimProvider creates a provider for the server at baseURL. The
+// public instance allows one request per second.
+func NewNominatimProvider(baseURL, userAgent string) *NominatimProvider {
+	if baseURL == "" {
+		baseURL = DefaultNominatimURL
+	}
+
+	return &NominatimProvider{
+		APIClient: api.NewAPIClient(api.ClientConfig{
+			APIName:          "Nominatim",
+			BaseURL:          strings.TrimRight(baseURL, "/"),
+			RateLimit:        rate.Limit(1), // 1 request per second
+			RateBurst:        1,
+			Timeout:          30 * time.Second,
+			MaxRetries:       3,
+			CircuitThreshold: 5,
+		}),
+		UserAgent: userAgent,
+	}
+}
+
+// Geocode places an address with a structured search
+func (n *NominatimProvider) Geocode(ctx context.Context, query Query) (Result, error) {
+	if strings.TrimSpace(n.UserAgent) == "" {
+		return Result{}, api.ErrUserAgentMissing
+	}
+
+	// A country or region alone would be placed at its centre
+	street := strings.TrimSpace(strings.Join([]string{query.Line1, query.Line2}, " "))
+	if street == "" && strings.TrimSpace(query.City) == "" && strings.TrimSpace(query.PostalCode) == "" {
+		return Result{}, ErrNotFound
+	}
+
+	params := url.Values{}
+	params.Set("format", "jsonv2")
+	params.Set("limit", "1")
+	for key, value := range map[string]string{
+		"street":       street,
+		"city":         query.City,
+		"state":        query.Region,
+		"postalcode":   query.PostalCode,
+		"countrycodes": strings.ToLower(query.Country),
+	} {
+		if value = strings.TrimSpace(value); value != "" {
+			params.Set(key, value)
+		}
+	}
+
+	resp, err := n.APIClient.MakeRequest(ctx, "GET", "/search?"+params.Encode(), map[string]string{
+		"Accept":     "application/json",
+		"User-Agent": n.UserAgent,
+	})
+	if api.IsRejected(err) {
+		return Result{}, fmt.Errorf("%w: Nominatim rejected the query: %v", ErrNotFound, err)
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("Nominatim search failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var places []nominatimPlace
+	if err := json.Unmarshal(body, &places); err != nil {
+		return Result{}, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	if len(places) == 0 {
+		return Result{}, ErrNotFound
+	}
+
+	lat, latErr := strconv.ParseFloat(places[0].Lat, 64)
+	lon, lonErr := strconv.ParseFloat(places[0].Lon, 64)
+	if latErr != nil || lonErr != nil {
+		return Result{}, fmt.Errorf("invalid Nominatim coordinates %q, %q", places[0].Lat, places[0].Lon)
+	}
+
+	return Result{Latitude: lat, Longitude: lon, Precision: places[0].precision(), Source: SourceNominatim}, nil
+}
+
+// precision maps the address type of a place, or its rank when the type is
+// not one of the common ones, to a precision level
+func (p nominatimPlace) precision() string {
+	switch p.AddressType {
+	case "house", "building", "amenity", "shop", "office":
+		return PrecisionAddress
+	case "road":
+		return PrecisionStreet
+	case "postcode":
+		return PrecisionPostcode
+	case "city", "town", "village", "hamlet", "suburb", "municipality":
+		return PrecisionCity
+	case "state", "county", "region", "province":
+		return PrecisionRegion
+	}
+
+	switch {
+	case p.PlaceRank >= 28:
+		return PrecisionAddress
+	case p.PlaceRank >= 26:
+		return PrecisionStreet
+	case p.PlaceRank >= 16:
+		return PrecisionCity
+	default:
+		return PrecisionRegion
+	}
+}
@@ -0,0 +1,101 @@
+package geocode
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// districts returns the district part of a normalized postcode in countries
+// whose datasets often only cover districts: the outward code of UK
+// postcodes, the digits of Dutch ones and the forward sortation area of
+// Canadian ones
+var districts = map[string]func(postcode string) string{
+	"GB": func(p string) string { return cut(p, len(p)-3) },
+	"NL": func(p string) string { return cut(p, 4) },
+	"CA": func(p string) string { return cut(p, 3) },
+	"IE": func(p string) string { return cut(p, 3) },
+}
+
+// cut returns the first n characters of s, or "" when s is not longer
+func cut(s string, n int) string {
+	if n <= 0 || n >= len(s) {
+		return ""
+	}
+	return s[:n]
+}
+
+// PostcodeProvider geocodes addresses offline from the postcode_centroids
+// table: by full postcode, then by postcode district, then by city
+type PostcodeProvider struct {
+	repo *repository.LocationRepository
+}
+
+// NewPostcodeProvider creates a provider reading centroids from db
+func NewPostcodeProvider(db repository.DBTX) *PostcodeProvider {
+	return &PostcodeProvider{repo: repository.NewLocationRepository(db)}
+}
+
+// Geocode places an address at the centroid of its postcode, its postcode
+// district or its city
+func (p *PostcodeProvider) Geocode(ctx context.Context, query Query) (Result, error) {
+	if query.Country == "" {
+		return Result{}, ErrNotFound
+	}
+
+	postcode := NormalizePostcode(query.Country, query.PostalCode)
+	if postcode != "" {
+		result, err := p.exact(ctx, query.Country, postcode, PrecisionPostcode)
+		if !errors.Is(err, ErrNotFound) {
+			return result, err
+		}
+
+		if district, ok := districts[query.Country]; ok && district(postcode) != "" {
+			code := district(postcode)
+
+			// Datasets such as GeoNames list districts as postcodes
+			result, err := p.exact(ctx, query.Country, code, PrecisionPostcodeDistrict)
+			if !errors.Is(err, ErrNotFound) {
+				return result, err
+			}
+
+			pattern := code + "*"
+			if query.Country == "GB" {
+				pattern = code + "???"
+			}
+			area, err := p.repo.AreaCentroid(ctx, query.Country, pattern)
+			if err != nil {
+				return Result{}, err
+			}
+			if area.Postcodes > 0 {
+				return Result{Latitude: area.Latitude, Longitude: area.Longitude, Precision: PrecisionPostcodeDistrict, Source: SourcePostcodes}, nil
+			}
+		}
+	}
+
+	if query.City != "" {
+		place, err := p.repo.PlaceCentroid(ctx, query.Country, query.City)
+		if err != nil {
+			return Result{}, err
+		}
+		if place.Postcodes > 0 {
+			return Result{Latitude: place.Latitude, Longitude: place.Longitude, Precision: PrecisionCity, Source: SourcePostcodes}, nil
+		}
+	}
+
+	return Result{}, ErrNotFound
+}
+
+// exact returns the centroid of a postcode
+func (p *PostcodeProvider) exact(ctx context.Context, country, postcode, precision string) (Result, error) {
+	c, err := p.repo.Centroid(ctx, country, postcode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Result{}, ErrNotFound
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Latitude: c.Latitude, Longitude: c.Longitude, Precision: precision, Source: c.Source}, nil
+}
@@ -0,0 +1,172 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// checkpointSource is the key under which bulk import progress is stored
+const checkpointSource = "companies_house_bulk"
+
+// DefaultBatchSize is the number of rows committed per transaction
+const DefaultBatchSize = 1000
+
+// CompaniesHouseImporter loads the Companies House bulk snapshot into the
+// companies and addresses tables. Progress is checkpointed in the same
+// transaction as each batch, so an interrupted run resumes where it stopped
+// and a completed file is skipped on re-run.
+type CompaniesHouseImporter struct {
+	db        *sqlx.DB
+	source    *sources.CompaniesHouseBulkSource
+	BatchSize int
+	logger    *logrus.Logger
+}
+
+// ImportStats summarises an import run
+type ImportStats struct {
+	Parts    int
+	Skipped  int
+	Imported int
+}
+
+// NewCompaniesHouseImporter creates an importer writing to db
+func NewCompaniesHouseImporter(db *sqlx.DB, source *sources.CompaniesHouseBulkSource) *CompaniesHouseImporter {
+	return &CompaniesHouseImporter{
+		db:        db,
+		source:    source,
+		BatchSize: DefaultBatchSize,
+		logger:    logrus.New(),
+	}
+}
+
+// Run imports every part of the snapshot that has not been completed yet
+func (im *CompaniesHouseImporter) Run(ctx context.Context) (ImportStats, error) {
+	var stats ImportStats
+
+	if err := im.source.Validate(); err != nil {
+		return stats, err
+	}
+
+	parts, err := im.source.Parts()
+	if err != nil {
+		return stats, err
+	}
+
+	for _, part := range parts {
+		stats.Parts++
+
+		imported, skipped, err := im.importPart(ctx, part)
+		stats.Imported += imported
+		if skipped {
+			stats.Skipped++
+		}
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+func (im *CompaniesHouseImporter) importPart(ctx context.Context, part sources.BulkPart) (int, bool, error) {
+	file := filepath.Base(part.File)
+
+	cp, err := repository.NewCheckpointRepository(im.db).Get(ctx, checkpointSource, file, part.Entry)
+	if err != nil {
+		return 0, false, err
+	}
+	if cp.Completed {
+		im.logger.WithFields(logrus.Fields{
+			"file":  file,
+			"entry": part.Entry,
+		}).Info("Skipping completed bulk data part")
+		return 0, true, nil
+	}
+
+	logger := im.logger.WithFields(logrus.Fields{
+		"file":       file,
+		"entry":      part.Entry,
+		"resume_row": cp.RowsDone,
+	})
+	logger.Info("Importing bulk data part")
+
+	batchSize := im.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	imported := 0
+	batch := make([]repository.CompanyRecord, 0, batchSize)
+
+	flush := func(rowsDone int, completed bool) error {
+		err := repository.Transact(ctx, im.db, func(tx *sqlx.Tx) error {
+			if err := repository.NewCompanyRepository(tx).SaveBatch(ctx, batch); err != nil {
+				return err
+			}
+			cp.RowsDone = rowsDone
+			cp.Completed = completed
+			return repository.NewCheckpointRepository(tx).Save(ctx, cp)
+		})
+		if err != nil {
+			return err
+		}
+
+		imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	rowsDone := cp.RowsDone
+	err = im.source.ReadPart(ctx, part, cp.RowsDone, func(row int, record api.RawRecord) error {
+		rowsDone = row + 1
+
+		companyRecord, err := companyRecord(record)
+		if err != nil {
+			logger.WithFields(logrus.Fields{"row": row, "error": err}).Debug("Skipping unmappable row")
+		} else {
+			batch = append(batch, companyRecord)
+		}
+
+		if len(batch) >= batchSize {
+			return flush(rowsDone, false)
+		}
+		return nil
+	})
+	if err != nil {
+		return imported, false, fmt.Errorf("import of %s/%s stopped at row %d: %w", file, part.Entry, cp.RowsDone, err)
+	}
+
+	if err := flush(rowsDone, true); err != nil {
+		return imported, false, err
+	}
+
+	logger.WithField("records", imported).Info("Bulk data part imported")
+	return imported, false, nil
+}
+
+// str returns the string stored under key, or "" when absent
+func str(m map[string]interface{}, key string) string {
+	v, _ := m[key].(string)
+	return v
+}
+
+// num returns the number stored under key, accepting both the int values set
+// by sources and the float64 values produced by decoding stored JSON
+func num(m map[string]interface{}, key string) int64 {
+	switch v := m[key].(type) {
+	case int:
+		return int64(v)
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
@@ -0,0 +1,212 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// DefaultFilingLimit is the number of most recent filings collected per company
+const DefaultFilingLimit = 100
+
+// CompaniesHouseDetailsImporter collects the officers, persons with
+// significant control and filing history of a company and stores them
+// linked to its companies row
+type CompaniesHouseDetailsImporter struct {
+	db          *sqlx.DB
+	source      *sources.CompaniesHouseSource
+	FilingLimit int
+	logger      *logrus.Logger
+}
+
+// DetailStats counts the records stored for one company
+type DetailStats struct {
+	Officers int
+	PSCs     int
+	Filings  int
+}
+
+// NewCompaniesHouseDetailsImporter creates a details importer writing to db
+func NewCompaniesHouseDetailsImporter(db *sqlx.DB, source *sources.CompaniesHouseSource) *CompaniesHouseDetailsImporter {
+	return &CompaniesHouseDetailsImporter{
+		db:          db,
+		source:      source,
+		FilingLimit: DefaultFilingLimit,
+		logger:      logrus.New(),
+	}
+}
+
+// Import collects and stores the details of one company. The company profile
+// is fetched first when the company is not stored yet. Records keep their
+// Companies House identifiers, so re-importing updates rows in place.
+func (im *CompaniesHouseDetailsImporter) Import(ctx context.Context, companyNumber string) (DetailStats, error) {
+	var stats DetailStats
+
+	companyID, err := im.ensureCompany(ctx, companyNumber)
+	if err != nil {
+		return stats, err
+	}
+
+	officers, err := emptyIfNotFound(im.source.CollectOfficers(ctx, companyNumber, 0))
+	if err != nil {
+		return stats, fmt.Errorf("failed to collect officers of %s: %w", companyNumber, err)
+	}
+
+	pscs, err := emptyIfNotFound(im.source.CollectPersonsWithSignificantControl(ctx, companyNumber, 0))
+	if err != nil {
+		return stats, fmt.Errorf("failed to collect PSCs of %s: %w", companyNumber, err)
+	}
+
+	filings, err := emptyIfNotFound(im.source.CollectFilingHistory(ctx, companyNumber, im.FilingLimit))
+	if err != nil {
+		return stats, fmt.Errorf("failed to collect filing history of %s: %w", companyNumber, err)
+	}
+
+	err = repository.Transact(ctx, im.db, func(tx *sqlx.Tx) error {
+		people := repository.NewPersonRepository(tx)
+		for _, record := range officers {
+			if err := people.Upsert(ctx, officerFromRecord(companyID, record)); err != nil {
+				return err
+			}
+		}
+		for _, record := range pscs {
+			if err := people.Upsert(ctx, pscFromRecord(companyID, record)); err != nil {
+				return err
+			}
+		}
+
+		filingRepo := repository.NewFilingRepository(tx)
+		for _, record := range filings {
+			if err := filingRepo.Upsert(ctx, filingFromRecord(companyID, record)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	stats = DetailStats{Officers: len(officers), PSCs: len(pscs), Filings: len(filings)}
+	im.logger.WithFields(logrus.Fields{
+		"company_number": companyNumber,
+		"officers":       stats.Officers,
+		"pscs":           stats.PSCs,
+		"filings":        stats.Filings,
+	}).Info("Company details imported")
+
+	return stats, nil
+}
+
+// emptyIfNotFound treats a 404, which Companies House answers for a
+// company without officers, PSCs or filings, as an empty list
+func emptyIfNotFound(records []api.RawRecord, err error) ([]api.RawRecord, error) {
+	if api.IsNotFound(err) {
+		return nil, nil
+	}
+	return records, err
+}
+
+// ensureCompany returns the row ID of the company, collecting its profile
+// when it has not been stored yet
+func (im *CompaniesHouseDetailsImporter) ensureCompany(ctx context.Context, companyNumber string) (int64, error) {
+	companies := repository.NewCompanyRepository(im.db)
+
+	id, err := companies.IDByExternalID(ctx, "ch_"+companyNumber)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	profile, err := im.source.CollectCompany(ctx, companyNumber)
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect profile of %s: %w", companyNumber, err)
+	}
+
+	record, err := companyRecord(profile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to map profile of %s: %w", companyNumber, err)
+	}
+
+	err = repository.Transact(ctx, im.db, func(tx *sqlx.Tx) error {
+		return repository.NewCompanyRepository(tx).SaveBatch(ctx, []repository.CompanyRecord{record})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return record.Company.ID, nil
+}
+
+func officerFromRecord(companyID int64, record api.RawRecord) *repository.Person {
+	person := &repository.Person{
+		ExternalID:         record.ID,
+		CompanyID:          companyID,
+		Kind:               repository.PersonKindOfficer,
+		Name:               str(record.Data, "name"),
+		Role:               repository.NullString(str(record.Data, "officer_role")),
+		Nationality:        repository.NullString(str(record.Data, "nationality")),
+		CountryOfResidence: repository.NullString(str(record.Data, "country_of_residence")),
+		Occupation:         repository.NullString(str(record.Data, "occupation")),
+		StartedOn:          repository.NullString(str(record.Data, "appointed_on")),
+		EndedOn:            repository.NullString(str(record.Data, "resigned_on")),
+		DataSource:         record.Source,
+	}
+	setDateOfBirth(person, record.Data)
+	return person
+}
+
+func pscFromRecord(companyID int64, record api.RawRecord) *repository.Person {
+	person := &repository.Person{
+		ExternalID:         record.ID,
+		CompanyID:          companyID,
+		Kind:               repository.PersonKindPSC,
+		Name:               str(record.Data, "name"),
+		Role:               repository.NullString(str(record.Data, "kind")),
+		Nationality:        repository.NullString(str(record.Data, "nationality")),
+		CountryOfResidence: repository.NullString(str(record.Data, "country_of_residence")),
+		StartedOn:          repository.NullString(str(record.Data, "notified_on")),
+		EndedOn:            repository.NullString(str(record.Data, "ceased_on")),
+		DataSource:         record.Source,
+	}
+	setDateOfBirth(person, record.Data)
+
+	if natures, ok := record.Data["natures_of_control"].([]interface{}); ok && len(natures) > 0 {
+		if encoded, err := json.Marshal(natures); err == nil {
+			person.NaturesOfControl = repository.NullString(string(encoded))
+		}
+	}
+	return person
+}
+
+func filingFromRecord(companyID int64, record api.RawRecord) *repository.Filing {
+	return &repository.Filing{
+		ExternalID:    record.ID,
+		CompanyID:     companyID,
+		TransactionID: repository.NullString(str(record.Data, "transaction_id")),
+		Category:      repository.NullString(str(record.Data, "category")),
+		Type:          repository.NullString(str(record.Data, "type")),
+		Description:   repository.NullString(str(record.Data, "description")),
+		FilingDate:    repository.NullString(str(record.Data, "date")),
+		ActionDate:    repository.NullString(str(record.Data, "action_date")),
+		Pages:         repository.NullInt64(num(record.Data, "pages")),
+		DataSource:    record.Source,
+	}
+}
+
+func setDateOfBirth(person *repository.Person, data map[string]interface{}) {
+	if dob, ok := data["date_of_birth"].(map[string]interface{}); ok {
+		person.BirthMonth = repository.NullInt64(num(dob, "month"))
+		person.BirthYear = repository.NullInt64(num(dob, "year"))
+	}
+}
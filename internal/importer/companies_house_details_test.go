@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+)
+
+func TestCompaniesHouseDetailsImporter_Import(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/company/12345678":
+			fmt.Fprint(w, `{"company_number": "12345678", "company_name": "TEST COMPANY LTD", "type": "ltd",
+				"company_status": "active", "date_of_creation": "2015-03-01",
+				"registered_office_address": {"premises": "1", "address_line_1": "Test Street", "locality": "London", "postal_code": "EC1A 1BB"}}`)
+		case "/company/12345678/officers":
+			fmt.Fprint(w, `{"total_results": 1, "items": [
+				{"name": "SMITH, John", "officer_role": "director", "appointed_on": "2015-03-01",
+				 "date_of_birth": {"month": 4, "year": 1970},
+				 "links": {"self": "/company/12345678/appointments/appt1"}}]}`)
+		case "/company/12345678/persons-with-significant-control":
+			fmt.Fprint(w, `{"total_results": 1, "items": [
+				{"name": "Mr John Smith", "kind": "individual-person-with-significant-control",
+				 "natures_of_control": ["voting-rights-75-to-100-percent"], "notified_on": "2016-04-06",
+				 "links": {"self": "/company/12345678/persons-with-significant-control/individual/psc1"}}]}`)
+		case "/company/12345678/filing-history":
+			fmt.Fprint(w, `{"total_count": 2, "items": [
+				{"transaction_id": "tx2", "category": "accounts", "type": "AA", "date": "2023-09-30", "pages": 10},
+				{"transaction_id": "tx1", "category": "incorporation", "type": "NEWINC", "date": "2015-03-01"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	source := sources.NewCompaniesHouseSource("test-api-key")
+	source.APIClient.BaseURL = server.URL
+	imp := NewCompaniesHouseDetailsImporter(db, source)
+
+	for run := 1; run <= 2; run++ {
+		stats, err := imp.Import(ctx, "12345678")
+		if err != nil {
+			t.Fatalf("Run %d: expected no error, got %v", run, err)
+		}
+		if stats.Officers != 1 || stats.PSCs != 1 || stats.Filings != 2 {
+			t.Errorf("Run %d: unexpected stats %+v", run, stats)
+		}
+	}
+
+	var companyID int64
+	if err := db.Get(&companyID, "SELECT id FROM companies WHERE external_id = 'ch_12345678'"); err != nil {
+		t.Fatalf("Expected company profile to be stored, got %v", err)
+	}
+
+	var people, filings int
+	db.Get(&people, "SELECT COUNT(*) FROM people WHERE company_id = ?", companyID)
+	db.Get(&filings, "SELECT COUNT(*) FROM filings WHERE company_id = ?", companyID)
+	if people != 2 || filings != 2 {
+		t.Errorf("Expected 2 people and 2 filings after two runs, got %d and %d", people, filings)
+	}
+
+	var birthYear int
+	db.Get(&birthYear, "SELECT birth_year FROM people WHERE external_id = 'ch_officer_12345678_appt1'")
+	if birthYear != 1970 {
+		t.Errorf("Expected birth year 1970, got %d", birthYear)
+	}
+
+	var natures string
+	db.Get(&natures, "SELECT natures_of_control FROM people WHERE kind = 'psc'")
+	if natures != `["voting-rights-75-to-100-percent"]` {
+		t.Errorf("Unexpected natures of control %s", natures)
+	}
+}
+
+func TestCompaniesHouseDetailsImporter_ImportMissingList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/company/87654321":
+			fmt.Fprint(w, `{"company_number": "87654321", "company_name": "NO PSC LTD", "company_status": "active"}`)
+		case "/company/87654321/officers":
+			fmt.Fprint(w, `{"total_results": 1, "items": [
+				{"name": "JONES, Ann", "officer_role": "director", "links": {"self": "/company/87654321/appointments/appt1"}}]}`)
+		case "/company/87654321/filing-history":
+			fmt.Fprint(w, `{"total_count": 0, "items": []}`)
+		default:
+			// Companies House answers 404 for a company that has never had a PSC
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db := dbtest.New(t)
+	source := sources.NewCompaniesHouseSource("test-api-key")
+	source.APIClient.BaseURL = server.URL
+
+	stats, err := NewCompaniesHouseDetailsImporter(db, source).Import(context.Background(), "87654321")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Officers != 1 || stats.PSCs != 0 || stats.Filings != 0 {
+		t.Errorf("Expected 1 officer and no PSCs or filings, got %+v", stats)
+	}
+}
@@ -0,0 +1,170 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// companiesStream is the streaming API path for company profile changes,
+// also used as the key of its stored timepoint
+const companiesStream = "/companies"
+
+// companyProfileKind is the resource kind of company profile events
+const companyProfileKind = "company-profile"
+
+// StatusDeleted is recorded as the reported status of companies removed from
+// the register by a deleted event; their normalized status is dissolved
+const StatusDeleted = "deleted"
+
+// CompaniesHouseStreamConsumer applies company profile changes from the
+// streaming API to stored companies. Each event is applied in the same
+// transaction as its timepoint, so after a restart or reconnect the stream
+// resumes after the last applied event.
+type CompaniesHouseStreamConsumer struct {
+	db         *sqlx.DB
+	stream     *sources.CompaniesHouseStream
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// InsertNew also stores companies that are not in the database yet;
+	// by default their events are skipped
+	InsertNew bool
+	logger    *logrus.Logger
+}
+
+// NewCompaniesHouseStreamConsumer creates a consumer writing to db
+func NewCompaniesHouseStreamConsumer(db *sqlx.DB, stream *sources.CompaniesHouseStream) *CompaniesHouseStreamConsumer {
+	return &CompaniesHouseStreamConsumer{
+		db:         db,
+		stream:     stream,
+		MinBackoff: time.Second,
+		MaxBackoff: time.Minute,
+		logger:     logrus.New(),
+	}
+}
+
+// Run consumes the stream until ctx is cancelled, reconnecting with
+// exponential backoff whenever the connection drops or fails. Only a missing
+// or rejected stream key stops it early.
+func (c *CompaniesHouseStreamConsumer) Run(ctx context.Context) error {
+	backoff := c.MinBackoff
+
+	for {
+		timepoint, err := repository.NewCheckpointRepository(c.db).GetTimepoint(ctx, companiesStream)
+		if err != nil {
+			return err
+		}
+
+		from := int64(0)
+		if timepoint > 0 {
+			from = timepoint + 1
+		}
+
+		applied := 0
+		err = c.stream.Stream(ctx, companiesStream, from, func(event sources.StreamEvent) error {
+			if err := c.apply(ctx, event); err != nil {
+				return err
+			}
+			applied++
+			return nil
+		})
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.Is(err, api.ErrAPIKeyMissing) || errors.Is(err, api.ErrUnauthorized) {
+			return err
+		}
+
+		// A connection that delivered events was healthy, so start over
+		if applied > 0 {
+			backoff = c.MinBackoff
+		}
+
+		c.logger.WithFields(logrus.Fields{
+			"stream":  companiesStream,
+			"applied": applied,
+			"error":   err,
+			"retry":   backoff,
+		}).Warn("Stream disconnected, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > c.MaxBackoff {
+			backoff = c.MaxBackoff
+		}
+	}
+}
+
+// apply stores one event and advances the timepoint atomically
+func (c *CompaniesHouseStreamConsumer) apply(ctx context.Context, event sources.StreamEvent) error {
+	return repository.Transact(ctx, c.db, func(tx *sqlx.Tx) error {
+		if event.ResourceKind == companyProfileKind {
+			if err := c.applyCompanyProfile(ctx, tx, event); err != nil {
+				return err
+			}
+		}
+
+		return repository.NewCheckpointRepository(tx).SaveTimepoint(ctx, companiesStream, event.Event.Timepoint)
+	})
+}
+
+func (c *CompaniesHouseStreamConsumer) applyCompanyProfile(ctx context.Context, tx *sqlx.Tx, event sources.StreamEvent) error {
+	companies := repository.NewCompanyRepository(tx)
+
+	if event.Event.Type == sources.StreamEventDeleted {
+		found, err := companies.UpdateStatus(ctx, "ch_"+event.ResourceID, model.StatusDissolved, StatusDeleted)
+		if err != nil {
+			return err
+		}
+		c.logger.WithFields(logrus.Fields{
+			"company_number": event.ResourceID,
+			"stored":         found,
+		}).Info("Company deleted from register")
+		return nil
+	}
+
+	record, err := sources.CompanyProfileRecord(event)
+	if err != nil {
+		// A malformed event would fail again on every reconnect, so skip it
+		c.logger.WithFields(logrus.Fields{
+			"timepoint": event.Event.Timepoint,
+			"error":     err,
+		}).Warn("Skipping unreadable company profile event")
+		return nil
+	}
+
+	if !c.InsertNew {
+		_, err := companies.IDByExternalID(ctx, record.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	companyRecord, err := companyRecord(record)
+	if err != nil {
+		c.logger.WithFields(logrus.Fields{
+			"resource_id": event.ResourceID,
+			"error":       err,
+		}).Warn("Skipping unmappable company profile")
+		return nil
+	}
+
+	return companies.SaveBatch(ctx, []repository.CompanyRecord{companyRecord})
+}
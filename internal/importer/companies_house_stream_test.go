@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestCompaniesHouseStreamConsumer_InsertNew(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	consumer := NewCompaniesHouseStreamConsumer(db, nil)
+	consumer.InsertNew = true
+
+	event := sources.StreamEvent{ResourceKind: companyProfileKind, ResourceID: "00000003"}
+	event.Data = []byte(`{"company_number":"00000003","company_name":"THIRD LTD","company_status":"active"}`)
+	event.Event.Timepoint = 5
+	if err := consumer.apply(ctx, event); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := repository.NewCompanyRepository(db).IDByExternalID(ctx, "ch_00000003"); err != nil {
+		t.Errorf("Expected the new company to be stored, got %v", err)
+	}
+}
+
+func TestCompaniesHouseStreamConsumer_Run(t *testing.T) {
+	db := dbtest.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var timepoints []string
+
+	// The stand-in drops the first connection after two events, then serves
+	// a deletion and holds the connection open until the test ends
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		timepoints = append(timepoints, r.URL.Query().Get("timepoint"))
+		connection := len(timepoints)
+		mu.Unlock()
+
+		flusher := w.(http.Flusher)
+		if connection == 1 {
+			fmt.Fprint(w, `{"resource_kind":"company-profile","resource_id":"00000001","data":{"company_number":"00000001","company_name":"FIRST LTD","company_status":"active","registered_office_address":{"address_line_1":"1 High Street","postal_code":"EC1A 1BB"}},"event":{"timepoint":10,"type":"changed"}}`+"\n")
+			fmt.Fprint(w, `{"resource_kind":"company-profile","resource_id":"00000002","data":{"company_number":"00000002","company_name":"SECOND LTD","company_status":"active"},"event":{"timepoint":11,"type":"changed"}}`+"\n")
+			flusher.Flush()
+			return
+		}
+
+		fmt.Fprint(w, `{"resource_kind":"company-profile","resource_id":"00000001","data":{},"event":{"timepoint":12,"type":"deleted"}}`+"\n")
+		flusher.Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	// Only the first company is stored, so events of the second are skipped
+	stored := repository.CompanyRecord{Company: repository.Company{
+		ExternalID: repository.NullString("ch_00000001"), Name: "OLD NAME LTD", DataSource: "companies_house",
+	}}
+	if _, err := repository.SaveCompany(ctx, db, &stored); err != nil {
+		t.Fatalf("Failed to store company: %v", err)
+	}
+
+	stream := sources.NewCompaniesHouseStream("stream-key")
+	stream.BaseURL = server.URL
+
+	consumer := NewCompaniesHouseStreamConsumer(db, stream)
+	consumer.MinBackoff = 10 * time.Millisecond
+
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	checkpoints := repository.NewCheckpointRepository(db)
+	for {
+		timepoint, _ := checkpoints.GetTimepoint(context.Background(), companiesStream)
+		if timepoint == 12 || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(timepoints) != 2 || timepoints[0] != "" || timepoints[1] != "12" {
+		t.Errorf("Expected reconnect after timepoint 11, got requests %v", timepoints)
+	}
+
+	var status struct {
+		Name   string `db:"name"`
+		Status string `db:"status"`
+		Raw    string `db:"status_raw"`
+	}
+	db.Get(&status, "SELECT name, status, status_raw FROM companies WHERE external_id = 'ch_00000001'")
+	if status.Name != "FIRST LTD" {
+		t.Errorf("Expected ch_00000001 to be updated, got name '%s'", status.Name)
+	}
+	if status.Status != model.StatusDissolved || status.Raw != StatusDeleted {
+		t.Errorf("Expected ch_00000001 to be dissolved after deletion, got '%s' ('%s')", status.Status, status.Raw)
+	}
+
+	var count int
+	db.Get(&count, "SELECT COUNT(*) FROM companies WHERE data_source = 'companies_house'")
+	if count != 1 {
+		t.Errorf("Expected only the stored company, got %d companies", count)
+	}
+
+	timepoint, err := checkpoints.GetTimepoint(context.Background(), companiesStream)
+	if err != nil || timepoint != 12 {
+		t.Errorf("Expected stored timepoint 12, got %d (%v)", timepoint, err)
+	}
+}
@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+const testHeader = "CompanyName, CompanyNumber,RegAddress.AddressLine1,RegAddress.PostTown,RegAddress.PostCode,RegAddress.Country,CompanyStatus,IncorporationDate,SICCode.SicText_1\n"
+
+func writeSnapshot(t *testing.T, rows int) string {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString(testHeader)
+	for i := 1; i <= rows; i++ {
+		fmt.Fprintf(&b, "\"COMPANY %d LTD\",\"%08d\",\"%d High Street\",\"LONDON\",\"EC1A 1BB\",\"UNITED KINGDOM\",\"Active\",\"01/01/2020\",\"62020 - Information technology consultancy activities\"\n", i, i, i)
+	}
+
+	path := filepath.Join(t.TempDir(), "BasicCompanyData-2024-01-01-part1_1.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("BasicCompanyData-2024-01-01-part1_1.csv")
+	w.Write([]byte(b.String()))
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+
+	return path
+}
+
+func TestCompaniesHouseImporter_Run(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	path := writeSnapshot(t, 5)
+
+	imp := NewCompaniesHouseImporter(db, sources.NewCompaniesHouseBulkSource(path))
+	imp.BatchSize = 2
+
+	stats, err := imp.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Imported != 5 {
+		t.Errorf("Expected 5 imported companies, got %d", stats.Imported)
+	}
+
+	var companies, addresses int
+	db.Get(&companies, "SELECT COUNT(*) FROM companies WHERE data_source = 'companies_house'")
+	db.Get(&addresses, "SELECT COUNT(*) FROM addresses a JOIN companies c ON c.id = a.company_id WHERE c.data_source = 'companies_house'")
+	if companies != 5 || addresses != 5 {
+		t.Errorf("Expected 5 companies and 5 addresses, got %d and %d", companies, addresses)
+	}
+
+	var company repository.Company
+	if err := db.Get(&company, "SELECT * FROM companies WHERE external_id = 'ch_00000003'"); err != nil {
+		t.Fatalf("Expected company ch_00000003, got %v", err)
+	}
+	if company.Name != "COMPANY 3 LTD" || company.Status.String != "active" || company.FoundedYear.Int64 != 2020 {
+		t.Errorf("Unexpected company row %+v", company)
+	}
+	if company.LegalForm.String != "Ltd" || company.LegalFormCode.String != "H0PO" || company.MatchKey.String != "company 3" {
+		t.Errorf("Expected legal form Ltd (H0PO) and match key 'company 3', got %s (%s) and '%s'", company.LegalForm.String, company.LegalFormCode.String, company.MatchKey.String)
+	}
+
+	categories, err := repository.NewCategoryRepository(db).ListByCompany(ctx, company.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(categories) != 1 || categories[0].Code != "62" || !categories[0].ParentID.Valid {
+		t.Errorf("Expected division 62 under its section, got %+v", categories)
+	}
+	if company.Industry.String != categories[0].Name {
+		t.Errorf("Expected industry '%s', got '%s'", categories[0].Name, company.Industry.String)
+	}
+
+	// A second run skips the completed part and creates no duplicates
+	stats, err = imp.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error on re-run, got %v", err)
+	}
+	if stats.Skipped != 1 || stats.Imported != 0 {
+		t.Errorf("Expected completed part to be skipped, got %+v", stats)
+	}
+
+	db.Get(&companies, "SELECT COUNT(*) FROM companies WHERE data_source = 'companies_house'")
+	if companies != 5 {
+		t.Errorf("Expected 5 companies after re-run, got %d", companies)
+	}
+
+	ids, err := repository.NewCategoryRepository(db).CompanyIDs(ctx, "J")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ids) != 5 {
+		t.Errorf("Expected 5 companies in section J, got %d", len(ids))
+	}
+}
+
+func TestCompaniesHouseImporter_Resume(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	path := writeSnapshot(t, 5)
+
+	// Simulate an earlier run interrupted after committing three rows
+	err := repository.NewCheckpointRepository(db).Save(ctx, repository.ImportCheckpoint{
+		Source:   checkpointSource,
+		File:     filepath.Base(path),
+		Entry:    "BasicCompanyData-2024-01-01-part1_1.csv",
+		RowsDone: 3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to save checkpoint: %v", err)
+	}
+
+	stats, err := NewCompaniesHouseImporter(db, sources.NewCompaniesHouseBulkSource(path)).Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Imported != 2 {
+		t.Errorf("Expected 2 companies imported after resume, got %d", stats.Imported)
+	}
+
+	var external []string
+	db.Select(&external, "SELECT external_id FROM companies WHERE data_source = 'companies_house' ORDER BY external_id")
+	if len(external) != 2 || external[0] != "ch_00000004" || external[1] != "ch_00000005" {
+		t.Errorf("Expected rows 4 and 5 only, got %v", external)
+	}
+
+	cp, _ := repository.NewCheckpointRepository(db).Get(ctx, checkpointSource, filepath.Base(path), "BasicCompanyData-2024-01-01-part1_1.csv")
+	if !cp.Completed || cp.RowsDone != 5 {
+		t.Errorf("Expected completed checkpoint at row 5, got %+v", cp)
+	}
+}
+
+func TestCompaniesHouseImporter_Cancelled(t *testing.T) {
+	db := dbtest.New(t)
+	path := writeSnapshot(t, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	imp := NewCompaniesHouseImporter(db, sources.NewCompaniesHouseBulkSource(path))
+	imp.BatchSize = 2
+
+	cancel()
+
+	if _, err := imp.Run(ctx); err == nil {
+		t.Fatal("Expected cancellation error, got nil")
+	}
+
+	// Nothing was committed, so the next run imports everything
+	stats, err := imp.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Imported != 5 {
+		t.Errorf("Expected 5 companies imported, got %d", stats.Imported)
+	}
+}
@@ -0,0 +1,145 @@
+package importer
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/industry"
+	"github.com/stkisengese/B2B-Data-Platform/internal/mapping"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// mappers converts raw records into the canonical model before storage
+var mappers = mapping.DefaultRegistry()
+
+// UnmappedStatuses returns the source statuses seen so far that are not in
+// the status mapping tables
+func UnmappedStatuses() []mapping.UnmappedStatus {
+	return mappers.Unmapped.List()
+}
+
+// companyRecord maps a raw record onto table rows through the mapper of its
+// source. Records without a name or company number are rejected with their
+// field errors; other field errors only leave the affected columns empty.
+func companyRecord(record api.RawRecord) (repository.CompanyRecord, error) {
+	company, err := mappers.Map(record)
+
+	var fieldErrs mapping.Errors
+	if err != nil && !errors.As(err, &fieldErrs) {
+		return repository.CompanyRecord{}, err
+	}
+	if company.SourceID == "" || fieldErrs.Has("name") || fieldErrs.Has("company_number") {
+		return repository.CompanyRecord{}, err
+	}
+
+	return companyRecordFromModel(company), nil
+}
+
+// companyRecordFromModel converts a canonical company into table rows
+func companyRecordFromModel(company model.Company) repository.CompanyRecord {
+	row := repository.Company{
+		ExternalID:    repository.NullString(company.SourceID),
+		Name:          company.Name,
+		LegalName:     repository.NullString(company.LegalName),
+		Description:   repository.NullString(company.Description),
+		Website:       repository.NullString(company.Website),
+		Domain:        repository.NullString(company.Domain),
+		Phone:         repository.NullString(company.Phone),
+		PhoneType:     repository.NullString(company.PhoneType),
+		PhoneValid:    sql.NullBool{Bool: company.PhoneValid, Valid: company.Phone != ""},
+		Industry:      repository.NullString(company.Industry),
+		EmployeeCount: repository.NullInt64(int64(company.EmployeeCount)),
+		FoundedYear:   repository.NullInt64(int64(company.FoundedYear)),
+		Status:        repository.NullString(company.Status),
+		StatusRaw:     repository.NullString(company.StatusRaw),
+		LegalForm:     repository.NullString(company.LegalForm),
+		LegalFormCode: repository.NullString(company.LegalFormCode),
+		MatchKey:      repository.NullString(company.MatchKey),
+		DataSource:    company.Source,
+	}
+
+	primary, _ := company.PrimaryAddress()
+
+	var addresses []repository.Address
+	for _, addr := range company.Addresses {
+		line1 := addr.Line1
+		if line1 == "" && addr.Line2 == "" {
+			line1 = addr.Raw
+		}
+
+		addresses = append(addresses, repository.Address{
+			AddressLine1: repository.NullString(line1),
+			AddressLine2: repository.NullString(addr.Line2),
+			City:         repository.NullString(addr.City),
+			State:        repository.NullString(addr.Region),
+			PostalCode:   repository.NullString(addr.PostalCode),
+			Country:      repository.NullString(addr.Country),
+			IsPrimary:    addr == primary,
+		})
+	}
+
+	var identifiers []repository.Identifier
+	for _, id := range company.Identifiers {
+		identifiers = append(identifiers, repository.Identifier{
+			Scheme:     id.Scheme,
+			Value:      id.Value,
+			DataSource: company.Source,
+		})
+	}
+
+	var categories []repository.Category
+	for _, code := range company.Categories {
+		if category := categoryRow(industry.Path(code)); category != nil {
+			categories = append(categories, *category)
+		}
+	}
+
+	var contacts []repository.Contact
+	for _, contact := range company.Contacts {
+		contacts = append(contacts, repository.Contact{
+			ExternalID:     contactExternalID(company.SourceID, contact),
+			Kind:           contact.Kind,
+			Name:           repository.NullString(contact.Name),
+			JobTitle:       repository.NullString(contact.JobTitle),
+			Department:     repository.NullString(contact.Department),
+			Email:          repository.NullString(contact.Email),
+			Phone:          repository.NullString(contact.Phone),
+			DataSource:     company.Source,
+			SourceRecordID: repository.NullString(company.SourceID),
+			CollectedAt:    sql.NullTime{Time: company.CollectedAt.UTC(), Valid: !company.CollectedAt.IsZero()},
+		})
+	}
+
+	return repository.CompanyRecord{
+		Company:     row,
+		Addresses:   addresses,
+		Identifiers: identifiers,
+		Categories:  categories,
+		Contacts:    contacts,
+		CollectedAt: company.CollectedAt,
+	}
+}
+
+// contactExternalID identifies a contact within its source record by its
+// email, or by its name or phone when it has none, e.g.
+// "ch_00445790:sales@example.com"
+func contactExternalID(sourceID string, contact model.Contact) string {
+	key := contact.Email
+	if key == "" {
+		key = contact.Name + "/" + contact.Phone
+	}
+	return sourceID + ":" + strings.ToLower(key)
+}
+
+// categoryRow converts a taxonomy path, top level first, into the row of its
+// last category with its parents attached
+func categoryRow(path []industry.Category) *repository.Category {
+	var row *repository.Category
+	for _, c := range path {
+		row = &repository.Category{Code: c.Code, Name: c.Name, Level: c.Level, Parent: row}
+	}
+	return row
+}
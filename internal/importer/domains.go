@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+	"github.com/stkisengese/B2B-Data-Platform/internal/website"
+)
+
+// domainPageSize is the number of companies indexed per transaction
+const domainPageSize = 1000
+
+// DomainStats counts the outcome of a domain indexing run
+type DomainStats struct {
+	Checked int
+	Indexed int
+	Invalid int
+}
+
+// DomainIndexer canonicalizes the websites of stored companies and indexes
+// their registrable domains, for rows stored before websites were
+// normalized on import
+type DomainIndexer struct {
+	db     *sqlx.DB
+	logger *logrus.Logger
+}
+
+// NewDomainIndexer creates an indexer writing to db
+func NewDomainIndexer(db *sqlx.DB) *DomainIndexer {
+	return &DomainIndexer{
+		db:     db,
+		logger: logrus.New(),
+	}
+}
+
+// Run canonicalizes every stored website. Websites that cannot be parsed
+// are left as they are, without a domain.
+func (i *DomainIndexer) Run(ctx context.Context) (DomainStats, error) {
+	var stats DomainStats
+
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		companies, err := repository.NewCompanyRepository(i.db).ListAfter(ctx, afterID, domainPageSize)
+		if err != nil {
+			return stats, err
+		}
+		if len(companies) == 0 {
+			break
+		}
+		afterID = companies[len(companies)-1].ID
+
+		err = repository.Transact(ctx, i.db, func(tx *sqlx.Tx) error {
+			repo := repository.NewCompanyRepository(tx)
+			for _, company := range companies {
+				if !company.Website.Valid {
+					continue
+				}
+				stats.Checked++
+
+				site, err := website.Parse(company.Website.String)
+				if err != nil {
+					stats.Invalid++
+					i.logger.WithFields(logrus.Fields{
+						"company_id": company.ID,
+						"website":    company.Website.String,
+					}).Debug("Skipping invalid website")
+					continue
+				}
+				if site.URL == company.Website.String && site.Domain == company.Domain.String {
+					continue
+				}
+
+				if err := repo.SetWebsite(ctx, company.ID, site.URL, site.Domain); err != nil {
+					return err
+				}
+				stats.Indexed++
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestDomainIndexer_Run(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	db.MustExec(`INSERT INTO companies (name, website, data_source) VALUES
+		('Foo Ltd', 'www.Foo.co.uk/?utm_source=crm', 'manual'),
+		('Foo Shop Ltd', 'http://shop.foo.co.uk/', 'manual'),
+		('Broken Ltd', 'not a website', 'manual')`)
+
+	stats, err := NewDomainIndexer(db).Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Invalid != 1 || stats.Indexed < 2 {
+		t.Errorf("Expected 1 invalid and at least 2 indexed websites, got %+v", stats)
+	}
+
+	companies, err := repository.NewCompanyRepository(db).ListByDomain(ctx, "foo.co.uk")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(companies) != 2 {
+		t.Fatalf("Expected 2 companies on foo.co.uk, got %d", len(companies))
+	}
+	if companies[0].Website.String != "https://www.foo.co.uk" || companies[1].Website.String != "http://shop.foo.co.uk" {
+		t.Errorf("Expected canonical websites, got '%s' and '%s'", companies[0].Website.String, companies[1].Website.String)
+	}
+
+	// A second run has nothing left to change
+	stats, err = NewDomainIndexer(db).Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error on re-run, got %v", err)
+	}
+	if stats.Indexed != 0 {
+		t.Errorf("Expected nothing to index on re-run, got %+v", stats)
+	}
+}
@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// LinkStats counts the outcome of a linking run
+type LinkStats struct {
+	Checked int
+	Linked  int
+}
+
+// DefaultLookupRetry is how long a company that was looked up in an
+// external register without a match is left out of later runs
+const DefaultLookupRetry = 30 * 24 * time.Hour
+
+// GLEIFLinker cross-references stored companies with the GLEIF register by
+// registration number and jurisdiction, and attaches the LEIs it finds as
+// identifiers of the matching companies
+type GLEIFLinker struct {
+	db     *sqlx.DB
+	source *sources.GLEIFSource
+	// RetryAfter is how long a company is skipped after it was looked up
+	RetryAfter time.Duration
+	now        func() time.Time
+	logger     *logrus.Logger
+}
+
+// NewGLEIFLinker creates a linker writing to db
+func NewGLEIFLinker(db *sqlx.DB, source *sources.GLEIFSource) *GLEIFLinker {
+	return &GLEIFLinker{
+		db:         db,
+		source:     source,
+		RetryAfter: DefaultLookupRetry,
+		now:        time.Now,
+		logger:     logrus.New(),
+	}
+}
+
+// Run looks up to limit companies that have a registration identifier but
+// no LEI yet in GLEIF and links those that are found. Every company looked
+// up is recorded, so that those without a match are not looked up again
+// until RetryAfter has passed and the next run moves on to other companies.
+func (l *GLEIFLinker) Run(ctx context.Context, limit int) (LinkStats, error) {
+	var stats LinkStats
+
+	now := l.now()
+	missing, err := repository.NewIdentifierRepository(l.db).ListMissing(
+		ctx, repository.SchemeLEI, repository.LookupGLEIF, now.Add(-l.RetryAfter), limit,
+	)
+	if err != nil {
+		return stats, err
+	}
+
+	// Companies from several sources can share a registration identifier, so
+	// look each one up only once
+	companies := make(map[string][]int64)
+	var values []string
+	for _, identifier := range missing {
+		if companies[identifier.Value] == nil {
+			values = append(values, identifier.Value)
+		}
+		companies[identifier.Value] = append(companies[identifier.Value], identifier.CompanyID)
+	}
+
+	lookups := repository.NewLookupRepository(l.db)
+	for _, value := range values {
+		jurisdiction, number, ok := strings.Cut(value, ":")
+		if ok {
+			records, err := l.source.LookupRegistration(ctx, jurisdiction, number)
+			if err != nil {
+				return stats, err
+			}
+			stats.Checked++
+
+			linked, err := l.LinkRecords(ctx, records)
+			if err != nil {
+				return stats, err
+			}
+			stats.Linked += linked
+		}
+
+		if err := lookups.RecordAttempt(ctx, repository.LookupGLEIF, now, companies[value]...); err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+// LinkRecords attaches the LEI of each GLEIF record to the companies
+// registered under the record's registration number and jurisdiction, and
+// returns the number of companies linked
+func (l *GLEIFLinker) LinkRecords(ctx context.Context, records []api.RawRecord) (int, error) {
+	linked := 0
+
+	err := repository.Transact(ctx, l.db, func(tx *sqlx.Tx) error {
+		identifiers := repository.NewIdentifierRepository(tx)
+
+		for _, record := range records {
+			lei := str(record.Data, "lei")
+			number := str(record.Data, "registered_as")
+			jurisdiction := str(record.Data, "jurisdiction")
+			if lei == "" || number == "" || jurisdiction == "" {
+				continue
+			}
+
+			ids, err := identifiers.CompanyIDs(ctx, repository.SchemeRegistration, model.RegistrationValue(jurisdiction, number))
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				if err := identifiers.Attach(ctx, &repository.Identifier{
+					CompanyID:  id,
+					Scheme:     repository.SchemeLEI,
+					Value:      lei,
+					DataSource: record.Source,
+				}); err != nil {
+					return err
+				}
+				linked++
+			}
+
+			if len(ids) > 0 {
+				l.logger.WithFields(logrus.Fields{
+					"lei":       lei,
+					"companies": len(ids),
+				}).Info("Linked LEI")
+			}
+		}
+
+		return nil
+	})
+
+	return linked, err
+}
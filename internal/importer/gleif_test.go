@@ -0,0 +1,122 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestGLEIFLinker_Run(t *testing.T) {
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		if r.URL.Query().Get("filter[entity.registeredAs]") != "12345678" {
+			fmt.Fprint(w, `{"data": []}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": [{"id": "213800EXAMPLE0000001", "attributes": {"lei": "213800EXAMPLE0000001",
+			"entity": {"legalName": {"name": "TEST COMPANY LIMITED"}, "registeredAs": "12345678", "jurisdiction": "GB"}}}]}`)
+	}))
+	defer server.Close()
+
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	for _, number := range []string{"12345678", "87654321"} {
+		record, err := companyRecord(api.RawRecord{
+			ID:     "ch_" + number,
+			Source: "companies_house",
+			Data:   map[string]interface{}{"company_number": number, "name": "COMPANY " + number},
+		})
+		if err != nil {
+			t.Fatalf("Expected company record for %s, got %v", number, err)
+		}
+		if err := repository.NewCompanyRepository(db).SaveBatch(ctx, []repository.CompanyRecord{record}); err != nil {
+			t.Fatalf("Failed to save company: %v", err)
+		}
+	}
+
+	source := sources.NewGLEIFSource()
+	source.APIClient.BaseURL = server.URL
+	linker := NewGLEIFLinker(db, source)
+
+	stats, err := linker.Run(ctx, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Checked != 2 || stats.Linked != 1 {
+		t.Errorf("Expected 2 checked and 1 linked, got %+v", stats)
+	}
+
+	identifiers := repository.NewIdentifierRepository(db)
+	ids, err := identifiers.CompanyIDs(ctx, repository.SchemeLEI, "213800EXAMPLE0000001")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	companyID, _ := repository.NewCompanyRepository(db).IDByExternalID(ctx, "ch_12345678")
+	if len(ids) != 1 || ids[0] != companyID {
+		t.Errorf("Expected LEI on company %d, got %v", companyID, ids)
+	}
+
+	// The unmatched company is not looked up again until RetryAfter has passed
+	lookups = 0
+	if _, err := linker.Run(ctx, 10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if lookups != 0 {
+		t.Errorf("Expected no lookups on second run, got %d", lookups)
+	}
+
+	linker.now = func() time.Time { return time.Now().Add(DefaultLookupRetry + time.Hour) }
+	if _, err := linker.Run(ctx, 10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if lookups != 1 {
+		t.Errorf("Expected 1 lookup after the retry period, got %d", lookups)
+	}
+}
+
+func TestGLEIFLinker_RunMovesOn(t *testing.T) {
+	var looked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		looked = append(looked, r.URL.Query().Get("filter[entity.registeredAs]"))
+		fmt.Fprint(w, `{"data": []}`)
+	}))
+	defer server.Close()
+
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	for _, number := range []string{"11111111", "22222222", "33333333"} {
+		record, _ := companyRecord(api.RawRecord{
+			ID:     "ch_" + number,
+			Source: "companies_house",
+			Data:   map[string]interface{}{"company_number": number, "name": "COMPANY " + number},
+		})
+		if err := repository.NewCompanyRepository(db).SaveBatch(ctx, []repository.CompanyRecord{record}); err != nil {
+			t.Fatalf("Failed to save company: %v", err)
+		}
+	}
+
+	source := sources.NewGLEIFSource()
+	source.APIClient.BaseURL = server.URL
+	linker := NewGLEIFLinker(db, source)
+
+	// Unmatched companies don't keep later ones from being looked up
+	for range 4 {
+		if _, err := linker.Run(ctx, 1); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if fmt.Sprint(looked) != "[11111111 22222222 33333333]" {
+		t.Errorf("Expected each company looked up once, got %v", looked)
+	}
+}
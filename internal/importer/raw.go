@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// rawPageSize is the number of raw records processed per transaction
+const rawPageSize = 500
+
+// RawStats counts the outcome of storing collected records
+type RawStats struct {
+	Stored     int
+	Duplicates int
+}
+
+// ProcessStats counts the outcome of processing raw records
+type ProcessStats struct {
+	Processed int
+	Saved     int
+	// Skipped records could not be mapped; the reason is stored in
+	// process_error
+	Skipped int
+}
+
+// RawProcessor lands collected records in the raw_records table and maps
+// them into companies, so that mapping can be re-run over stored payloads
+// without collecting them again
+type RawProcessor struct {
+	db     *sqlx.DB
+	now    func() time.Time
+	logger *logrus.Logger
+}
+
+// NewRawProcessor creates a processor writing to db
+func NewRawProcessor(db *sqlx.DB) *RawProcessor {
+	return &RawProcessor{
+		db:     db,
+		now:    time.Now,
+		logger: logrus.New(),
+	}
+}
+
+// Store saves collected records. Payloads identical to a stored version of
+// the same record are not stored again.
+func (p *RawProcessor) Store(ctx context.Context, records []api.RawRecord) (RawStats, error) {
+	var stats RawStats
+
+	err := repository.Transact(ctx, p.db, func(tx *sqlx.Tx) error {
+		repo := repository.NewRawRecordRepository(tx)
+		for _, record := range records {
+			row, err := rawRow(record, p.now())
+			if err != nil {
+				return err
+			}
+
+			inserted, err := repo.Insert(ctx, &row)
+			if err != nil {
+				return err
+			}
+			if inserted {
+				stats.Stored++
+			} else {
+				stats.Duplicates++
+			}
+		}
+		return nil
+	})
+
+	return stats, err
+}
+
+// ProcessPending maps and saves the records that have not been processed,
+// in the order they were last collected
+func (p *RawProcessor) ProcessPending(ctx context.Context) (ProcessStats, error) {
+	var stats ProcessStats
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		rows, err := repository.NewRawRecordRepository(p.db).ListPending(ctx, rawPageSize)
+		if err != nil {
+			return stats, err
+		}
+		if len(rows) == 0 {
+			return stats, nil
+		}
+
+		if err := p.process(ctx, rows, &stats); err != nil {
+			return stats, err
+		}
+	}
+}
+
+// Replay maps and saves the latest version of every stored record again, or
+// of the records of one source, e.g. after a mapper was fixed
+func (p *RawProcessor) Replay(ctx context.Context, source string) (ProcessStats, error) {
+	var stats ProcessStats
+
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		rows, err := repository.NewRawRecordRepository(p.db).ListLatest(ctx, source, afterID, rawPageSize)
+		if err != nil {
+			return stats, err
+		}
+		if len(rows) == 0 {
+			return stats, nil
+		}
+		afterID = rows[len(rows)-1].ID
+
+		if err := p.process(ctx, rows, &stats); err != nil {
+			return stats, err
+		}
+	}
+}
+
+// process maps a page of raw records and saves the mapped companies in one
+// transaction, marking every record processed
+func (p *RawProcessor) process(ctx context.Context, rows []repository.RawRecord, stats *ProcessStats) error {
+	return repository.Transact(ctx, p.db, func(tx *sqlx.Tx) error {
+		raw := repository.NewRawRecordRepository(tx)
+		now := p.now()
+
+		var records []repository.CompanyRecord
+		for _, row := range rows {
+			record, err := decodeRawRow(row)
+			var company repository.CompanyRecord
+			if err == nil {
+				company, err = companyRecord(record)
+			}
+			if err == nil && company.Company.Name == "" {
+				err = errors.New("record has no company")
+			}
+
+			processErr := ""
+			if err != nil {
+				processErr = err.Error()
+				stats.Skipped++
+				p.logger.WithFields(logrus.Fields{
+					"source":    row.Source,
+					"record_id": row.RecordID,
+					"error":     err,
+				}).Debug("Skipping raw record")
+			} else {
+				records = append(records, company)
+			}
+
+			if err := raw.MarkProcessed(ctx, row.ID, processErr, now); err != nil {
+				return err
+			}
+			stats.Processed++
+		}
+
+		if err := repository.NewCompanyRepository(tx).SaveBatch(ctx, records); err != nil {
+			return err
+		}
+		stats.Saved += len(records)
+		return nil
+	})
+}
+
+// rawRow encodes a collected record. encoding/json sorts map keys, so equal
+// data always hashes the same.
+func rawRow(record api.RawRecord, now time.Time) (repository.RawRecord, error) {
+	payload, err := json.Marshal(record.Data)
+	if err != nil {
+		return repository.RawRecord{}, fmt.Errorf("failed to encode raw record %s: %w", record.ID, err)
+	}
+	hash := sha256.Sum256(payload)
+
+	collectedAt := record.CollectedAt
+	if collectedAt.IsZero() {
+		collectedAt = now
+	}
+
+	return repository.RawRecord{
+		Source:      record.Source,
+		RecordID:    record.ID,
+		Payload:     string(payload),
+		PayloadHash: hex.EncodeToString(hash[:]),
+		CollectedAt: collectedAt,
+	}, nil
+}
+
+// decodeRawRow restores a collected record from its stored version. Numbers
+// are kept as json.Number, and the record counts as collected when its
+// payload was last seen.
+func decodeRawRow(row repository.RawRecord) (api.RawRecord, error) {
+	decoder := json.NewDecoder(strings.NewReader(row.Payload))
+	decoder.UseNumber()
+
+	var data map[string]interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return api.RawRecord{}, fmt.Errorf("failed to decode raw record %s: %w", row.RecordID, err)
+	}
+
+	return api.RawRecord{
+		ID:          row.RecordID,
+		Source:      row.Source,
+		Data:        data,
+		CollectedAt: row.LastSeenAt,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestRawProcessor(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	processor := NewRawProcessor(db)
+
+	collected := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	record := func(employees int, at time.Time) api.RawRecord {
+		return api.RawRecord{
+			ID:     "registry_1",
+			Source: "registry",
+			Data: map[string]interface{}{
+				"name":           "TEST LIMITED",
+				"company_number": "01234567",
+				"jurisdiction":   "gb",
+				"employee_count": employees,
+			},
+			CollectedAt: at,
+		}
+	}
+	nameless := api.RawRecord{ID: "registry_2", Source: "registry", Data: map[string]interface{}{"company_number": "7654321"}, CollectedAt: collected}
+
+	employees := func() int64 {
+		t.Helper()
+		var company repository.Company
+		if err := db.Get(&company, "SELECT * FROM companies WHERE external_id = 'registry_1'"); err != nil {
+			t.Fatalf("Failed to load company: %v", err)
+		}
+		return company.EmployeeCount.Int64
+	}
+	process := func(expected ProcessStats) {
+		t.Helper()
+		stats, err := processor.ProcessPending(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stats != expected {
+			t.Errorf("Expected %+v, got %+v", expected, stats)
+		}
+	}
+
+	stats, err := processor.Store(ctx, []api.RawRecord{record(120, collected), nameless})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Stored != 2 {
+		t.Errorf("Expected 2 records stored, got %+v", stats)
+	}
+
+	// Collecting identical payloads again stores nothing new
+	stats, _ = processor.Store(ctx, []api.RawRecord{record(120, collected.Add(time.Hour)), nameless})
+	if stats.Stored != 0 || stats.Duplicates != 2 {
+		t.Errorf("Expected 2 duplicates, got %+v", stats)
+	}
+
+	process(ProcessStats{Processed: 2, Saved: 1, Skipped: 1})
+	if n := employees(); n != 120 {
+		t.Errorf("Expected 120 employees, got %d", n)
+	}
+	var processErr string
+	db.Get(&processErr, "SELECT process_error FROM raw_records WHERE record_id = 'registry_2'")
+	if processErr == "" {
+		t.Errorf("Expected the reason the nameless record was skipped to be stored")
+	}
+	process(ProcessStats{})
+
+	// A changed payload is a new version
+	processor.Store(ctx, []api.RawRecord{record(150, collected.Add(2*time.Hour))})
+	process(ProcessStats{Processed: 1, Saved: 1})
+	if n := employees(); n != 150 {
+		t.Errorf("Expected the new version with 150 employees, got %d", n)
+	}
+
+	// A payload reverting to an earlier version is applied again
+	processor.Store(ctx, []api.RawRecord{record(120, collected.Add(3*time.Hour))})
+	process(ProcessStats{Processed: 1, Saved: 1})
+	if n := employees(); n != 120 {
+		t.Errorf("Expected the reverted version with 120 employees, got %d", n)
+	}
+
+	// Replaying processes the latest version of every record
+	db.MustExec("UPDATE companies SET employee_count = NULL WHERE external_id = 'registry_1'")
+	replayed, err := processor.Replay(ctx, "registry")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if replayed != (ProcessStats{Processed: 2, Saved: 1, Skipped: 1}) {
+		t.Errorf("Expected 2 records replayed, got %+v", replayed)
+	}
+	if n := employees(); n != 120 {
+		t.Errorf("Expected replay to restore 120 employees, got %d", n)
+	}
+}
@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// statusPageSize is the number of companies normalized per transaction
+const statusPageSize = 1000
+
+// StatusStats counts the outcome of a status normalization run
+type StatusStats struct {
+	Checked  int
+	Updated  int
+	Unmapped int
+}
+
+// StatusNormalizer maps the reported status of stored companies to a
+// normalized status again with model.NormalizeStatus, for rows stored
+// before the mapping tables covered their values
+type StatusNormalizer struct {
+	db     *sqlx.DB
+	logger *logrus.Logger
+}
+
+// NewStatusNormalizer creates a normalizer writing to db
+func NewStatusNormalizer(db *sqlx.DB) *StatusNormalizer {
+	return &StatusNormalizer{
+		db:     db,
+		logger: logrus.New(),
+	}
+}
+
+// Run normalizes the status of every company with a reported status.
+// Companies without one, such as those entered manually, are left as they
+// are.
+func (n *StatusNormalizer) Run(ctx context.Context) (StatusStats, error) {
+	var stats StatusStats
+
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		companies, err := repository.NewCompanyRepository(n.db).ListAfter(ctx, afterID, statusPageSize)
+		if err != nil {
+			return stats, err
+		}
+		if len(companies) == 0 {
+			break
+		}
+		afterID = companies[len(companies)-1].ID
+
+		err = repository.Transact(ctx, n.db, func(tx *sqlx.Tx) error {
+			repo := repository.NewCompanyRepository(tx)
+			for _, company := range companies {
+				if !company.StatusRaw.Valid {
+					continue
+				}
+				stats.Checked++
+
+				status, ok := model.NormalizeStatus(company.StatusRaw.String)
+				if !ok {
+					stats.Unmapped++
+					n.logger.WithFields(logrus.Fields{
+						"company_id": company.ID,
+						"status":     company.StatusRaw.String,
+					}).Debug("Unmapped company status")
+				}
+				if status == company.Status.String {
+					continue
+				}
+
+				if err := repo.SetStatus(ctx, company.ID, status); err != nil {
+					return err
+				}
+				stats.Updated++
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,53 @@
+package importer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+)
+
+func TestStatusNormalizer_Run(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	// Statuses the SQL backfill of status_raw left as 'unknown'
+	db.MustExec(`INSERT INTO companies (name, status, status_raw, data_source) VALUES
+		('Receiver Ltd', 'unknown', 'In Administration/Receiver Action', 'opencorporates'),
+		('Struck Ltd', 'unknown', 'Struck Off', 'opencorporates'),
+		('Liquid Ltd', 'unknown', 'In Liquidation', 'opencorporates'),
+		('Standing Inc', 'unknown', 'Good Standing', 'opencorporates'),
+		('Odd Ltd', 'unknown', 'Limbo', 'opencorporates'),
+		('Manual Ltd', 'dormant', NULL, 'manual')`)
+
+	stats, err := NewStatusNormalizer(db).Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Checked != 5 || stats.Updated != 4 || stats.Unmapped != 1 {
+		t.Errorf("Expected 5 checked, 4 updated and 1 unmapped, got %+v", stats)
+	}
+
+	want := map[string]string{
+		"Receiver Ltd": "in_insolvency",
+		"Struck Ltd":   "dissolved",
+		"Liquid Ltd":   "in_insolvency",
+		"Standing Inc": "active",
+		"Odd Ltd":      "unknown",
+		"Manual Ltd":   "dormant",
+	}
+	for name, status := range want {
+		var got string
+		if err := db.Get(&got, "SELECT status FROM companies WHERE name = ?", name); err != nil {
+			t.Fatalf("Failed to load %s: %v", name, err)
+		}
+		if got != status {
+			t.Errorf("Expected %s to be '%s', got '%s'", name, status, got)
+		}
+	}
+
+	// A second run has nothing left to change
+	if stats, _ := NewStatusNormalizer(db).Run(ctx); stats.Updated != 0 {
+		t.Errorf("Expected nothing to update on re-run, got %+v", stats)
+	}
+}
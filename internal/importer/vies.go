@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// DefaultVATCacheTTL is how long a VIES answer is reused before the VAT ID
+// is checked again
+const DefaultVATCacheTTL = 24 * time.Hour
+
+// VATValidator validates VAT IDs through VIES, caching answers in the
+// vat_validations table, and links valid VAT IDs to companies
+type VATValidator struct {
+	db       *sqlx.DB
+	source   *sources.VIESSource
+	CacheTTL time.Duration
+	now      func() time.Time
+	logger   *logrus.Logger
+}
+
+// NewVATValidator creates a validator writing to db
+func NewVATValidator(db *sqlx.DB, source *sources.VIESSource) *VATValidator {
+	return &VATValidator{
+		db:       db,
+		source:   source,
+		CacheTTL: DefaultVATCacheTTL,
+		now:      time.Now,
+		logger:   logrus.New(),
+	}
+}
+
+// Validate returns the validation of a VAT ID, querying VIES only when no
+// unexpired answer is cached
+func (v *VATValidator) Validate(ctx context.Context, vatID string) (repository.VATValidation, error) {
+	normalized, err := sources.NormalizeVATID(vatID)
+	if err != nil {
+		return repository.VATValidation{}, err
+	}
+
+	validations := repository.NewVATValidationRepository(v.db)
+	now := v.now()
+
+	cached, ok, err := validations.GetFresh(ctx, normalized, now)
+	if err != nil || ok {
+		return cached, err
+	}
+
+	record, err := v.source.CheckVAT(ctx, normalized)
+	if err != nil {
+		return repository.VATValidation{}, err
+	}
+
+	valid, _ := record.Data["valid"].(bool)
+	validation := repository.VATValidation{
+		VATID:       normalized,
+		CountryCode: str(record.Data, "country_code"),
+		VATNumber:   str(record.Data, "vat_number"),
+		Valid:       valid,
+		Name:        repository.NullString(str(record.Data, "name")),
+		Address:     repository.NullString(str(record.Data, "address")),
+		CheckedAt:   now,
+		ExpiresAt:   now.Add(v.CacheTTL),
+	}
+	if err := validations.Save(ctx, validation); err != nil {
+		return validation, err
+	}
+
+	v.logger.WithFields(logrus.Fields{
+		"vat_id": normalized,
+		"valid":  valid,
+	}).Info("VAT ID checked with VIES")
+
+	return validation, nil
+}
+
+// Link validates a VAT ID and, if it is valid, attaches it to the company
+// with the given external ID
+func (v *VATValidator) Link(ctx context.Context, externalID, vatID string) (repository.VATValidation, error) {
+	validation, err := v.Validate(ctx, vatID)
+	if err != nil {
+		return validation, err
+	}
+	if !validation.Valid {
+		return validation, nil
+	}
+
+	companyID, err := repository.NewCompanyRepository(v.db).IDByExternalID(ctx, externalID)
+	if err != nil {
+		return validation, err
+	}
+
+	err = repository.NewIdentifierRepository(v.db).Attach(ctx, &repository.Identifier{
+		CompanyID:  companyID,
+		Scheme:     repository.SchemeVAT,
+		Value:      validation.VATID,
+		DataSource: "vies",
+	})
+	if err != nil {
+		return validation, fmt.Errorf("failed to link VAT ID to %s: %w", externalID, err)
+	}
+
+	return validation, nil
+}
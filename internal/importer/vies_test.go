@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestVATValidator_Link(t *testing.T) {
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks++
+		if r.URL.Path == "/ms/DE/vat/123456789" {
+			fmt.Fprint(w, `{"isValid": true, "userError": "VALID", "name": "TEST GMBH", "address": "TESTSTRASSE 1, BERLIN"}`)
+			return
+		}
+		fmt.Fprint(w, `{"isValid": false, "userError": "INVALID"}`)
+	}))
+	defer server.Close()
+
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	companyID, err := repository.NewCompanyRepository(db).Upsert(ctx, &repository.Company{
+		ExternalID: repository.NullString("oc_de_HRB1"),
+		Name:       "Test GmbH",
+		DataSource: "opencorporates",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create company: %v", err)
+	}
+
+	source := sources.NewVIESSource()
+	source.APIClient.BaseURL = server.URL
+	validator := NewVATValidator(db, source)
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	validator.now = func() time.Time { return now }
+
+	validation, err := validator.Link(ctx, "oc_de_HRB1", "DE 123456789")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !validation.Valid || validation.Name.String != "TEST GMBH" {
+		t.Errorf("Unexpected validation %+v", validation)
+	}
+
+	ids, _ := repository.NewIdentifierRepository(db).CompanyIDs(ctx, repository.SchemeVAT, "DE123456789")
+	if len(ids) != 1 || ids[0] != companyID {
+		t.Errorf("Expected VAT ID linked to company %d, got %v", companyID, ids)
+	}
+
+	// Invalid numbers are cached too but never linked
+	if validation, err := validator.Link(ctx, "oc_de_HRB1", "DE999999999"); err != nil || validation.Valid {
+		t.Errorf("Expected invalid validation, got %+v (%v)", validation, err)
+	}
+	ids, _ = repository.NewIdentifierRepository(db).CompanyIDs(ctx, repository.SchemeVAT, "DE999999999")
+	if len(ids) != 0 {
+		t.Errorf("Expected invalid VAT ID not to be linked, got %v", ids)
+	}
+
+	// Answers are served from the cache until they expire
+	checks = 0
+	now = now.Add(time.Hour)
+	if _, err := validator.Validate(ctx, "DE123456789"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if checks != 0 {
+		t.Errorf("Expected cached answer, got %d VIES requests", checks)
+	}
+
+	now = now.Add(DefaultVATCacheTTL)
+	if _, err := validator.Validate(ctx, "DE123456789"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if checks != 1 {
+		t.Errorf("Expected expired answer to be rechecked, got %d VIES requests", checks)
+	}
+}
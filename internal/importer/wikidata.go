@@ -0,0 +1,171 @@
+package importer
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+	"github.com/stkisengese/B2B-Data-Platform/internal/website"
+)
+
+// EnrichStats counts the outcome of an enrichment run
+type EnrichStats struct {
+	Checked int
+	Matched int
+	Updated int
+}
+
+// WikidataEnricher fills empty website, industry, employee count and
+// founded year columns of stored companies from Wikidata and records the
+// matching item as a wikidata identifier
+type WikidataEnricher struct {
+	db     *sqlx.DB
+	source *sources.WikidataSource
+	// RetryAfter is how long a company is skipped after it was looked up
+	RetryAfter time.Duration
+	now        func() time.Time
+	logger     *logrus.Logger
+}
+
+// NewWikidataEnricher creates an enricher writing to db
+func NewWikidataEnricher(db *sqlx.DB, source *sources.WikidataSource) *WikidataEnricher {
+	return &WikidataEnricher{
+		db:         db,
+		source:     source,
+		RetryAfter: DefaultLookupRetry,
+		now:        time.Now,
+		logger:     logrus.New(),
+	}
+}
+
+// Run looks up to limit companies that have no Wikidata item yet and
+// enriches those that match exactly one item. Companies without a match
+// are skipped until RetryAfter has passed, so later runs move on.
+func (e *WikidataEnricher) Run(ctx context.Context, limit int) (EnrichStats, error) {
+	var stats EnrichStats
+
+	now := e.now()
+	companies, err := repository.NewCompanyRepository(e.db).ListWithoutIdentifier(
+		ctx, repository.SchemeWikidata, repository.LookupWikidata, now.Add(-e.RetryAfter), limit,
+	)
+	if err != nil {
+		return stats, err
+	}
+
+	lookups := repository.NewLookupRepository(e.db)
+	for _, company := range companies {
+		records, err := e.lookup(ctx, company)
+		if err != nil {
+			return stats, err
+		}
+		stats.Checked++
+
+		if err := lookups.RecordAttempt(ctx, repository.LookupWikidata, now, company.ID); err != nil {
+			return stats, err
+		}
+
+		// Several items sharing a label or identifier cannot be told apart
+		if len(records) != 1 {
+			continue
+		}
+		stats.Matched++
+
+		updated, err := e.Enrich(ctx, company.ID, records[0])
+		if err != nil {
+			return stats, err
+		}
+		if updated {
+			stats.Updated++
+		}
+	}
+
+	return stats, nil
+}
+
+// lookup finds Wikidata items for a company by LEI, then by UK company
+// number, and by exact name only when it has neither
+func (e *WikidataEnricher) lookup(ctx context.Context, company repository.Company) ([]api.RawRecord, error) {
+	identifiers, err := repository.NewIdentifierRepository(e.db).ListByCompany(ctx, company.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identifier := range identifiers {
+		if identifier.Scheme == repository.SchemeLEI {
+			return e.source.LookupByIdentifier(ctx, sources.WikidataLEI, identifier.Value)
+		}
+	}
+	for _, identifier := range identifiers {
+		if number, ok := strings.CutPrefix(identifier.Value, "GB:"); ok && identifier.Scheme == repository.SchemeRegistration {
+			return e.source.LookupByIdentifier(ctx, sources.WikidataUKCompanyNumber, number)
+		}
+	}
+
+	name := company.Name
+	if company.LegalName.Valid {
+		name = company.LegalName.String
+	}
+	return e.source.LookupByName(ctx, name)
+}
+
+// Enrich fills the empty columns of a company from a Wikidata record and
+// attaches the item's QID, reporting whether any column changed
+func (e *WikidataEnricher) Enrich(ctx context.Context, companyID int64, record api.RawRecord) (bool, error) {
+	company := repository.Company{
+		ID:          companyID,
+		Website:     repository.NullString(str(record.Data, "website")),
+		Description: repository.NullString(str(record.Data, "description")),
+	}
+	if site, err := website.Parse(company.Website.String); err == nil {
+		company.Website = repository.NullString(site.URL)
+		company.Domain = repository.NullString(site.Domain)
+	}
+	if industries, ok := record.Data["industries"].([]interface{}); ok && len(industries) > 0 {
+		if industry, ok := industries[0].(map[string]interface{}); ok {
+			company.Industry = repository.NullString(str(industry, "label"))
+		}
+	}
+	if _, ok := record.Data["employee_count"]; ok {
+		company.EmployeeCount = repository.NullInt64(num(record.Data, "employee_count"))
+	}
+	if _, ok := record.Data["founded_year"]; ok {
+		company.FoundedYear = repository.NullInt64(num(record.Data, "founded_year"))
+	}
+
+	var updated bool
+	err := repository.Transact(ctx, e.db, func(tx *sqlx.Tx) error {
+		filled, err := repository.NewCompanyRepository(tx).FillBlanks(ctx, company)
+		if err != nil {
+			return err
+		}
+		updated = len(filled) > 0
+
+		err = repository.NewProvenanceRepository(tx).Record(ctx, companyID, filled, record.Source, record.ID, record.CollectedAt)
+		if err != nil {
+			return err
+		}
+
+		return repository.NewIdentifierRepository(tx).Attach(ctx, &repository.Identifier{
+			CompanyID:  companyID,
+			Scheme:     repository.SchemeWikidata,
+			Value:      str(record.Data, "qid"),
+			DataSource: record.Source,
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+
+	e.logger.WithFields(logrus.Fields{
+		"company_id": companyID,
+		"qid":        str(record.Data, "qid"),
+		"updated":    updated,
+	}).Info("Enriched company from Wikidata")
+
+	return updated, nil
+}
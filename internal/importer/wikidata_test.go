@@ -0,0 +1,96 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api/sources"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestWikidataEnricher_Run(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		switch {
+		case strings.Contains(query, `"Apple Inc."@en`):
+			fmt.Fprint(w, `{"results": {"bindings": [{
+				"item": {"value": "http://www.wikidata.org/entity/Q312"},
+				"itemLabel": {"value": "Apple Inc."},
+				"inception": {"value": "1976-04-01T00:00:00Z"},
+				"website": {"value": "https://www.apple.com/"},
+				"employees": {"value": "164000"},
+				"industry": {"value": "http://www.wikidata.org/entity/Q11661"},
+				"industryLabel": {"value": "information technology"}}]}}`)
+		case strings.Contains(query, `"Google LLC"@en`):
+			// Two items with the same label are ambiguous
+			fmt.Fprint(w, `{"results": {"bindings": [
+				{"item": {"value": "http://www.wikidata.org/entity/Q95"}},
+				{"item": {"value": "http://www.wikidata.org/entity/Q999"}}]}}`)
+		default:
+			fmt.Fprint(w, `{"results": {"bindings": []}}`)
+		}
+	}))
+	defer server.Close()
+
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	source := sources.NewWikidataSource("TestAgent/1.0 (test@example.com)")
+	source.APIClient.BaseURL = server.URL
+	source.APIClient.RateLimiter.SetLimit(100)
+
+	enricher := NewWikidataEnricher(db, source)
+	stats, err := enricher.Run(ctx, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Checked != 3 || stats.Matched != 1 || stats.Updated != 1 {
+		t.Errorf("Expected 3 checked, 1 matched and 1 updated, got %+v", stats)
+	}
+
+	var apple repository.Company
+	if err := db.Get(&apple, "SELECT * FROM companies WHERE name = 'Apple'"); err != nil {
+		t.Fatalf("Failed to load Apple: %v", err)
+	}
+	if apple.Website.String != "https://www.apple.com" || apple.Domain.String != "apple.com" || apple.EmployeeCount.Int64 != 164000 || apple.FoundedYear.Int64 != 1976 {
+		t.Errorf("Expected Apple to be enriched, got %+v", apple)
+	}
+	if apple.Industry.String != "Technology" {
+		t.Errorf("Expected existing industry to be kept, got '%s'", apple.Industry.String)
+	}
+
+	ids, _ := repository.NewIdentifierRepository(db).CompanyIDs(ctx, repository.SchemeWikidata, "Q312")
+	if len(ids) != 1 || ids[0] != apple.ID {
+		t.Errorf("Expected Q312 attached to Apple, got %v", ids)
+	}
+
+	provenance, err := repository.NewProvenanceRepository(db).ListByCompanies(ctx, []int64{apple.ID})
+	if err != nil {
+		t.Fatalf("Failed to load provenance: %v", err)
+	}
+	filled := make(map[string]string)
+	for _, p := range provenance {
+		filled[p.Field] = p.SourceRecordID.String
+	}
+	if filled["website"] != "wikidata_Q312" || filled["founded_year"] != "wikidata_Q312" {
+		t.Errorf("Expected provenance of filled fields from wikidata_Q312, got %v", filled)
+	}
+	if _, ok := filled["industry"]; ok {
+		t.Errorf("Expected no provenance for the kept industry, got %v", filled)
+	}
+
+	// Companies without a match are skipped until RetryAfter has passed
+	if stats, _ := enricher.Run(ctx, 10); stats.Checked != 0 {
+		t.Errorf("Expected nothing checked on second run, got %+v", stats)
+	}
+	enricher.now = func() time.Time { return time.Now().Add(DefaultLookupRetry + time.Hour) }
+	if stats, _ := enricher.Run(ctx, 10); stats.Checked != 2 {
+		t.Errorf("Expected the 2 unmatched companies checked after the retry period, got %+v", stats)
+	}
+}
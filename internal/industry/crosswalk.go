@@ -0,0 +1,76 @@
+package industry
+
+// usSIC maps prefixes of US SIC (1987) codes, as used by SEC EDGAR, to
+// taxonomy divisions. Major groups are listed by their two digits and
+// industry groups that fall into another division by three or four.
+var usSIC = map[string]string{
+	"01": "01", "02": "01", "07": "01", "08": "02", "09": "03",
+	"10": "07", "12": "05", "13": "06", "138": "09", "14": "08",
+	"15": "41", "16": "42", "17": "43",
+	"20": "10", "208": "11", "21": "12", "22": "13", "23": "14",
+	"24": "16", "25": "31", "26": "17", "27": "58", "275": "18",
+	"276": "18", "277": "18", "278": "18", "279": "18", "28": "20",
+	"283": "21", "29": "19", "30": "22", "31": "15", "32": "23",
+	"33": "24", "34": "25", "35": "28", "357": "26", "36": "27",
+	"366": "26", "367": "26", "37": "29", "372": "30", "373": "30",
+	"374": "30", "375": "30", "376": "30", "379": "30", "38": "26",
+	"384": "32", "385": "32", "39": "32",
+	"40": "49", "41": "49", "42": "49", "422": "52", "43": "53",
+	"44": "50", "45": "51", "46": "49", "47": "52", "472": "79",
+	"48": "61", "483": "60", "49": "35", "494": "36", "495": "38",
+	"50": "46", "51": "46", "52": "47", "53": "47", "54": "47",
+	"55": "45", "554": "47", "56": "47", "57": "47", "58": "56",
+	"59": "47",
+	"60": "64", "61": "64", "62": "66", "63": "65", "64": "66",
+	"65": "68", "67": "64",
+	"70": "55", "72": "96", "73": "82", "731": "73", "734": "81",
+	"735": "77", "736": "78", "737": "62", "7374": "63", "7375": "63",
+	"7381": "80", "7382": "80", "75": "45", "751": "77", "76": "95",
+	"78": "59", "79": "93", "80": "86", "805": "87", "81": "69",
+	"82": "85", "83": "88", "836": "87", "84": "91", "86": "94",
+	"87": "71", "872": "69", "873": "72", "874": "70", "88": "97",
+	"89": "74",
+	"91": "84", "92": "84", "93": "84", "94": "84", "95": "84",
+	"96": "84", "97": "84",
+}
+
+// naics maps prefixes of NAICS codes to taxonomy divisions. Sectors are
+// listed by their two digits and subsectors or industry groups that fall
+// into another division by three to five.
+var naics = map[string]string{
+	"11": "01", "113": "02", "114": "03", "1153": "02",
+	"21": "08", "211": "06", "2121": "05", "2122": "07", "213": "09",
+	"22": "35", "2213": "36",
+	"23": "43", "236": "41", "237": "42",
+	"311": "10", "312": "11", "3122": "12", "313": "13", "314": "13",
+	"315": "14", "316": "15", "321": "16", "322": "17", "323": "18",
+	"324": "19", "325": "20", "3254": "21", "326": "22", "327": "23",
+	"331": "24", "332": "25", "333": "28", "334": "26", "335": "27",
+	"336": "29", "3364": "30", "3365": "30", "3366": "30", "3369": "30",
+	"337": "31", "339": "32",
+	"42": "46", "4231": "45", "44": "47", "45": "47", "441": "45",
+	"481": "51", "482": "49", "483": "50", "484": "49", "485": "49",
+	"486": "49", "487": "49", "488": "52", "491": "53", "492": "53",
+	"493": "52",
+	"51":  "63", "511": "58", "512": "59", "513": "58", "515": "60",
+	"516": "60", "517": "61",
+	"52": "64", "523": "66", "524": "65", "5242": "66", "5251": "65",
+	"53": "68", "532": "77", "533": "77",
+	"54": "74", "5411": "69", "5412": "69", "5413": "71", "5415": "62",
+	"5416": "70", "5417": "72", "5418": "73", "54191": "73", "54194": "75",
+	"55": "70",
+	"56": "82", "5612": "81", "5613": "78", "5615": "79", "5616": "80",
+	"5617": "81", "562": "38",
+	"61": "85",
+	"62": "86", "623": "87", "624": "88",
+	"71": "93", "711": "90", "712": "91", "7132": "92",
+	"72": "56", "721": "55",
+	"81": "96", "811": "95", "8111": "45", "813": "94", "814": "97",
+	"92": "84",
+}
+
+// ukSICExcluded are UK SIC 2007 codes that describe the state of a company
+// rather than its activity
+var ukSICExcluded = map[string]bool{
+	"99999": true, // Dormant company
+}
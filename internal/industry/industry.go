@@ -0,0 +1,118 @@
+// Package industry maps industry codes of the UK SIC 2007, US SIC, NAICS and
+// NACE Rev. 2 schemes onto a unified two-level taxonomy: the sections and
+// divisions of NACE Rev. 2.
+package industry
+
+import (
+	"strings"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// Levels of the taxonomy
+const (
+	LevelSection  = 1
+	LevelDivision = 2
+)
+
+// Category is a node of the taxonomy. Sections are coded by a letter and
+// divisions by two digits.
+type Category struct {
+	Code   string
+	Name   string
+	Level  int
+	Parent string
+}
+
+// categories indexes the taxonomy by code
+var categories = func() map[string]Category {
+	index := make(map[string]Category, len(sections)+len(divisions))
+	for _, c := range sections {
+		c.Level = LevelSection
+		index[c.Code] = c
+	}
+	for _, c := range divisions {
+		c.Level = LevelDivision
+		index[c.Code] = c
+	}
+	return index
+}()
+
+// Get returns the category with a taxonomy code
+func Get(code string) (Category, bool) {
+	c, ok := categories[code]
+	return c, ok
+}
+
+// Path returns the category with a taxonomy code and its ancestors, top
+// level first
+func Path(code string) []Category {
+	var path []Category
+	for c, ok := categories[code]; ok; c, ok = categories[c.Parent] {
+		path = append([]Category{c}, path...)
+	}
+	return path
+}
+
+// Map returns the division an industry code of a scheme belongs to
+func Map(scheme, code string) (Category, bool) {
+	digits := digitsOf(code)
+
+	var division string
+	switch scheme {
+	case model.ClassificationUKSIC, model.ClassificationNACE:
+		if len(digits) < 2 || ukSICExcluded[digits] {
+			return Category{}, false
+		}
+		division = digits[:2]
+	case model.ClassificationUSSIC:
+		division = longestPrefix(usSIC, digits)
+	case model.ClassificationNAICS:
+		division = longestPrefix(naics, digits)
+	}
+
+	c, ok := categories[division]
+	if !ok || c.Level != LevelDivision {
+		return Category{}, false
+	}
+	return c, true
+}
+
+// Classify returns the distinct divisions of codes in the order of the codes
+// they were mapped from, skipping codes that are not in the crosswalks
+func Classify(codes []model.IndustryCode) []Category {
+	var result []Category
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		c, ok := Map(code.Scheme, code.Code)
+		if !ok || seen[c.Code] {
+			continue
+		}
+		seen[c.Code] = true
+		result = append(result, c)
+	}
+	return result
+}
+
+// digitsOf drops the section letter, dots and spaces of a code such as
+// "J62.01"
+func digitsOf(code string) string {
+	var b strings.Builder
+	for _, r := range code {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// longestPrefix returns the division of the longest prefix of code listed in
+// crosswalk
+func longestPrefix(crosswalk map[string]string, code string) string {
+	for n := len(code); n >= 2; n-- {
+		if division, ok := crosswalk[code[:n]]; ok {
+			return division
+		}
+	}
+	return ""
+}
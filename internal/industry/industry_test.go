@@ -0,0 +1,79 @@
+package industry
+
+import (
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+func TestMap(t *testing.T) {
+	tests := []struct {
+		scheme, code string
+		want         string
+	}{
+		{model.ClassificationUKSIC, "62020", "62"},
+		{model.ClassificationUKSIC, "99999", ""},
+		{model.ClassificationNACE, "J62.01", "62"},
+		{model.ClassificationNACE, "C10.1", "10"},
+		{model.ClassificationUSSIC, "7372", "62"},
+		{model.ClassificationUSSIC, "7374", "63"},
+		{model.ClassificationUSSIC, "2834", "21"},
+		{model.ClassificationUSSIC, "2800", "20"},
+		{model.ClassificationUSSIC, "9995", ""},
+		{model.ClassificationNAICS, "541511", "62"},
+		{model.ClassificationNAICS, "541940", "75"},
+		{model.ClassificationNAICS, "325412", "21"},
+		{model.ClassificationNAICS, "722511", "56"},
+		{"unknown", "62020", ""},
+	}
+
+	for _, tt := range tests {
+		c, ok := Map(tt.scheme, tt.code)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("Expected %s %s to be unmapped, got %s", tt.scheme, tt.code, c.Code)
+			}
+			continue
+		}
+		if !ok || c.Code != tt.want || c.Level != LevelDivision {
+			t.Errorf("Expected %s %s to map to division %s, got %+v", tt.scheme, tt.code, tt.want, c)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	path := Path("62")
+	if len(path) != 2 || path[0].Code != "J" || path[0].Level != LevelSection || path[1].Code != "62" {
+		t.Errorf("Expected path J > 62, got %+v", path)
+	}
+	if path := Path("XX"); len(path) != 0 {
+		t.Errorf("Expected no path for unknown code, got %+v", path)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	categories := Classify([]model.IndustryCode{
+		{Scheme: model.ClassificationUKSIC, Code: "62020"},
+		{Scheme: model.ClassificationUKSIC, Code: "62090"},
+		{Scheme: model.ClassificationUKSIC, Code: "99999"},
+		{Scheme: model.ClassificationUKSIC, Code: "70229"},
+	})
+	if len(categories) != 2 || categories[0].Code != "62" || categories[1].Code != "70" {
+		t.Errorf("Expected divisions 62 and 70, got %+v", categories)
+	}
+}
+
+func TestCrosswalks(t *testing.T) {
+	for _, crosswalk := range []map[string]string{usSIC, naics} {
+		for prefix, division := range crosswalk {
+			if c, ok := Get(division); !ok || c.Level != LevelDivision {
+				t.Errorf("Expected prefix %s to map to a division, got %s", prefix, division)
+			}
+		}
+	}
+	for _, c := range divisions {
+		if s, ok := Get(c.Parent); !ok || s.Level != LevelSection {
+			t.Errorf("Expected division %s to have a section parent, got %s", c.Code, c.Parent)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package industry
+
+// sections are the top level of the taxonomy, the sections of NACE Rev. 2
+var sections = []Category{
+	{Code: "A", Name: "Agriculture, forestry and fishing"},
+	{Code: "B", Name: "Mining and quarrying"},
+	{Code: "C", Name: "Manufacturing"},
+	{Code: "D", Name: "Electricity, gas, steam and air conditioning supply"},
+	{Code: "E", Name: "Water supply; sewerage, waste management and remediation activities"},
+	{Code: "F", Name: "Construction"},
+	{Code: "G", Name: "Wholesale and retail trade; repair of motor vehicles and motorcycles"},
+	{Code: "H", Name: "Transportation and storage"},
+	{Code: "I", Name: "Accommodation and food service activities"},
+	{Code: "J", Name: "Information and communication"},
+	{Code: "K", Name: "Financial and insurance activities"},
+	{Code: "L", Name: "Real estate activities"},
+	{Code: "M", Name: "Professional, scientific and technical activities"},
+	{Code: "N", Name: "Administrative and support service activities"},
+	{Code: "O", Name: "Public administration and defence; compulsory social security"},
+	{Code: "P", Name: "Education"},
+	{Code: "Q", Name: "Human health and social work activities"},
+	{Code: "R", Name: "Arts, entertainment and recreation"},
+	{Code: "S", Name: "Other service activities"},
+	{Code: "T", Name: "Activities of households as employers; undifferentiated goods- and services-producing activities of households for own use"},
+	{Code: "U", Name: "Activities of extraterritorial organisations and bodies"},
+}
+
+// divisions are the second level of the taxonomy, the two-digit divisions
+// of NACE Rev. 2 (and of UK SIC 2007, which extends NACE)
+var divisions = []Category{
+	{Code: "01", Parent: "A", Name: "Crop and animal production, hunting and related service activities"},
+	{Code: "02", Parent: "A", Name: "Forestry and logging"},
+	{Code: "03", Parent: "A", Name: "Fishing and aquaculture"},
+	{Code: "05", Parent: "B", Name: "Mining of coal and lignite"},
+	{Code: "06", Parent: "B", Name: "Extraction of crude petroleum and natural gas"},
+	{Code: "07", Parent: "B", Name: "Mining of metal ores"},
+	{Code: "08", Parent: "B", Name: "Other mining and quarrying"},
+	{Code: "09", Parent: "B", Name: "Mining support service activities"},
+	{Code: "10", Parent: "C", Name: "Manufacture of food products"},
+	{Code: "11", Parent: "C", Name: "Manufacture of beverages"},
+	{Code: "12", Parent: "C", Name: "Manufacture of tobacco products"},
+	{Code: "13", Parent: "C", Name: "Manufacture of textiles"},
+	{Code: "14", Parent: "C", Name: "Manufacture of wearing apparel"},
+	{Code: "15", Parent: "C", Name: "Manufacture of leather and related products"},
+	{Code: "16", Parent: "C", Name: "Manufacture of wood and of products of wood and cork, except furniture"},
+	{Code: "17", Parent: "C", Name: "Manufacture of paper and paper products"},
+	{Code: "18", Parent: "C", Name: "Printing and reproduction of recorded media"},
+	{Code: "19", Parent: "C", Name: "Manufacture of coke and refined petroleum products"},
+	{Code: "20", Parent: "C", Name: "Manufacture of chemicals and chemical products"},
+	{Code: "21", Parent: "C", Name: "Manufacture of basic pharmaceutical products and pharmaceutical preparations"},
+	{Code: "22", Parent: "C", Name: "Manufacture of rubber and plastic products"},
+	{Code: "23", Parent: "C", Name: "Manufacture of other non-metallic mineral products"},
+	{Code: "24", Parent: "C", Name: "Manufacture of basic metals"},
+	{Code: "25", Parent: "C", Name: "Manufacture of fabricated metal products, except machinery and equipment"},
+	{Code: "26", Parent: "C", Name: "Manufacture of computer, electronic and optical products"},
+	{Code: "27", Parent: "C", Name: "Manufacture of electrical equipment"},
+	{Code: "28", Parent: "C", Name: "Manufacture of machinery and equipment n.e.c."},
+	{Code: "29", Parent: "C", Name: "Manufacture of motor vehicles, trailers and semi-trailers"},
+	{Code: "30", Parent: "C", Name: "Manufacture of other transport equipment"},
+	{Code: "31", Parent: "C", Name: "Manufacture of furniture"},
+	{Code: "32", Parent: "C", Name: "Other manufacturing"},
+	{Code: "33", Parent: "C", Name: "Repair and installation of machinery and equipment"},
+	{Code: "35", Parent: "D", Name: "Electricity, gas, steam and air conditioning supply"},
+	{Code: "36", Parent: "E", Name: "Water collection, treatment and supply"},
+	{Code: "37", Parent: "E", Name: "Sewerage"},
+	{Code: "38", Parent: "E", Name: "Waste collection, treatment and disposal activities; materials recovery"},
+	{Code: "39", Parent: "E", Name: "Remediation activities and other waste management services"},
+	{Code: "41", Parent: "F", Name: "Construction of buildings"},
+	{Code: "42", Parent: "F", Name: "Civil engineering"},
+	{Code: "43", Parent: "F", Name: "Specialised construction activities"},
+	{Code: "45", Parent: "G", Name: "Wholesale and retail trade and repair of motor vehicles and motorcycles"},
+	{Code: "46", Parent: "G", Name: "Wholesale trade, except of motor vehicles and motorcycles"},
+	{Code: "47", Parent: "G", Name: "Retail trade, except of motor vehicles and motorcycles"},
+	{Code: "49", Parent: "H", Name: "Land transport and transport via pipelines"},
+	{Code: "50", Parent: "H", Name: "Water transport"},
+	{Code: "51", Parent: "H", Name: "Air transport"},
+	{Code: "52", Parent: "H", Name: "Warehousing and support activities for transportation"},
+	{Code: "53", Parent: "H", Name: "Postal and courier activities"},
+	{Code: "55", Parent: "I", Name: "Accommodation"},
+	{Code: "56", Parent: "I", Name: "Food and beverage service activities"},
+	{Code: "58", Parent: "J", Name: "Publishing activities"},
+	{Code: "59", Parent: "J", Name: "Motion picture, video and television programme production, sound recording and music publishing activities"},
+	{Code: "60", Parent: "J", Name: "Programming and broadcasting activities"},
+	{Code: "61", Parent: "J", Name: "Telecommunications"},
+	{Code: "62", Parent: "J", Name: "Computer programming, consultancy and related activities"},
+	{Code: "63", Parent: "J", Name: "Information service activities"},
+	{Code: "64", Parent: "K", Name: "Financial service activities, except insurance and pension funding"},
+	{Code: "65", Parent: "K", Name: "Insurance, reinsurance and pension funding, except compulsory social security"},
+	{Code: "66", Parent: "K", Name: "Activities auxiliary to financial services and insurance activities"},
+	{Code: "68", Parent: "L", Name: "Real estate activities"},
+	{Code: "69", Parent: "M", Name: "Legal and accounting activities"},
+	{Code: "70", Parent: "M", Name: "Activities of head offices; management consultancy activities"},
+	{Code: "71", Parent: "M", Name: "Architectural and engineering activities; technical testing and analysis"},
+	{Code: "72", Parent: "M", Name: "Scientific research and development"},
+	{Code: "73", Parent: "M", Name: "Advertising and market research"},
+	{Code: "74", Parent: "M", Name: "Other professional, scientific and technical activities"},
+	{Code: "75", Parent: "M", Name: "Veterinary activities"},
+	{Code: "77", Parent: "N", Name: "Rental and leasing activities"},
+	{Code: "78", Parent: "N", Name: "Employment activities"},
+	{Code: "79", Parent: "N", Name: "Travel agency, tour operator and other reservation service and related activities"},
+	{Code: "80", Parent: "N", Name: "Security and investigation activities"},
+	{Code: "81", Parent: "N", Name: "Services to buildings and landscape activities"},
+	{Code: "82", Parent: "N", Name: "Office administrative, office support and other business support activities"},
+	{Code: "84", Parent: "O", Name: "Public administration and defence; compulsory social security"},
+	{Code: "85", Parent: "P", Name: "Education"},
+	{Code: "86", Parent: "Q", Name: "Human health activities"},
+	{Code: "87", Parent: "Q", Name: "Residential care activities"},
+	{Code: "88", Parent: "Q", Name: "Social work activities without accommodation"},
+	{Code: "90", Parent: "R", Name: "Creative, arts and entertainment activities"},
+	{Code: "91", Parent: "R", Name: "Libraries, archives, museums and other cultural activities"},
+	{Code: "92", Parent: "R", Name: "Gambling and betting activities"},
+	{Code: "93", Parent: "R", Name: "Sports activities and amusement and recreation activities"},
+	{Code: "94", Parent: "S", Name: "Activities of membership organisations"},
+	{Code: "95", Parent: "S", Name: "Repair of computers and personal and household goods"},
+	{Code: "96", Parent: "S", Name: "Other personal service activities"},
+	{Code: "97", Parent: "T", Name: "Activities of households as employers of domestic personnel"},
+	{Code: "98", Parent: "T", Name: "Undifferentiated goods- and services-producing activities of private households for own use"},
+	{Code: "99", Parent: "U", Name: "Activities of extraterritorial organisations and bodies"},
+}
@@ -0,0 +1,175 @@
+package mapping
+
+import (
+	"strings"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/address"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// Conventional maps records that use the field names of the Companies House
+// layout: name, company_number, jurisdiction, company_status, company_type,
+// date_of_creation or incorporation_date, website, phone, industry,
+// sic_codes (UK SIC 2007), naics_codes, nace_codes, employee_count,
+// founded_year, an address object with address_line_1, address_line_2,
+// locality, region, postal_code and country (or a single address string),
+// a company email and contacts objects with name, job_title, department,
+// email and phone. Generic REST sources can target these names to be mapped
+// without code.
+func Conventional(record api.RawRecord) (model.Company, error) {
+	var errs Errors
+	company := conventional(record, newReader(record.Data, &errs), "")
+	return result(company, errs)
+}
+
+// conventional maps the conventional fields, using defaultJurisdiction for
+// records that carry none
+func conventional(record api.RawRecord, r *reader, defaultJurisdiction string) model.Company {
+	company := newCompany(record)
+	company.Name = r.required("name")
+	company.LegalName = r.first("legal_name", "name")
+	company.CompanyType = r.str("company_type")
+	setStatus(&company, r.first("company_status", "status"))
+	company.Description = r.str("description")
+	company.Website = r.str("website")
+	company.Phone = r.str("phone")
+	company.Industry = r.str("industry")
+	company.IndustryCodes = industryCodes(model.ClassificationUKSIC, r.strings("sic_codes"))
+	company.IndustryCodes = append(company.IndustryCodes, industryCodes(model.ClassificationNAICS, r.strings("naics_codes"))...)
+	company.IndustryCodes = append(company.IndustryCodes, industryCodes(model.ClassificationNACE, r.strings("nace_codes"))...)
+	company.EmployeeCount = r.integer("employee_count")
+
+	company.Jurisdiction = model.NormalizeJurisdiction(r.first("jurisdiction_code", "jurisdiction"))
+	if company.Jurisdiction == "" {
+		company.Jurisdiction = defaultJurisdiction
+	}
+
+	company.IncorporationDate = r.date("date_of_creation")
+	if company.IncorporationDate == "" {
+		company.IncorporationDate = r.date("incorporation_date")
+	}
+	company.DissolutionDate = r.date("date_of_cessation")
+	if company.DissolutionDate == "" {
+		company.DissolutionDate = r.date("dissolution_date")
+	}
+	company.FoundedYear = r.integer("founded_year")
+	if company.FoundedYear == 0 {
+		company.FoundedYear = yearOf(company.IncorporationDate)
+	}
+
+	for _, previous := range r.objects("previous_names") {
+		if name := previous.str("name"); name != "" {
+			company.PreviousNames = append(company.PreviousNames, name)
+		}
+	}
+
+	if _, ok := record.Data["address"].(string); ok {
+		company.Addresses = appendAddress(company.Addresses, parseAddress(model.AddressRegistered, r.str("address"), company.Jurisdiction))
+	} else if addr := r.object("address"); addr != nil {
+		company.Addresses = appendAddress(company.Addresses, model.Address{
+			Kind:       model.AddressRegistered,
+			Line1:      addr.str("address_line_1"),
+			Line2:      addr.str("address_line_2"),
+			City:       addr.first("locality", "city"),
+			Region:     addr.str("region"),
+			PostalCode: addr.str("postal_code"),
+			Country:    addr.str("country"),
+		})
+	}
+
+	company.Contacts = appendContact(company.Contacts, model.Contact{Email: r.str("email")})
+	for _, c := range r.objects("contacts") {
+		company.Contacts = appendContact(company.Contacts, model.Contact{
+			Name:       c.str("name"),
+			JobTitle:   c.first("job_title", "title"),
+			Department: c.str("department"),
+			Email:      c.str("email"),
+			Phone:      c.str("phone"),
+		})
+	}
+
+	if number := r.str("company_number"); number != "" && company.Jurisdiction != "" {
+		company.Identifiers = append(company.Identifiers, model.Identifier{
+			Scheme: model.IdentifierRegistration,
+			Value:  model.RegistrationValue(company.Jurisdiction, number),
+		})
+	}
+
+	return company
+}
+
+// appendContact appends a contact unless it has no email or phone. Named
+// contacts are people and the others role-based contact points.
+func appendContact(contacts []model.Contact, contact model.Contact) []model.Contact {
+	if contact.IsZero() {
+		return contacts
+	}
+	contact.Kind = model.ContactPerson
+	if contact.Name == "" {
+		contact.Kind = model.ContactRole
+	}
+	return append(contacts, contact)
+}
+
+// CompaniesHouse maps company records from the Companies House REST API,
+// streaming API and bulk snapshot, which all share the conventional layout
+func CompaniesHouse(record api.RawRecord) (model.Company, error) {
+	var errs Errors
+	r := newReader(record.Data, &errs)
+
+	// The profile's jurisdiction is a UK nation such as "england-wales"
+	// rather than a country code
+	company := conventional(record, r, "")
+	company.Jurisdiction = "GB"
+	company.Identifiers = nil
+	if number := r.required("company_number"); number != "" {
+		company.Identifiers = append(company.Identifiers, model.Identifier{
+			Scheme: model.IdentifierRegistration,
+			Value:  model.RegistrationValue("GB", number),
+		})
+	}
+
+	return result(company, errs)
+}
+
+// OpenCorporates maps company search results from OpenCorporates
+func OpenCorporates(record api.RawRecord) (model.Company, error) {
+	var errs Errors
+	r := newReader(record.Data, &errs)
+
+	company := conventional(record, r, "")
+	setStatus(&company, r.str("current_status"))
+	company.DissolutionDate = r.date("inactive_date")
+	if raw := r.str("registered_address"); raw != "" {
+		company.Addresses = appendAddress(nil, parseAddress(model.AddressRegistered, raw, company.Jurisdiction))
+	}
+
+	if r.str("company_number") == "" {
+		errs = append(errs, &FieldError{Field: "company_number", Err: ErrMissing})
+	}
+
+	return result(company, errs)
+}
+
+// parseAddress splits a single-line address, keeping only the raw line when
+// the parse is uncertain. The country of the jurisdiction is assumed when
+// the line names none.
+func parseAddress(kind, raw, jurisdiction string) model.Address {
+	country, _, _ := strings.Cut(jurisdiction, "-")
+
+	addr, confidence := address.Parse(raw, country)
+	if confidence < address.MinConfidence {
+		addr = model.Address{Raw: addr.Raw}
+	}
+	addr.Kind = kind
+	return addr
+}
+
+// appendAddress appends addr unless it is empty
+func appendAddress(addresses []model.Address, addr model.Address) []model.Address {
+	if addr.IsZero() {
+		return addresses
+	}
+	return append(addresses, addr)
+}
@@ -0,0 +1,70 @@
+package mapping
+
+import (
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// EDGAR maps company submissions from SEC EDGAR
+func EDGAR(record api.RawRecord) (model.Company, error) {
+	var errs Errors
+	r := newReader(record.Data, &errs)
+
+	company := newCompany(record)
+	company.Name = r.required("name")
+	company.LegalName = company.Name
+	company.Website = r.str("website")
+	company.Phone = r.str("phone")
+	company.Industry = r.str("sic_description")
+	if sic := r.str("sic_code"); sic != "" {
+		company.IndustryCodes = industryCodes(model.ClassificationUSSIC, []string{sic})
+	}
+	for _, former := range r.objects("former_names") {
+		if name := former.str("name"); name != "" {
+			company.PreviousNames = append(company.PreviousNames, name)
+		}
+	}
+
+	// EDGAR codes US states by their postal abbreviation and other places
+	// by codes containing a digit, such as "X0" for the United Kingdom
+	if state := r.str("state_of_incorporation"); isUSState(state) {
+		company.Jurisdiction = "US-" + state
+	}
+
+	for _, kind := range []struct{ key, kind string }{
+		{"business_address", model.AddressBusiness},
+		{"mailing_address", model.AddressMailing},
+	} {
+		addr := r.object(kind.key)
+		if addr == nil {
+			continue
+		}
+
+		address := model.Address{
+			Kind:       kind.kind,
+			Line1:      addr.str("street1"),
+			Line2:      addr.str("street2"),
+			City:       addr.str("city"),
+			Region:     addr.str("state_or_country"),
+			PostalCode: addr.str("zip_code"),
+		}
+		if isUSState(address.Region) {
+			address.Country = "US"
+		}
+		company.Addresses = appendAddress(company.Addresses, address)
+	}
+
+	if cik := r.required("cik"); cik != "" {
+		company.Identifiers = append(company.Identifiers, model.Identifier{Scheme: model.IdentifierCIK, Value: cik})
+	}
+	for _, ticker := range r.strings("tickers") {
+		company.Identifiers = append(company.Identifiers, model.Identifier{Scheme: model.IdentifierTicker, Value: ticker})
+	}
+
+	return result(company, errs)
+}
+
+// isUSState reports whether code is a two-letter EDGAR state code
+func isUSState(code string) bool {
+	return len(code) == 2 && code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z'
+}
@@ -0,0 +1,65 @@
+package mapping
+
+import (
+	"strings"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// GLEIF maps LEI records from the GLEIF API
+func GLEIF(record api.RawRecord) (model.Company, error) {
+	var errs Errors
+	r := newReader(record.Data, &errs)
+
+	company := newCompany(record)
+	company.Name = r.required("name")
+	company.LegalName = company.Name
+	setStatus(&company, r.str("entity_status"))
+	company.CompanyType = r.str("legal_form_id")
+	// GLEIF records carry the ELF code, or "8888" when none applies
+	if company.CompanyType != "8888" {
+		company.LegalFormCode = company.CompanyType
+	}
+	company.Jurisdiction = model.NormalizeJurisdiction(r.str("jurisdiction"))
+	company.IncorporationDate = r.date("creation_date")
+	company.FoundedYear = yearOf(company.IncorporationDate)
+
+	for _, kind := range []struct{ key, kind string }{
+		{"legal_address", model.AddressRegistered},
+		{"headquarters_address", model.AddressHeadquarters},
+	} {
+		addr := r.object(kind.key)
+		if addr == nil {
+			continue
+		}
+
+		lines := addr.strings("address_lines")
+		address := model.Address{
+			Kind:       kind.kind,
+			City:       addr.str("city"),
+			Region:     addr.str("region"),
+			PostalCode: addr.str("postal_code"),
+			Country:    addr.str("country"),
+		}
+		if len(lines) > 0 {
+			address.Line1 = strings.TrimSpace(addr.str("address_number") + " " + lines[0])
+		}
+		if len(lines) > 1 {
+			address.Line2 = strings.Join(lines[1:], ", ")
+		}
+		company.Addresses = appendAddress(company.Addresses, address)
+	}
+
+	if lei := r.required("lei"); lei != "" {
+		company.Identifiers = append(company.Identifiers, model.Identifier{Scheme: model.IdentifierLEI, Value: lei})
+	}
+	if number := r.str("registered_as"); number != "" && company.Jurisdiction != "" {
+		company.Identifiers = append(company.Identifiers, model.Identifier{
+			Scheme: model.IdentifierRegistration,
+			Value:  model.RegistrationValue(company.Jurisdiction, number),
+		})
+	}
+
+	return result(company, errs)
+}
@@ -0,0 +1,239 @@
+// Package mapping converts raw records from data sources into the
+// canonical model.
+package mapping
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/address"
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/email"
+	"github.com/stkisengese/B2B-Data-Platform/internal/industry"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+	"github.com/stkisengese/B2B-Data-Platform/internal/names"
+	"github.com/stkisengese/B2B-Data-Platform/internal/phone"
+	"github.com/stkisengese/B2B-Data-Platform/internal/website"
+)
+
+var (
+	// ErrMissing marks a required field without a value
+	ErrMissing = errors.New("missing required value")
+	// ErrInvalid marks a field whose value cannot be converted
+	ErrInvalid = errors.New("invalid value")
+	// ErrNoMapper is returned for records of sources without a mapper
+	ErrNoMapper = errors.New("no mapper for source")
+)
+
+// FieldError reports a problem with one field of a raw record
+type FieldError struct {
+	Field string
+	Value interface{}
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf("%s: %v", e.Field, e.Err)
+	}
+	return fmt.Sprintf("%s: %v (%v)", e.Field, e.Err, e.Value)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors collects the field errors of one record. Mappers return it
+// alongside the partially mapped company, so callers decide whether a
+// record is still usable.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Has reports whether field has an error
+func (e Errors) Has(field string) bool {
+	for _, err := range e {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Mapper converts the raw records of one source into companies
+type Mapper interface {
+	Map(record api.RawRecord) (model.Company, error)
+}
+
+// MapperFunc adapts a function to the Mapper interface
+type MapperFunc func(record api.RawRecord) (model.Company, error)
+
+// Map calls f(record)
+func (f MapperFunc) Map(record api.RawRecord) (model.Company, error) {
+	return f(record)
+}
+
+// Registry selects the mapper of a record by its source
+type Registry struct {
+	mappers map[string]Mapper
+	// Fallback maps records of sources without a registered mapper, such as
+	// generic REST sources that follow the conventional field names
+	Fallback Mapper
+	// Unmapped counts the statuses that could not be normalized
+	Unmapped *StatusCounter
+	logger   *logrus.Logger
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		mappers:  make(map[string]Mapper),
+		Unmapped: NewStatusCounter(),
+		logger:   logrus.New(),
+	}
+}
+
+// DefaultRegistry creates a registry with the mappers of all built-in
+// sources and the conventional mapper as fallback
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register("companies_house", MapperFunc(CompaniesHouse))
+	r.Register("opencorporates", MapperFunc(OpenCorporates))
+	r.Register("gleif", MapperFunc(GLEIF))
+	r.Register("sec_edgar", MapperFunc(EDGAR))
+	r.Register("vies", MapperFunc(VIES))
+	r.Register("wikidata", MapperFunc(Wikidata))
+	r.Fallback = MapperFunc(Conventional)
+	return r
+}
+
+// Register sets the mapper of a source
+func (r *Registry) Register(source string, mapper Mapper) {
+	r.mappers[source] = mapper
+}
+
+// Map converts a record with the mapper of its source. The returned error
+// is an Errors value when only individual fields failed. Statuses that
+// could not be normalized are counted, and logged the first time they are
+// seen.
+func (r *Registry) Map(record api.RawRecord) (model.Company, error) {
+	mapper, ok := r.mappers[record.Source]
+	if !ok {
+		mapper = r.Fallback
+	}
+	if mapper == nil {
+		return model.Company{}, fmt.Errorf("%w %q", ErrNoMapper, record.Source)
+	}
+
+	company, err := mapper.Map(record)
+	if company.Status == model.StatusUnknown && company.StatusRaw != "" {
+		if r.Unmapped.Add(record.Source, company.StatusRaw) == 1 {
+			r.logger.WithFields(logrus.Fields{
+				"source": record.Source,
+				"status": company.StatusRaw,
+			}).Warn("Unmapped company status")
+		}
+	}
+
+	return company, err
+}
+
+// newCompany starts a company from the record's identity
+func newCompany(record api.RawRecord) model.Company {
+	return model.Company{
+		SourceID:    record.ID,
+		Source:      record.Source,
+		CollectedAt: record.CollectedAt,
+	}
+}
+
+// result standardizes the company's name and countries and returns the
+// company with the collected field errors, if any
+func result(company model.Company, errs Errors) (model.Company, error) {
+	// Countries are stored as ISO codes, so that "United Kingdom" from
+	// Companies House and "GB" from other sources compare equal
+	for i := range company.Addresses {
+		if code, ok := address.CountryCode(company.Addresses[i].Country); ok {
+			company.Addresses[i].Country = code
+		}
+	}
+
+	name := company.LegalName
+	if name == "" {
+		name = company.Name
+	}
+	if name != "" {
+		standard := names.Standardize(name, company.Jurisdiction)
+		company.DisplayName = standard.Display
+		company.MatchKey = standard.MatchKey
+		company.LegalForm = standard.LegalForm
+		if company.LegalFormCode == "" {
+			company.LegalFormCode = standard.ELFCode
+		}
+	}
+
+	if company.Website != "" {
+		if site, err := website.Parse(company.Website); err == nil {
+			company.Website = site.URL
+			company.Domain = site.Domain
+		}
+	}
+
+	// Phone numbers are stored in E.164 format; numbers that cannot be
+	// parsed are kept as written and flagged
+	if company.Phone != "" {
+		if number, err := phone.Parse(company.Phone, company.Country()); err == nil {
+			company.Phone = number.E164
+			company.PhoneType = number.Type
+			company.PhoneValid = true
+		}
+	}
+
+	// Contact emails and phones are normalized like the company's own;
+	// values that cannot be parsed are kept as written
+	for i := range company.Contacts {
+		contact := &company.Contacts[i]
+		if addr, err := email.Parse(contact.Email); err == nil {
+			contact.Email = addr.Email
+			if contact.Kind == model.ContactRole && contact.Department == "" && email.IsRoleAccount(addr.Local) {
+				contact.Department, _, _ = strings.Cut(strings.ToLower(addr.Local), "+")
+			}
+		}
+		if contact.Phone != "" {
+			if number, err := phone.Parse(contact.Phone, company.Country()); err == nil {
+				contact.Phone = number.E164
+			}
+		}
+	}
+
+	// The industry is named after the taxonomy division of the first
+	// classified code, falling back to the source's own text
+	for _, category := range industry.Classify(company.IndustryCodes) {
+		if len(company.Categories) == 0 {
+			company.Industry = category.Name
+		}
+		company.Categories = append(company.Categories, category.Code)
+	}
+
+	if len(errs) > 0 {
+		return company, errs
+	}
+	return company, nil
+}
+
+// industryCodes pairs codes with their classification scheme
+func industryCodes(scheme string, codes []string) []model.IndustryCode {
+	var result []model.IndustryCode
+	for _, code := range codes {
+		result = append(result, model.IndustryCode{Scheme: scheme, Code: code})
+	}
+	return result
+}
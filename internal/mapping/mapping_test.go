@@ -0,0 +1,305 @@
+package mapping
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+	"github.com/stkisengese/B2B-Data-Platform/internal/phone"
+)
+
+func TestCompaniesHouse(t *testing.T) {
+	company, err := CompaniesHouse(api.RawRecord{
+		ID:     "ch_12345678",
+		Source: "companies_house",
+		Data: map[string]interface{}{
+			"company_number":   "12345678",
+			"name":             "TEST COMPANY LIMITED",
+			"company_status":   "active",
+			"jurisdiction":     "england-wales",
+			"date_of_creation": "2015-03-01",
+			"sic_codes":        []interface{}{"62020", ""},
+			"address": map[string]interface{}{
+				"address_line_1": "1 Test Street",
+				"locality":       "London",
+				"postal_code":    "EC1A 1BB",
+				"country":        "United Kingdom",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if company.Jurisdiction != "GB" {
+		t.Errorf("Expected jurisdiction 'GB', got '%s'", company.Jurisdiction)
+	}
+	if company.FoundedYear != 2015 {
+		t.Errorf("Expected founded year 2015, got %d", company.FoundedYear)
+	}
+	if len(company.IndustryCodes) != 1 || company.IndustryCodes[0] != (model.IndustryCode{Scheme: model.ClassificationUKSIC, Code: "62020"}) {
+		t.Errorf("Expected UK SIC codes [62020], got %v", company.IndustryCodes)
+	}
+	if len(company.Categories) != 1 || company.Categories[0] != "62" {
+		t.Errorf("Expected category 62, got %v", company.Categories)
+	}
+	if company.Industry != "Computer programming, consultancy and related activities" {
+		t.Errorf("Expected industry of division 62, got '%s'", company.Industry)
+	}
+	if id, ok := company.Identifier(model.IdentifierRegistration); !ok || id != "GB:12345678" {
+		t.Errorf("Expected registration 'GB:12345678', got '%s'", id)
+	}
+
+	addr, ok := company.PrimaryAddress()
+	if !ok || addr.City != "London" || addr.Kind != model.AddressRegistered {
+		t.Errorf("Expected registered address in London, got %+v", addr)
+	}
+	if addr.Country != "GB" {
+		t.Errorf("Expected country 'GB', got '%s'", addr.Country)
+	}
+}
+
+func TestCompaniesHouse_FieldErrors(t *testing.T) {
+	company, err := CompaniesHouse(api.RawRecord{
+		ID:     "ch_",
+		Source: "companies_house",
+		Data: map[string]interface{}{
+			"name":             "TEST COMPANY LIMITED",
+			"date_of_creation": "not a date",
+			"employee_count":   "many",
+		},
+	})
+
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected field errors, got %v", err)
+	}
+	for _, field := range []string{"company_number", "date_of_creation", "employee_count"} {
+		if !errs.Has(field) {
+			t.Errorf("Expected error for %s, got %v", field, errs)
+		}
+	}
+	if !errors.Is(errs[0], ErrMissing) && !errors.Is(errs[0], ErrInvalid) {
+		t.Errorf("Expected ErrMissing or ErrInvalid, got %v", errs[0])
+	}
+
+	// The fields that could be read are still mapped
+	if company.Name != "TEST COMPANY LIMITED" {
+		t.Errorf("Expected name to be mapped, got '%s'", company.Name)
+	}
+}
+
+func TestGLEIF(t *testing.T) {
+	company, err := GLEIF(api.RawRecord{
+		ID:     "gleif_213800EXAMPLE0000001",
+		Source: "gleif",
+		Data: map[string]interface{}{
+			"lei":           "213800EXAMPLE0000001",
+			"name":          "TEST COMPANY LIMITED",
+			"jurisdiction":  "GB",
+			"registered_as": "12345678",
+			"legal_address": map[string]interface{}{
+				"address_lines":  []interface{}{"Test Street", "Floor 2"},
+				"address_number": "1",
+				"city":           "London",
+				"country":        "GB",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if id, _ := company.Identifier(model.IdentifierLEI); id != "213800EXAMPLE0000001" {
+		t.Errorf("Expected LEI '213800EXAMPLE0000001', got '%s'", id)
+	}
+	if id, _ := company.Identifier(model.IdentifierRegistration); id != "GB:12345678" {
+		t.Errorf("Expected registration 'GB:12345678', got '%s'", id)
+	}
+	if len(company.Addresses) != 1 || company.Addresses[0].Line1 != "1 Test Street" || company.Addresses[0].Line2 != "Floor 2" {
+		t.Errorf("Expected legal address lines, got %+v", company.Addresses)
+	}
+}
+
+func TestEDGAR(t *testing.T) {
+	company, err := EDGAR(api.RawRecord{
+		ID:     "edgar_0000320193",
+		Source: "sec_edgar",
+		Data: map[string]interface{}{
+			"cik":                    "0000320193",
+			"name":                   "Apple Inc.",
+			"state_of_incorporation": "CA",
+			"tickers":                []interface{}{"AAPL"},
+			"business_address": map[string]interface{}{
+				"street1":          "One Apple Park Way",
+				"city":             "Cupertino",
+				"state_or_country": "CA",
+				"zip_code":         "95014",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if company.Jurisdiction != "US-CA" {
+		t.Errorf("Expected jurisdiction 'US-CA', got '%s'", company.Jurisdiction)
+	}
+	if id, _ := company.Identifier(model.IdentifierTicker); id != "AAPL" {
+		t.Errorf("Expected ticker 'AAPL', got '%s'", id)
+	}
+	if len(company.Addresses) != 1 || company.Addresses[0].Country != "US" {
+		t.Errorf("Expected US business address, got %+v", company.Addresses)
+	}
+}
+
+func TestRegistry_Fallback(t *testing.T) {
+	record := api.RawRecord{
+		ID:     "registry_1",
+		Source: "example_registry",
+		Data: map[string]interface{}{
+			"name":           "Example GmbH",
+			"company_number": "HRB 1",
+			"jurisdiction":   "de",
+			"address":        "Musterstraße 1, 10115 Berlin",
+		},
+	}
+
+	company, err := DefaultRegistry().Map(record)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id, _ := company.Identifier(model.IdentifierRegistration); id != "DE:HRB1" {
+		t.Errorf("Expected registration 'DE:HRB1', got '%s'", id)
+	}
+	if len(company.Addresses) != 1 || company.Addresses[0].Raw != "Musterstraße 1, 10115 Berlin" {
+		t.Errorf("Expected raw address, got %+v", company.Addresses)
+	}
+
+	if _, err := NewRegistry().Map(record); !errors.Is(err, ErrNoMapper) {
+		t.Errorf("Expected ErrNoMapper, got %v", err)
+	}
+}
+
+func TestRegistry_UnmappedStatus(t *testing.T) {
+	registry := DefaultRegistry()
+
+	for i := 0; i < 2; i++ {
+		company, _ := registry.Map(api.RawRecord{
+			ID:     "oc_gb_1",
+			Source: "opencorporates",
+			Data: map[string]interface{}{
+				"name":           "TEST LIMITED",
+				"company_number": "1",
+				"current_status": "Pending Verification",
+			},
+		})
+		if company.Status != model.StatusUnknown || company.StatusRaw != "Pending Verification" {
+			t.Errorf("Expected unknown status with raw value, got '%s' ('%s')", company.Status, company.StatusRaw)
+		}
+	}
+
+	unmapped := registry.Unmapped.List()
+	if len(unmapped) != 1 || unmapped[0].Source != "opencorporates" || unmapped[0].Count != 2 {
+		t.Errorf("Expected one unmapped status seen twice, got %+v", unmapped)
+	}
+}
+
+func TestOpenCorporates_Address(t *testing.T) {
+	company, err := OpenCorporates(api.RawRecord{
+		ID:     "oc_gb_12345678",
+		Source: "opencorporates",
+		Data: map[string]interface{}{
+			"name":               "TEST LIMITED",
+			"company_number":     "12345678",
+			"jurisdiction_code":  "gb",
+			"registered_address": "1 High Street, London, EC1A 1BB",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(company.Addresses) != 1 {
+		t.Fatalf("Expected 1 address, got %d", len(company.Addresses))
+	}
+	if addr := company.Addresses[0]; addr.City != "London" || addr.PostalCode != "EC1A 1BB" || addr.Country != "GB" {
+		t.Errorf("Expected parsed London address, got %+v", addr)
+	}
+
+	// Lines that cannot be parsed confidently are kept whole
+	company, _ = OpenCorporates(api.RawRecord{
+		ID:     "oc_gb_2",
+		Source: "opencorporates",
+		Data: map[string]interface{}{
+			"name":               "OTHER LIMITED",
+			"company_number":     "2",
+			"registered_address": "Unit 4 Somewhere",
+		},
+	})
+	if addr := company.Addresses[0]; addr.Raw != "Unit 4 Somewhere" || addr.Line1 != "" || addr.City != "" {
+		t.Errorf("Expected raw address only, got %+v", addr)
+	}
+}
+
+func TestConventional_Phone(t *testing.T) {
+	company, _ := Conventional(api.RawRecord{
+		ID:     "registry_1",
+		Source: "registry",
+		Data: map[string]interface{}{
+			"name":         "TEST LIMITED",
+			"jurisdiction": "gb",
+			"phone":        "020 7946 0000",
+		},
+	})
+	if company.Phone != "+442079460000" || company.PhoneType != phone.TypeLandline || !company.PhoneValid {
+		t.Errorf("Expected valid landline +442079460000, got '%s' (%s, valid %v)", company.Phone, company.PhoneType, company.PhoneValid)
+	}
+
+	company, _ = Conventional(api.RawRecord{
+		ID:     "registry_2",
+		Source: "registry",
+		Data: map[string]interface{}{
+			"name":         "TEST LIMITED",
+			"jurisdiction": "gb",
+			"phone":        "ask reception",
+		},
+	})
+	if company.Phone != "ask reception" || company.PhoneValid {
+		t.Errorf("Expected invalid phone kept as written, got '%s' (valid %v)", company.Phone, company.PhoneValid)
+	}
+}
+
+func TestConventional_Contacts(t *testing.T) {
+	company, _ := Conventional(api.RawRecord{
+		ID:     "registry_1",
+		Source: "registry",
+		Data: map[string]interface{}{
+			"name":         "TEST LIMITED",
+			"jurisdiction": "gb",
+			"email":        "Sales@Example.COM",
+			"contacts": []interface{}{
+				map[string]interface{}{
+					"name":      "Jane Smith",
+					"job_title": "Managing Director",
+					"email":     "jane.smith@example.com",
+					"phone":     "07700 900123",
+				},
+				map[string]interface{}{"name": "No Details"},
+			},
+		},
+	})
+
+	expected := []model.Contact{
+		{Kind: model.ContactRole, Department: "sales", Email: "Sales@example.com"},
+		{Kind: model.ContactPerson, Name: "Jane Smith", JobTitle: "Managing Director", Email: "jane.smith@example.com", Phone: "+447700900123"},
+	}
+	if len(company.Contacts) != len(expected) {
+		t.Fatalf("Expected %d contacts, got %+v", len(expected), company.Contacts)
+	}
+	for i, contact := range company.Contacts {
+		if contact != expected[i] {
+			t.Errorf("Expected contact %+v, got %+v", expected[i], contact)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package mapping
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the date formats found in source records
+var dateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"02/01/2006",
+}
+
+// reader reads typed values from a record's data, collecting an error for
+// every field that is present but cannot be converted
+type reader struct {
+	data   map[string]interface{}
+	prefix string
+	errs   *Errors
+}
+
+func newReader(data map[string]interface{}, errs *Errors) *reader {
+	return &reader{data: data, errs: errs}
+}
+
+// object returns a reader over the map stored under key, or nil when absent
+func (r *reader) object(key string) *reader {
+	value, ok := r.data[key]
+	if !ok || value == nil {
+		return nil
+	}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		r.fail(key, value, ErrInvalid)
+		return nil
+	}
+	return &reader{data: obj, prefix: r.field(key) + ".", errs: r.errs}
+}
+
+// str returns the trimmed string under key, or "" when absent
+func (r *reader) str(key string) string {
+	switch v := r.data[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case json.Number:
+		return v.String()
+	case int, int64:
+		return fmt.Sprint(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		r.fail(key, v, ErrInvalid)
+		return ""
+	}
+}
+
+// first returns the first non-empty string among keys
+func (r *reader) first(keys ...string) string {
+	for _, key := range keys {
+		if value := r.str(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// required returns the string under key, recording ErrMissing when empty
+func (r *reader) required(key string) string {
+	value := r.str(key)
+	if value == "" {
+		r.fail(key, nil, ErrMissing)
+	}
+	return value
+}
+
+// date returns the date under key as YYYY-MM-DD
+func (r *reader) date(key string) string {
+	value := r.str(key)
+	if value == "" {
+		return ""
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+
+	r.fail(key, value, ErrInvalid)
+	return ""
+}
+
+// integer returns the whole number under key, accepting numeric strings
+func (r *reader) integer(key string) int {
+	switch v := r.data[key].(type) {
+	case nil:
+		return 0
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		if v == math.Trunc(v) {
+			return int(v)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return int(n)
+		}
+	case string:
+		if v == "" {
+			return 0
+		}
+		if n, err := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(v), ",", "")); err == nil {
+			return n
+		}
+	}
+
+	r.fail(key, r.data[key], ErrInvalid)
+	return 0
+}
+
+// strings returns the non-empty strings in the list under key
+func (r *reader) strings(key string) []string {
+	var values []string
+	switch v := r.data[key].(type) {
+	case nil:
+	case []string:
+		for _, s := range v {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				r.fail(fmt.Sprintf("%s[%d]", key, i), item, ErrInvalid)
+				continue
+			}
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+	default:
+		r.fail(key, v, ErrInvalid)
+	}
+	return values
+}
+
+// objects returns readers over the maps in the list under key
+func (r *reader) objects(key string) []*reader {
+	value, ok := r.data[key]
+	if !ok || value == nil {
+		return nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		r.fail(key, value, ErrInvalid)
+		return nil
+	}
+
+	var readers []*reader
+	for i, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			r.fail(fmt.Sprintf("%s[%d]", key, i), item, ErrInvalid)
+			continue
+		}
+		readers = append(readers, &reader{data: obj, prefix: fmt.Sprintf("%s[%d].", r.field(key), i), errs: r.errs})
+	}
+	return readers
+}
+
+func (r *reader) field(key string) string {
+	return r.prefix + key
+}
+
+func (r *reader) fail(key string, value interface{}, err error) {
+	*r.errs = append(*r.errs, &FieldError{Field: r.field(key), Value: value, Err: err})
+}
+
+// yearOf returns the year of a YYYY-MM-DD date, or 0
+func yearOf(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, _ := strconv.Atoi(date[:4])
+	return year
+}
@@ -0,0 +1,67 @@
+package mapping
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// setStatus stores the reported status of a company next to its normalized
+// form
+func setStatus(company *model.Company, raw string) {
+	company.StatusRaw = raw
+	company.Status, _ = model.NormalizeStatus(raw)
+}
+
+// UnmappedStatus is a source status missing from the mapping tables
+type UnmappedStatus struct {
+	Source string
+	Value  string
+	Count  int
+}
+
+// StatusCounter counts the statuses that could not be normalized, per
+// source, so the mapping tables can be extended
+type StatusCounter struct {
+	mu     sync.Mutex
+	counts map[UnmappedStatus]int
+}
+
+// NewStatusCounter creates an empty counter
+func NewStatusCounter() *StatusCounter {
+	return &StatusCounter{counts: make(map[UnmappedStatus]int)}
+}
+
+// Add counts one occurrence of value and returns the count so far
+func (c *StatusCounter) Add(source, value string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := UnmappedStatus{Source: source, Value: value}
+	c.counts[key]++
+	return c.counts[key]
+}
+
+// List returns the unmapped statuses, most frequent first
+func (c *StatusCounter) List() []UnmappedStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	list := make([]UnmappedStatus, 0, len(c.counts))
+	for key, count := range c.counts {
+		key.Count = count
+		list = append(list, key)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		if list[i].Source != list[j].Source {
+			return list[i].Source < list[j].Source
+		}
+		return list[i].Value < list[j].Value
+	})
+	return list
+}
@@ -0,0 +1,36 @@
+package mapping
+
+import (
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// VIES maps VAT validation results. Records of invalid VAT IDs carry no
+// name and are reported as such.
+func VIES(record api.RawRecord) (model.Company, error) {
+	var errs Errors
+	r := newReader(record.Data, &errs)
+
+	company := newCompany(record)
+	if valid, _ := record.Data["valid"].(bool); !valid {
+		errs = append(errs, &FieldError{Field: "valid", Value: record.Data["valid"], Err: ErrInvalid})
+	}
+
+	company.Name = r.required("name")
+	company.LegalName = company.Name
+
+	// VIES prefixes Greek VAT IDs with EL instead of the ISO code GR
+	company.Jurisdiction = r.str("country_code")
+	if company.Jurisdiction == "EL" {
+		company.Jurisdiction = "GR"
+	}
+
+	if raw := r.str("address"); raw != "" {
+		company.Addresses = appendAddress(nil, model.Address{Kind: model.AddressRegistered, Raw: raw, Country: company.Jurisdiction})
+	}
+	if vatID := r.required("vat_id"); vatID != "" {
+		company.Identifiers = append(company.Identifiers, model.Identifier{Scheme: model.IdentifierVAT, Value: vatID})
+	}
+
+	return result(company, errs)
+}
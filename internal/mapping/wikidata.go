@@ -0,0 +1,44 @@
+package mapping
+
+import (
+	"github.com/stkisengese/B2B-Data-Platform/internal/api"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// Wikidata maps company items from the Wikidata Query Service
+func Wikidata(record api.RawRecord) (model.Company, error) {
+	var errs Errors
+	r := newReader(record.Data, &errs)
+
+	company := newCompany(record)
+	company.Name = r.required("name")
+	company.Description = r.str("description")
+	company.Website = r.str("website")
+	company.FoundedYear = r.integer("founded_year")
+	company.EmployeeCount = r.integer("employee_count")
+	if industries := r.objects("industries"); len(industries) > 0 {
+		company.Industry = industries[0].str("label")
+	}
+
+	identifiers := []struct{ key, scheme string }{
+		{"qid", model.IdentifierWikidata},
+		{"lei", model.IdentifierLEI},
+		{"cik", model.IdentifierCIK},
+	}
+	for _, id := range identifiers {
+		if value := r.str(id.key); value != "" {
+			company.Identifiers = append(company.Identifiers, model.Identifier{Scheme: id.scheme, Value: value})
+		}
+	}
+	if number := r.str("uk_company_number"); number != "" {
+		company.Identifiers = append(company.Identifiers, model.Identifier{
+			Scheme: model.IdentifierRegistration,
+			Value:  model.RegistrationValue("GB", number),
+		})
+	}
+	for _, ticker := range r.strings("tickers") {
+		company.Identifiers = append(company.Identifiers, model.Identifier{Scheme: model.IdentifierTicker, Value: ticker})
+	}
+
+	return result(company, errs)
+}
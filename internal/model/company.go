@@ -0,0 +1,172 @@
+// Package model defines the canonical shape that records from every data
+// source are mapped into before they are stored, served or exported.
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Address kinds
+const (
+	AddressRegistered   = "registered"
+	AddressHeadquarters = "headquarters"
+	AddressBusiness     = "business"
+	AddressMailing      = "mailing"
+)
+
+// Identifier schemes
+const (
+	IdentifierRegistration = "registration"
+	IdentifierLEI          = "lei"
+	IdentifierVAT          = "vat"
+	IdentifierCIK          = "cik"
+	IdentifierWikidata     = "wikidata"
+	IdentifierTicker       = "ticker"
+)
+
+// Industry classification schemes
+const (
+	ClassificationUKSIC = "uk_sic_2007"
+	ClassificationUSSIC = "us_sic"
+	ClassificationNAICS = "naics"
+	ClassificationNACE  = "nace_rev2"
+)
+
+// Company is a company as described by one source record
+type Company struct {
+	// SourceID is the ID of the record the company was mapped from, e.g.
+	// "ch_00445790"
+	SourceID string `json:"source_id"`
+	Source   string `json:"source"`
+
+	Name          string   `json:"name"`
+	LegalName     string   `json:"legal_name,omitempty"`
+	PreviousNames []string `json:"previous_names,omitempty"`
+	CompanyType   string   `json:"company_type,omitempty"`
+	// DisplayName is the standardized name, e.g. "Test Company Ltd"; MatchKey
+	// compares equal for spellings of the same name
+	DisplayName string `json:"display_name,omitempty"`
+	MatchKey    string `json:"match_key,omitempty"`
+	// LegalForm is the abbreviated legal form and LegalFormCode its ISO
+	// 20275 ELF code
+	LegalForm     string `json:"legal_form,omitempty"`
+	LegalFormCode string `json:"legal_form_code,omitempty"`
+	// Status is one of the normalized statuses; StatusRaw keeps the value
+	// the source reported
+	Status    string `json:"status,omitempty"`
+	StatusRaw string `json:"status_raw,omitempty"`
+	// Jurisdiction is an ISO 3166 code, optionally with a subdivision,
+	// e.g. "GB" or "US-DE"
+	Jurisdiction string `json:"jurisdiction,omitempty"`
+
+	// Dates are formatted as YYYY-MM-DD
+	IncorporationDate string `json:"incorporation_date,omitempty"`
+	DissolutionDate   string `json:"dissolution_date,omitempty"`
+	FoundedYear       int    `json:"founded_year,omitempty"`
+
+	Description string `json:"description,omitempty"`
+	// Website is canonical when Domain, its registrable domain, is set
+	Website string `json:"website,omitempty"`
+	Domain  string `json:"domain,omitempty"`
+	// Phone is in E.164 format when PhoneValid, and as the source wrote it
+	// otherwise; PhoneType is one of the phone package's types
+	Phone         string         `json:"phone,omitempty"`
+	PhoneType     string         `json:"phone_type,omitempty"`
+	PhoneValid    bool           `json:"phone_valid,omitempty"`
+	Industry      string         `json:"industry,omitempty"`
+	IndustryCodes []IndustryCode `json:"industry_codes,omitempty"`
+	// Categories are the codes of the taxonomy divisions IndustryCodes map
+	// to, see package industry
+	Categories    []string `json:"categories,omitempty"`
+	EmployeeCount int      `json:"employee_count,omitempty"`
+
+	Addresses   []Address    `json:"addresses,omitempty"`
+	Identifiers []Identifier `json:"identifiers,omitempty"`
+	Contacts    []Contact    `json:"contacts,omitempty"`
+
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// IndustryCode is an activity code in one of the classification schemes,
+// e.g. {"uk_sic_2007", "62020"}
+type IndustryCode struct {
+	Scheme string `json:"scheme"`
+	Code   string `json:"code"`
+}
+
+// Address is a postal address of a company
+type Address struct {
+	Kind       string `json:"kind"`
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"`
+	// Raw holds the address as a single line when the source does not split
+	// it into parts
+	Raw string `json:"raw,omitempty"`
+}
+
+// Identifier is an external identifier of a company
+type Identifier struct {
+	Scheme string `json:"scheme"`
+	Value  string `json:"value"`
+}
+
+// IsZero reports whether the address has no content
+func (a Address) IsZero() bool {
+	return a.Line1 == "" && a.Line2 == "" && a.City == "" && a.Region == "" &&
+		a.PostalCode == "" && a.Country == "" && a.Raw == ""
+}
+
+// PrimaryAddress returns the registered address, or the first address when
+// there is no registered one
+func (c Company) PrimaryAddress() (Address, bool) {
+	for _, addr := range c.Addresses {
+		if addr.Kind == AddressRegistered {
+			return addr, true
+		}
+	}
+	if len(c.Addresses) > 0 {
+		return c.Addresses[0], true
+	}
+	return Address{}, false
+}
+
+// Country returns the ISO 3166 country code of the jurisdiction, or of the
+// primary address when the jurisdiction is unknown
+func (c Company) Country() string {
+	if c.Jurisdiction != "" {
+		country, _, _ := strings.Cut(c.Jurisdiction, "-")
+		return country
+	}
+	if addr, ok := c.PrimaryAddress(); ok && len(addr.Country) == 2 {
+		return strings.ToUpper(addr.Country)
+	}
+	return ""
+}
+
+// Identifier returns the first identifier of a scheme
+func (c Company) Identifier(scheme string) (string, bool) {
+	for _, id := range c.Identifiers {
+		if id.Scheme == scheme {
+			return id.Value, true
+		}
+	}
+	return "", false
+}
+
+// RegistrationValue builds the value of a registration identifier from a
+// jurisdiction code and registry number, e.g. ("gb", "00445790") becomes
+// "GB:00445790" and ("us_de", "123") becomes "US-DE:123"
+func RegistrationValue(jurisdiction, number string) string {
+	return NormalizeJurisdiction(jurisdiction) + ":" + strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(number), " ", ""))
+}
+
+// NormalizeJurisdiction converts jurisdiction codes such as "gb" or "us_de"
+// to ISO 3166 form ("GB", "US-DE")
+func NormalizeJurisdiction(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+}
@@ -0,0 +1,28 @@
+package model
+
+// Contact kinds
+const (
+	// ContactPerson is a named person at the company
+	ContactPerson = "person"
+	// ContactRole is a contact point for a function rather than a person,
+	// e.g. "sales@example.com"
+	ContactRole = "role"
+)
+
+// Contact is a person or role-based contact point of a company. Contacts
+// share the source and collection time of the company they came with.
+type Contact struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name,omitempty"`
+	JobTitle string `json:"job_title,omitempty"`
+	// Department names the function of role-based contacts, e.g. "sales"
+	Department string `json:"department,omitempty"`
+	Email      string `json:"email,omitempty"`
+	// Phone is in E.164 format when it could be parsed
+	Phone string `json:"phone,omitempty"`
+}
+
+// IsZero reports whether the contact has no way to reach it
+func (c Contact) IsZero() bool {
+	return c.Email == "" && c.Phone == ""
+}
@@ -0,0 +1,84 @@
+package model
+
+import "strings"
+
+// Normalized company statuses
+const (
+	StatusActive       = "active"
+	StatusDormant      = "dormant"
+	StatusInInsolvency = "in_insolvency"
+	StatusDissolved    = "dissolved"
+	StatusUnknown      = "unknown"
+)
+
+// statusValues maps the statuses reported by sources, lowercased and with
+// separators collapsed to single spaces, to a normalized status. Companies
+// House uses slugs such as "voluntary-arrangement", OpenCorporates free
+// text such as "In Administration/Receiver Action", GLEIF "ACTIVE" and
+// "INACTIVE".
+var statusValues = map[string]string{
+	"active":                            StatusActive,
+	"live":                              StatusActive,
+	"registered":                        StatusActive,
+	"open":                              StatusActive,
+	"good standing":                     StatusActive,
+	"in good standing":                  StatusActive,
+	"active proposal to strike off":     StatusActive,
+	"dormant":                           StatusDormant,
+	"inactive":                          StatusDormant,
+	"non trading":                       StatusDormant,
+	"liquidation":                       StatusInInsolvency,
+	"in liquidation":                    StatusInInsolvency,
+	"administration":                    StatusInInsolvency,
+	"in administration":                 StatusInInsolvency,
+	"in administration receiver action": StatusInInsolvency,
+	"receivership":                      StatusInInsolvency,
+	"receiver action":                   StatusInInsolvency,
+	"insolvency proceedings":            StatusInInsolvency,
+	"voluntary arrangement":             StatusInInsolvency,
+	"bankrupt":                          StatusInInsolvency,
+	"bankruptcy":                        StatusInInsolvency,
+	"dissolved":                         StatusDissolved,
+	"closed":                            StatusDissolved,
+	"removed":                           StatusDissolved,
+	"deleted":                           StatusDissolved,
+	"struck off":                        StatusDissolved,
+	"strike off":                        StatusDissolved,
+	"deregistered":                      StatusDissolved,
+	"merged":                            StatusDissolved,
+	"ceased":                            StatusDissolved,
+	"converted closed":                  StatusDissolved,
+}
+
+// NormalizeStatus maps a status reported by a source to one of the
+// normalized statuses. It reports false, returning StatusUnknown, when the
+// value is not in the mapping tables; an empty value maps to "".
+func NormalizeStatus(raw string) (string, bool) {
+	key := statusKey(raw)
+	if key == "" {
+		return "", true
+	}
+	if status, ok := statusValues[key]; ok {
+		return status, true
+	}
+	if status := normalizedStatus(key); status != "" {
+		return status, true
+	}
+	return StatusUnknown, false
+}
+
+// normalizedStatus accepts values that are already normalized
+func normalizedStatus(key string) string {
+	switch status := strings.ReplaceAll(key, " ", "_"); status {
+	case StatusActive, StatusDormant, StatusInInsolvency, StatusDissolved, StatusUnknown:
+		return status
+	}
+	return ""
+}
+
+// statusKey lowercases a status and collapses hyphens, underscores,
+// slashes and runs of whitespace to single spaces
+func statusKey(raw string) string {
+	replacer := strings.NewReplacer("-", " ", "_", " ", "/", " ")
+	return strings.Join(strings.Fields(strings.ToLower(replacer.Replace(raw))), " ")
+}
@@ -0,0 +1,32 @@
+package model
+
+import "testing"
+
+func TestNormalizeStatus(t *testing.T) {
+	tests := []struct {
+		raw    string
+		status string
+		ok     bool
+	}{
+		{"active", StatusActive, true},
+		{"Active", StatusActive, true},
+		{"ACTIVE", StatusActive, true},
+		{"dormant", StatusDormant, true},
+		{"liquidation", StatusInInsolvency, true},
+		{"administration", StatusInInsolvency, true},
+		{"voluntary-arrangement", StatusInInsolvency, true},
+		{"In Administration/Receiver Action", StatusInInsolvency, true},
+		{"Dissolved", StatusDissolved, true},
+		{"converted-closed", StatusDissolved, true},
+		{"in_insolvency", StatusInInsolvency, true},
+		{"", "", true},
+		{"Pending Verification", StatusUnknown, false},
+	}
+
+	for _, tt := range tests {
+		status, ok := NormalizeStatus(tt.raw)
+		if status != tt.status || ok != tt.ok {
+			t.Errorf("Expected %q to normalize to (%q, %v), got (%q, %v)", tt.raw, tt.status, tt.ok, status, ok)
+		}
+	}
+}
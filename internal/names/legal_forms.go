@@ -0,0 +1,70 @@
+package names
+
+// legalForm is a legal form as written at the end of company names
+type legalForm struct {
+	// Abbreviation is the standard way of writing the form
+	Abbreviation string
+	// Variants are the spellings of the form, compared after normalization
+	Variants []string
+	// ELF is the ISO 20275 Entity Legal Form code, empty where the form is
+	// recognised but not mapped yet
+	ELF string
+}
+
+// legalForms lists the legal forms of each country. Longer variants are
+// matched first, so "GmbH & Co. KG" is not mistaken for "KG".
+var legalForms = map[string][]legalForm{
+	"GB": {
+		{Abbreviation: "Ltd", Variants: []string{"limited", "ltd"}, ELF: "H0PO"},
+		{Abbreviation: "PLC", Variants: []string{"public limited company", "p l c", "plc"}, ELF: "B6ES"},
+		{Abbreviation: "LLP", Variants: []string{"limited liability partnership", "l l p", "llp"}},
+		{Abbreviation: "CIC", Variants: []string{"community interest company", "cic"}},
+	},
+	"IE": {
+		{Abbreviation: "Ltd", Variants: []string{"limited", "ltd", "teoranta", "teo"}},
+		{Abbreviation: "DAC", Variants: []string{"designated activity company", "dac"}},
+		{Abbreviation: "PLC", Variants: []string{"public limited company", "plc", "cpt"}},
+	},
+	"US": {
+		{Abbreviation: "Inc.", Variants: []string{"incorporated", "inc"}},
+		{Abbreviation: "Corp.", Variants: []string{"corporation", "corp"}},
+		{Abbreviation: "LLC", Variants: []string{"limited liability company", "l l c", "llc"}},
+		{Abbreviation: "LP", Variants: []string{"limited partnership", "l p", "lp"}},
+	},
+	"US-DE": {
+		{Abbreviation: "Inc.", Variants: []string{"incorporated", "inc"}, ELF: "XTIQ"},
+		{Abbreviation: "Corp.", Variants: []string{"corporation", "corp"}, ELF: "XTIQ"},
+		{Abbreviation: "LLC", Variants: []string{"limited liability company", "l l c", "llc"}, ELF: "HZEH"},
+		{Abbreviation: "LP", Variants: []string{"limited partnership", "l p", "lp"}},
+	},
+	"DE": {
+		{Abbreviation: "GmbH & Co. KG", Variants: []string{"gmbh and co kg", "gmbh co kg"}},
+		{Abbreviation: "GmbH", Variants: []string{"gesellschaft mit beschrankter haftung", "gmbh"}, ELF: "2HBR"},
+		{Abbreviation: "UG (haftungsbeschränkt)", Variants: []string{"ug haftungsbeschrankt", "ug"}},
+		{Abbreviation: "AG", Variants: []string{"aktiengesellschaft", "ag"}, ELF: "6QQB"},
+		{Abbreviation: "KG", Variants: []string{"kommanditgesellschaft", "kg"}},
+		{Abbreviation: "e.V.", Variants: []string{"eingetragener verein", "e v", "ev"}},
+	},
+	"AT": {
+		{Abbreviation: "GmbH", Variants: []string{"gesellschaft mit beschrankter haftung", "gmbh"}},
+		{Abbreviation: "AG", Variants: []string{"aktiengesellschaft", "ag"}},
+	},
+	"NL": {
+		{Abbreviation: "B.V.", Variants: []string{"besloten vennootschap", "b v", "bv"}, ELF: "54M6"},
+		{Abbreviation: "N.V.", Variants: []string{"naamloze vennootschap", "n v", "nv"}, ELF: "B5PM"},
+	},
+	"FR": {
+		{Abbreviation: "SASU", Variants: []string{"sasu"}},
+		{Abbreviation: "SAS", Variants: []string{"societe par actions simplifiee", "s a s", "sas"}},
+		{Abbreviation: "SARL", Variants: []string{"societe a responsabilite limitee", "s a r l", "sarl"}},
+		{Abbreviation: "SA", Variants: []string{"societe anonyme", "s a", "sa"}},
+	},
+	"ES": {
+		{Abbreviation: "S.L.", Variants: []string{"sociedad limitada", "s l", "sl"}},
+		{Abbreviation: "S.A.", Variants: []string{"sociedad anonima", "s a", "sa"}},
+	},
+	"IT": {
+		{Abbreviation: "S.p.A.", Variants: []string{"societa per azioni", "s p a", "spa"}},
+		{Abbreviation: "S.r.l.", Variants: []string{"societa a responsabilita limitata", "s r l", "srl"}},
+	},
+}
@@ -0,0 +1,164 @@
+// Package names standardizes company names for display and matching and
+// extracts their legal form.
+package names
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxFormWords is the largest number of words a legal form spans
+const maxFormWords = 6
+
+// countryOrder is the order in which legal form tables are searched when
+// the jurisdiction is unknown
+var countryOrder = []string{"GB", "US", "DE", "NL", "FR", "IE", "ES", "IT", "AT"}
+
+// Name is a company name split into its parts
+type Name struct {
+	// Display is the name with consistent capitalisation and the legal form
+	// abbreviated, e.g. "Test Company Ltd"
+	Display string
+	// Base is the name without its legal form
+	Base string
+	// MatchKey compares equal for names that differ only in case,
+	// punctuation, diacritics, "&"/"and" or spelling of the legal form
+	MatchKey string
+	// LegalForm is the abbreviation of the legal form, e.g. "Ltd"
+	LegalForm string
+	// ELFCode is the ISO 20275 code of the legal form in the jurisdiction
+	ELFCode string
+}
+
+// Standardize splits name for a company of jurisdiction, an ISO 3166 code
+// such as "GB" or "US-DE" that may be empty. ELF codes are only set when the
+// jurisdiction is known.
+func Standardize(name, jurisdiction string) Name {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return Name{}
+	}
+
+	form, n := findLegalForm(words, strings.ToUpper(jurisdiction))
+	base := strings.TrimRight(strings.Join(words[:len(words)-n], " "), " ,-")
+
+	result := Name{
+		Base:     titleCase(base),
+		MatchKey: MatchKey(base),
+	}
+	result.Display = result.Base
+	if form != nil {
+		result.LegalForm = form.Abbreviation
+		result.Display += " " + form.Abbreviation
+		if jurisdiction != "" {
+			result.ELFCode = form.ELF
+		}
+	}
+	return result
+}
+
+// MatchKey returns the text of name in lower case without diacritics or
+// punctuation, with "&" spelled "and" and a leading "the" removed. It does
+// not strip the legal form; use Standardize for that.
+func MatchKey(name string) string {
+	key := normalize(name)
+	key = strings.TrimPrefix(key, "the ")
+	return key
+}
+
+// findLegalForm returns the legal form that the name ends with and the
+// number of words it spans, preferring the longest match. The first word
+// is never taken as a legal form.
+func findLegalForm(words []string, jurisdiction string) (*legalForm, int) {
+	tables := formTables(jurisdiction)
+
+	for n := min(maxFormWords, len(words)-1); n > 0; n-- {
+		suffix := normalize(strings.Join(words[len(words)-n:], " "))
+		for _, table := range tables {
+			for i := range table {
+				for _, variant := range table[i].Variants {
+					if suffix == variant {
+						return &table[i], n
+					}
+				}
+			}
+		}
+	}
+	return nil, 0
+}
+
+// formTables returns the legal form tables to search for a jurisdiction:
+// its own, then its country's, or all of them when it is unknown
+func formTables(jurisdiction string) [][]legalForm {
+	var tables [][]legalForm
+	if forms, ok := legalForms[jurisdiction]; ok {
+		tables = append(tables, forms)
+	}
+	if country, _, found := strings.Cut(jurisdiction, "-"); found {
+		if forms, ok := legalForms[country]; ok {
+			tables = append(tables, forms)
+		}
+	}
+	if len(tables) == 0 {
+		for _, country := range countryOrder {
+			tables = append(tables, legalForms[country])
+		}
+	}
+	return tables
+}
+
+// normalize lowercases s, removes diacritics, spells "&" as "and" and
+// reduces punctuation to single spaces
+func normalize(s string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r == '&':
+			if !space {
+				b.WriteByte(' ')
+			}
+			b.WriteString("and ")
+			space = true
+		case r == 'ß':
+			b.WriteString("ss")
+			space = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+			space = false
+		case r == '\'' || r == '’':
+			// "Sainsbury's" and "Sainsburys" are the same name
+		default:
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// titleCase capitalises the words of names written entirely in upper or
+// lower case and leaves mixed-case names as they are
+func titleCase(s string) string {
+	if s != strings.ToUpper(s) && s != strings.ToLower(s) {
+		return s
+	}
+
+	words := strings.Fields(s)
+	for i, word := range words {
+		runes := []rune(strings.ToLower(word))
+		for j, r := range runes {
+			if unicode.IsLetter(r) {
+				runes[j] = unicode.ToUpper(r)
+				break
+			}
+		}
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,34 @@
+package names
+
+import "testing"
+
+func TestStandardize(t *testing.T) {
+	tests := []struct {
+		name, jurisdiction string
+		expected           Name
+	}{
+		{"TEST COMPANY LTD", "GB", Name{Display: "Test Company Ltd", Base: "Test Company", MatchKey: "test company", LegalForm: "Ltd", ELFCode: "H0PO"}},
+		{"Test Company Limited", "GB", Name{Display: "Test Company Ltd", Base: "Test Company", MatchKey: "test company", LegalForm: "Ltd", ELFCode: "H0PO"}},
+		{"Test Company Ltd.", "", Name{Display: "Test Company Ltd", Base: "Test Company", MatchKey: "test company", LegalForm: "Ltd"}},
+		{"Marks & Spencer Group p.l.c.", "GB", Name{Display: "Marks & Spencer Group PLC", Base: "Marks & Spencer Group", MatchKey: "marks and spencer group", LegalForm: "PLC", ELFCode: "B6ES"}},
+		{"Müller Bau GmbH & Co. KG", "DE", Name{Display: "Müller Bau GmbH & Co. KG", Base: "Müller Bau", MatchKey: "muller bau", LegalForm: "GmbH & Co. KG"}},
+		{"Apple Inc.", "US-CA", Name{Display: "Apple Inc.", Base: "Apple", MatchKey: "apple", LegalForm: "Inc."}},
+		{"The Example Company", "", Name{Display: "The Example Company", Base: "The Example Company", MatchKey: "example company"}},
+		{"Limited", "GB", Name{Display: "Limited", Base: "Limited", MatchKey: "limited"}},
+	}
+
+	for _, tt := range tests {
+		if got := Standardize(tt.name, tt.jurisdiction); got != tt.expected {
+			t.Errorf("Expected %q to standardize to %+v, got %+v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestMatchKey(t *testing.T) {
+	if a, b := MatchKey("Société Générale"), MatchKey("SOCIETE GENERALE"); a != b {
+		t.Errorf("Expected equal keys, got '%s' and '%s'", a, b)
+	}
+	if a, b := MatchKey("Johnson & Johnson"), MatchKey("Johnson and Johnson"); a != b {
+		t.Errorf("Expected equal keys, got '%s' and '%s'", a, b)
+	}
+}
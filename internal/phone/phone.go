@@ -0,0 +1,177 @@
+// Package phone normalizes phone numbers to E.164 using the numbering plan of
+// the company's country, and tells landlines, mobiles and toll-free numbers
+// apart where the plan allows it offline.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Number types
+const (
+	TypeLandline    = "landline"
+	TypeMobile      = "mobile"
+	TypeTollFree    = "toll_free"
+	TypePremiumRate = "premium_rate"
+	TypeUnknown     = "unknown"
+)
+
+var (
+	// ErrInvalid is returned for numbers that do not fit a numbering plan
+	ErrInvalid = errors.New("invalid phone number")
+	// ErrUnknownCountry is returned for national numbers when the country
+	// is missing or has no known numbering plan
+	ErrUnknownCountry = errors.New("unknown phone number country")
+)
+
+// maxDigits is the maximum length of an E.164 number without the "+"
+const maxDigits = 15
+
+// Number is a parsed phone number
+type Number struct {
+	// E164 is the number in E.164 format, e.g. "+442079460000"
+	E164 string
+	// Country is the ISO 3166 code of the numbering plan, empty when the
+	// calling code has no known plan
+	Country string
+	Type    string
+}
+
+// Parse parses a phone number written in national format for country, or in
+// international format with a "+" or "00" prefix. Extensions are dropped.
+func Parse(raw, country string) (Number, error) {
+	// Drop labels such as "Tel: " or "Tel/Fax: ", which may hold an "x"
+	text := raw
+	if i := strings.IndexAny(text, "+0123456789"); i > 0 {
+		text = text[i:]
+	}
+	text = strings.ReplaceAll(stripExtension(text), "(0)", "")
+
+	var b strings.Builder
+	plus := false
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && b.Len() == 0 && !plus:
+			plus = true
+		case strings.ContainsRune(" -./()", r):
+		default:
+			return Number{}, fmt.Errorf("%w: unexpected %q in %q", ErrInvalid, r, raw)
+		}
+	}
+	digits := b.String()
+
+	country = strings.ToUpper(strings.TrimSpace(country))
+	home, hasHome := plans[country]
+
+	international := plus
+	switch {
+	case international:
+	case strings.HasPrefix(digits, "00"):
+		digits, international = digits[2:], true
+	case hasHome && home.code == "1" && strings.HasPrefix(digits, "011"):
+		digits, international = digits[3:], true
+	}
+
+	var p plan
+	var nsn string
+	if international {
+		var ok bool
+		country, p, nsn, ok = splitCallingCode(digits, country)
+		if !ok {
+			// Calling codes without a known plan are only checked for length
+			if len(digits) < 8 || len(digits) > maxDigits {
+				return Number{}, fmt.Errorf("%w: %q has the wrong length", ErrInvalid, raw)
+			}
+			return Number{E164: "+" + digits, Type: TypeUnknown}, nil
+		}
+	} else {
+		if !hasHome {
+			return Number{}, fmt.Errorf("%w: %q", ErrUnknownCountry, raw)
+		}
+		p, nsn = home, digits
+	}
+
+	// The trunk prefix is part of national numbers and is often kept after
+	// the calling code as well, e.g. "+44 020 7946 0000"
+	if p.trunk != "" && strings.HasPrefix(nsn, p.trunk) && len(nsn)-len(p.trunk) >= p.min {
+		nsn = nsn[len(p.trunk):]
+	}
+
+	if !p.valid(nsn) || len(p.code)+len(nsn) > maxDigits {
+		return Number{}, fmt.Errorf("%w: %q does not fit the %s numbering plan", ErrInvalid, raw, country)
+	}
+
+	return Number{
+		E164:    "+" + p.code + nsn,
+		Country: country,
+		Type:    p.typeOf(nsn),
+	}, nil
+}
+
+// splitCallingCode splits an international number into the country of its
+// calling code, that country's plan and the national significant number.
+// Calling codes shared by several countries resolve to home when it is one
+// of them.
+func splitCallingCode(digits, home string) (string, plan, string, bool) {
+	for n := 1; n <= 3 && n < len(digits); n++ {
+		code := digits[:n]
+		country, ok := callingCodes[code]
+		if !ok {
+			continue
+		}
+		if p, ok := plans[home]; ok && p.code == code {
+			country = home
+		}
+		return country, plans[country], digits[n:], true
+	}
+	return "", plan{}, "", false
+}
+
+// valid reports whether nsn is a national significant number of the plan
+func (p plan) valid(nsn string) bool {
+	if len(nsn) < p.min || len(nsn) > p.max {
+		return false
+	}
+	// North American area codes and exchanges start with 2 to 9
+	if p.code == "1" {
+		return nsn[0] >= '2' && nsn[3] >= '2'
+	}
+	return true
+}
+
+// typeOf returns the type of the longest listed prefix of nsn
+func (p plan) typeOf(nsn string) string {
+	for n := len(nsn); n > 0; n-- {
+		if t, ok := p.types[nsn[:n]]; ok {
+			return t
+		}
+	}
+	return TypeUnknown
+}
+
+// stripExtension drops an extension such as " ext. 12", " x12", "#12" or
+// ";ext=12". An "x" only marks an extension after a space or a digit and
+// before digits.
+func stripExtension(raw string) string {
+	lower := strings.ToLower(raw)
+	for _, marker := range []string{"ext", "#", ";"} {
+		if i := strings.Index(lower, marker); i > 0 {
+			raw, lower = raw[:i], lower[:i]
+		}
+	}
+	for i := 1; i < len(lower); i++ {
+		if lower[i] != 'x' || (lower[i-1] != ' ' && !isDigit(lower[i-1])) {
+			continue
+		}
+		if rest := strings.TrimLeft(lower[i+1:], " ."); rest != "" && isDigit(rest[0]) {
+			return raw[:i]
+		}
+	}
+	return raw
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
@@ -0,0 +1,70 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw, country string
+		e164         string
+		numberType   string
+		numberIn     string
+	}{
+		{"020 7946 0000", "GB", "+442079460000", TypeLandline, "GB"},
+		{"+44 (0)20 7946 0000", "", "+442079460000", TypeLandline, "GB"},
+		{"07700 900123", "gb", "+447700900123", TypeMobile, "GB"},
+		{"0800 123 4567", "GB", "+448001234567", TypeTollFree, "GB"},
+		{"0044 20 7946 0000", "DE", "+442079460000", TypeLandline, "GB"},
+		{"(212) 555-0123 ext. 45", "US", "+12125550123", TypeUnknown, "US"},
+		{"Tel: 1-800-555-0199", "US", "+18005550199", TypeTollFree, "US"},
+		{"Fax: 020 7946 0000", "GB", "+442079460000", TypeLandline, "GB"},
+		{"Tel/Fax: +44 20 7946 0000", "", "+442079460000", TypeLandline, "GB"},
+		{"020 7946 0000 x123", "GB", "+442079460000", TypeLandline, "GB"},
+		{"212-555-0123x45", "US", "+12125550123", TypeUnknown, "US"},
+		{"+44 20 7946 0000;ext=12", "", "+442079460000", TypeLandline, "GB"},
+		{"+1 416 555 0123", "CA", "+14165550123", TypeUnknown, "CA"},
+		{"011 44 20 7946 0000", "US", "+442079460000", TypeLandline, "GB"},
+		{"030 1234567", "DE", "+49301234567", TypeLandline, "DE"},
+		{"0151 23456789", "DE", "+4915123456789", TypeMobile, "DE"},
+		{"06 12 34 56 78", "FR", "+33612345678", TypeMobile, "FR"},
+		{"06 1234 5678", "NL", "+31612345678", TypeMobile, "NL"},
+		{"02 1234 5678", "IT", "+390212345678", TypeLandline, "IT"},
+		{"+39 347 123 4567", "", "+393471234567", TypeMobile, "IT"},
+		{"8 800 123 45 67", "RU", "+78001234567", TypeTollFree, "RU"},
+		{"+263 24 2701234", "", "+263242701234", TypeUnknown, ""},
+	}
+
+	for _, tt := range tests {
+		number, err := Parse(tt.raw, tt.country)
+		if err != nil {
+			t.Errorf("Expected %q to parse, got %v", tt.raw, err)
+			continue
+		}
+		if number.E164 != tt.e164 || number.Type != tt.numberType || number.Country != tt.numberIn {
+			t.Errorf("Expected %q to be %s (%s, %s), got %+v", tt.raw, tt.e164, tt.numberType, tt.numberIn, number)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		raw, country string
+		err          error
+	}{
+		{"020 7946", "GB", ErrInvalid},
+		{"(112) 555-0123", "US", ErrInvalid},
+		{"call us", "GB", ErrInvalid},
+		{"1-800-FLOWERS", "US", ErrInvalid},
+		{"+44 20 7946 0000 0000", "", ErrInvalid},
+		{"020 7946 0000", "", ErrUnknownCountry},
+		{"020 7946 0000", "ZZ", ErrUnknownCountry},
+	}
+
+	for _, tt := range tests {
+		if _, err := Parse(tt.raw, tt.country); !errors.Is(err, tt.err) {
+			t.Errorf("Expected %v for %q, got %v", tt.err, tt.raw, err)
+		}
+	}
+}
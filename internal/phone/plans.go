@@ -0,0 +1,178 @@
+package phone
+
+// plan describes the numbering plan of a country: its calling code, the
+// trunk prefix dialled before national numbers, the length range of national
+// significant numbers and the number types by prefix
+type plan struct {
+	code     string
+	trunk    string
+	min, max int
+	types    map[string]string
+}
+
+// nanpTypes are the number types of the North American Numbering Plan,
+// which does not distinguish landlines from mobiles
+var nanpTypes = map[string]string{
+	"800": TypeTollFree, "833": TypeTollFree, "844": TypeTollFree, "855": TypeTollFree,
+	"866": TypeTollFree, "877": TypeTollFree, "888": TypeTollFree, "900": TypePremiumRate,
+}
+
+// plans are the numbering plans by ISO 3166 country code. Types are matched
+// on the longest prefix of the national significant number.
+var plans = map[string]plan{
+	"US": {code: "1", trunk: "1", min: 10, max: 10, types: nanpTypes},
+	"CA": {code: "1", trunk: "1", min: 10, max: 10, types: nanpTypes},
+	"GB": {code: "44", trunk: "0", min: 9, max: 10, types: map[string]string{
+		"1": TypeLandline, "2": TypeLandline, "7": TypeMobile, "70": TypeUnknown, "76": TypeUnknown,
+		"800": TypeTollFree, "808": TypeTollFree, "9": TypePremiumRate,
+	}},
+	"IE": {code: "353", trunk: "0", min: 7, max: 9, types: map[string]string{
+		"1": TypeLandline, "2": TypeLandline, "4": TypeLandline, "5": TypeLandline, "6": TypeLandline,
+		"9": TypeLandline, "8": TypeMobile, "1800": TypeTollFree, "15": TypePremiumRate,
+	}},
+	"FR": {code: "33", trunk: "0", min: 9, max: 9, types: map[string]string{
+		"1": TypeLandline, "2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeLandline,
+		"6": TypeMobile, "7": TypeMobile, "80": TypeTollFree, "89": TypePremiumRate,
+	}},
+	"DE": {code: "49", trunk: "0", min: 6, max: 13, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeLandline, "6": TypeLandline,
+		"7": TypeLandline, "8": TypeLandline, "9": TypeLandline,
+		"15": TypeMobile, "16": TypeMobile, "17": TypeMobile, "800": TypeTollFree, "900": TypePremiumRate,
+	}},
+	"NL": {code: "31", trunk: "0", min: 9, max: 9, types: map[string]string{
+		"1": TypeLandline, "2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeLandline,
+		"7": TypeLandline, "6": TypeMobile, "800": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"BE": {code: "32", trunk: "0", min: 8, max: 9, types: map[string]string{
+		"1": TypeLandline, "2": TypeLandline, "3": TypeLandline, "5": TypeLandline, "6": TypeLandline,
+		"7": TypeLandline, "8": TypeLandline, "9": TypeLandline, "4": TypeMobile,
+		"800": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"LU": {code: "352", min: 4, max: 11, types: map[string]string{
+		"6": TypeMobile, "800": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"ES": {code: "34", min: 9, max: 9, types: map[string]string{
+		"8": TypeLandline, "9": TypeLandline, "6": TypeMobile, "7": TypeMobile,
+		"900": TypeTollFree, "80": TypePremiumRate, "905": TypePremiumRate,
+	}},
+	"PT": {code: "351", min: 9, max: 9, types: map[string]string{
+		"2": TypeLandline, "9": TypeMobile, "800": TypeTollFree, "760": TypePremiumRate,
+	}},
+	"IT": {code: "39", min: 6, max: 11, types: map[string]string{
+		"0": TypeLandline, "3": TypeMobile, "800": TypeTollFree, "803": TypeTollFree, "89": TypePremiumRate,
+	}},
+	"CH": {code: "41", trunk: "0", min: 9, max: 9, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeLandline, "6": TypeLandline,
+		"8": TypeLandline, "7": TypeMobile, "800": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"AT": {code: "43", trunk: "0", min: 4, max: 13, types: map[string]string{
+		"1": TypeLandline, "2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeLandline,
+		"7": TypeLandline, "6": TypeMobile, "800": TypeTollFree, "9": TypePremiumRate,
+	}},
+	"DK": {code: "45", min: 8, max: 8, types: map[string]string{
+		"80": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"SE": {code: "46", trunk: "0", min: 7, max: 9, types: map[string]string{
+		"1": TypeLandline, "2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeLandline,
+		"6": TypeLandline, "8": TypeLandline, "9": TypeLandline, "7": TypeMobile,
+		"20": TypeTollFree, "900": TypePremiumRate,
+	}},
+	"NO": {code: "47", min: 8, max: 8, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "5": TypeLandline, "6": TypeLandline, "7": TypeLandline,
+		"4": TypeMobile, "9": TypeMobile, "800": TypeTollFree, "820": TypePremiumRate,
+	}},
+	"FI": {code: "358", trunk: "0", min: 5, max: 12, types: map[string]string{
+		"4": TypeMobile, "50": TypeMobile, "800": TypeTollFree,
+	}},
+	"PL": {code: "48", min: 9, max: 9, types: map[string]string{
+		"800": TypeTollFree, "70": TypePremiumRate,
+	}},
+	"CZ": {code: "420", min: 9, max: 9, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeLandline,
+		"6": TypeMobile, "7": TypeMobile, "800": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"GR": {code: "30", min: 10, max: 10, types: map[string]string{
+		"2": TypeLandline, "69": TypeMobile, "800": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"HU": {code: "36", trunk: "06", min: 8, max: 9, types: map[string]string{
+		"20": TypeMobile, "30": TypeMobile, "31": TypeMobile, "50": TypeMobile, "70": TypeMobile,
+		"80": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"RO": {code: "40", trunk: "0", min: 9, max: 9, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "7": TypeMobile, "800": TypeTollFree, "90": TypePremiumRate,
+	}},
+	"RU": {code: "7", trunk: "8", min: 10, max: 10, types: map[string]string{
+		"3": TypeLandline, "4": TypeLandline, "8": TypeLandline, "9": TypeMobile, "800": TypeTollFree,
+	}},
+	"TR": {code: "90", trunk: "0", min: 10, max: 10, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeMobile, "800": TypeTollFree,
+	}},
+	"IL": {code: "972", trunk: "0", min: 8, max: 9, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "8": TypeLandline, "9": TypeLandline,
+		"5": TypeMobile, "1800": TypeTollFree,
+	}},
+	"AE": {code: "971", trunk: "0", min: 8, max: 9, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "6": TypeLandline, "7": TypeLandline,
+		"9": TypeLandline, "5": TypeMobile, "800": TypeTollFree,
+	}},
+	"IN": {code: "91", trunk: "0", min: 10, max: 10, types: map[string]string{
+		"6": TypeMobile, "7": TypeMobile, "8": TypeMobile, "9": TypeMobile, "1800": TypeTollFree,
+	}},
+	"CN": {code: "86", trunk: "0", min: 10, max: 11, types: map[string]string{
+		"1": TypeMobile, "400": TypeTollFree, "800": TypeTollFree,
+	}},
+	"JP": {code: "81", trunk: "0", min: 9, max: 10, types: map[string]string{
+		"70": TypeMobile, "80": TypeMobile, "90": TypeMobile, "120": TypeTollFree,
+	}},
+	"SG": {code: "65", min: 8, max: 8, types: map[string]string{
+		"6": TypeLandline, "8": TypeMobile, "9": TypeMobile, "1800": TypeTollFree,
+	}},
+	"HK": {code: "852", min: 8, max: 8, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "5": TypeMobile, "6": TypeMobile, "9": TypeMobile,
+		"800": TypeTollFree,
+	}},
+	"AU": {code: "61", trunk: "0", min: 9, max: 9, types: map[string]string{
+		"2": TypeLandline, "3": TypeLandline, "7": TypeLandline, "8": TypeLandline, "4": TypeMobile,
+		"1800": TypeTollFree, "190": TypePremiumRate,
+	}},
+	"NZ": {code: "64", trunk: "0", min: 8, max: 10, types: map[string]string{
+		"3": TypeLandline, "4": TypeLandline, "6": TypeLandline, "7": TypeLandline, "9": TypeLandline,
+		"2": TypeMobile, "800": TypeTollFree, "900": TypePremiumRate,
+	}},
+	"ZA": {code: "27", trunk: "0", min: 9, max: 9, types: map[string]string{
+		"1": TypeLandline, "2": TypeLandline, "3": TypeLandline, "4": TypeLandline, "5": TypeLandline,
+		"6": TypeMobile, "7": TypeMobile, "8": TypeMobile, "80": TypeTollFree, "86": TypePremiumRate,
+	}},
+	"KE": {code: "254", trunk: "0", min: 9, max: 9, types: map[string]string{
+		"20": TypeLandline, "1": TypeMobile, "7": TypeMobile, "800": TypeTollFree,
+	}},
+	"NG": {code: "234", trunk: "0", min: 8, max: 10, types: map[string]string{
+		"70": TypeMobile, "80": TypeMobile, "81": TypeMobile, "90": TypeMobile, "91": TypeMobile,
+		"800": TypeTollFree,
+	}},
+	"BR": {code: "55", trunk: "0", min: 10, max: 11},
+	"MX": {code: "52", min: 10, max: 10, types: map[string]string{
+		"800": TypeTollFree,
+	}},
+}
+
+// regions are the countries calling codes shared by several countries are
+// attributed to
+var regions = map[string]string{
+	"1": "US",
+	"7": "RU",
+}
+
+// callingCodes indexes the countries by calling code
+var callingCodes = func() map[string]string {
+	index := make(map[string]string, len(plans))
+	for country, p := range plans {
+		if _, ok := regions[p.code]; !ok {
+			index[p.code] = country
+		}
+	}
+	for code, country := range regions {
+		index[code] = country
+	}
+	return index
+}()
@@ -0,0 +1,394 @@
+// Package quality rates stored companies on completeness, validity,
+// freshness and agreement between sources.
+package quality
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stkisengese/B2B-Data-Platform/internal/address"
+	"github.com/stkisengese/B2B-Data-Platform/internal/names"
+	"github.com/stkisengese/B2B-Data-Platform/internal/phone"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+	"github.com/stkisengese/B2B-Data-Platform/internal/website"
+)
+
+// Weights of the dimensions in the overall score
+const (
+	completenessWeight = 0.35
+	validityWeight     = 0.25
+	freshnessWeight    = 0.20
+	agreementWeight    = 0.20
+)
+
+// Records collected within FreshFor are fully fresh; freshness then falls
+// linearly to zero at StaleAfter
+const (
+	FreshFor   = 30 * 24 * time.Hour
+	StaleAfter = 365 * 24 * time.Hour
+)
+
+// Issues recorded with a score
+const (
+	IssueInvalidPostcode    = "invalid_postcode"
+	IssueInvalidPhone       = "invalid_phone"
+	IssueInvalidWebsite     = "invalid_website"
+	IssueInvalidFoundedYear = "invalid_founded_year"
+	IssueStale              = "stale"
+	IssueConflict           = "conflicting_"
+)
+
+// pageSize is the number of companies scored per transaction
+const pageSize = 500
+
+// Stats summarises a scoring run
+type Stats struct {
+	Scored  int
+	Average float64
+}
+
+// Scorer computes and stores the quality score of every company
+type Scorer struct {
+	db     *sqlx.DB
+	now    func() time.Time
+	logger *logrus.Logger
+}
+
+// NewScorer creates a scorer writing to db
+func NewScorer(db *sqlx.DB) *Scorer {
+	return &Scorer{
+		db:     db,
+		now:    time.Now,
+		logger: logrus.New(),
+	}
+}
+
+// record is a company with the data it is scored on
+type record struct {
+	company         repository.Company
+	address         repository.Address
+	hasAddress      bool
+	hasRegistration bool
+	lastCollected   time.Time
+}
+
+// country returns the ISO code of the record's country. Companies stored
+// before countries were normalized may still have names like "England".
+func (r record) country() string {
+	if code, ok := address.CountryCode(r.address.Country.String); ok {
+		return code
+	}
+	return r.address.Country.String
+}
+
+// Run scores every company
+func (s *Scorer) Run(ctx context.Context) (Stats, error) {
+	var stats Stats
+	var total float64
+
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		companies, err := repository.NewCompanyRepository(s.db).ListAfter(ctx, afterID, pageSize)
+		if err != nil {
+			return stats, err
+		}
+		if len(companies) == 0 {
+			break
+		}
+		afterID = companies[len(companies)-1].ID
+
+		err = repository.Transact(ctx, s.db, func(tx *sqlx.Tx) error {
+			scores, err := s.scorePage(ctx, tx, companies)
+			if err != nil {
+				return err
+			}
+
+			repo := repository.NewQualityRepository(tx)
+			for _, score := range scores {
+				if err := repo.Save(ctx, score); err != nil {
+					return err
+				}
+				total += score.Score
+			}
+			stats.Scored += len(scores)
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	if stats.Scored > 0 {
+		stats.Average = total / float64(stats.Scored)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"scored":  stats.Scored,
+		"average": fmt.Sprintf("%.1f", stats.Average),
+	}).Info("Quality scoring finished")
+
+	return stats, nil
+}
+
+// scorePage loads what a page of companies is scored on and scores them
+func (s *Scorer) scorePage(ctx context.Context, db repository.DBTX, companies []repository.Company) ([]repository.QualityScore, error) {
+	ids := make([]int64, len(companies))
+	var clusterIDs []int64
+	for i, c := range companies {
+		ids[i] = c.ID
+		if c.ClusterID.Valid {
+			clusterIDs = append(clusterIDs, c.ClusterID.Int64)
+		}
+	}
+
+	members, err := repository.NewClusterRepository(db).MembersOf(ctx, clusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Records of every company on the page and of their cluster peers
+	all := append([]repository.Company{}, companies...)
+	all = append(all, members...)
+	records, err := loadRecords(ctx, db, all)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make(map[int64][]*record)
+	for _, m := range members {
+		clusters[m.ClusterID.Int64] = append(clusters[m.ClusterID.Int64], records[m.ID])
+	}
+
+	now := s.now()
+	scores := make([]repository.QualityScore, len(companies))
+	for i, c := range companies {
+		var peers []*record
+		for _, peer := range clusters[c.ClusterID.Int64] {
+			if c.ClusterID.Valid && peer.company.ID != c.ID {
+				peers = append(peers, peer)
+			}
+		}
+		scores[i] = Score(*records[c.ID], peers, now)
+	}
+	return scores, nil
+}
+
+// loadRecords loads the primary address, registration and last collection
+// time of companies
+func loadRecords(ctx context.Context, db repository.DBTX, companies []repository.Company) (map[int64]*record, error) {
+	ids := make([]int64, len(companies))
+	for i, c := range companies {
+		ids[i] = c.ID
+	}
+
+	addresses, err := repository.NewCompanyRepository(db).PrimaryAddresses(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	registered, err := repository.NewIdentifierRepository(db).CompaniesWithScheme(ctx, repository.SchemeRegistration, ids)
+	if err != nil {
+		return nil, err
+	}
+	collected, err := repository.NewProvenanceRepository(db).LatestCollected(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[int64]*record, len(companies))
+	for _, c := range companies {
+		addr, hasAddress := addresses[c.ID]
+		lastCollected, ok := collected[c.ID]
+		if !ok {
+			lastCollected = c.UpdatedAt
+		}
+		records[c.ID] = &record{
+			company:         c,
+			address:         addr,
+			hasAddress:      hasAddress,
+			hasRegistration: registered[c.ID],
+			lastCollected:   lastCollected,
+		}
+	}
+	return records, nil
+}
+
+// Score rates a company against the records of the same entity from other
+// sources, as of now
+func Score(r record, peers []*record, now time.Time) repository.QualityScore {
+	var issues []string
+
+	completeness := completeness(r)
+	validity, invalid := validity(r, now)
+	issues = append(issues, invalid...)
+
+	freshness := freshness(now.Sub(r.lastCollected))
+	if freshness == 0 {
+		issues = append(issues, IssueStale)
+	}
+
+	agreement, conflicts := agreement(r, peers)
+	issues = append(issues, conflicts...)
+
+	total := completenessWeight*completeness + validityWeight*validity +
+		freshnessWeight*freshness + agreementWeight*agreement
+
+	return repository.QualityScore{
+		CompanyID:    r.company.ID,
+		Score:        math.Round(total*1000) / 10,
+		Completeness: completeness,
+		Validity:     validity,
+		Freshness:    freshness,
+		Agreement:    agreement,
+		Issues:       repository.NullString(strings.Join(issues, ",")),
+		ScoredAt:     now,
+	}
+}
+
+// completeness is the share of expected values that are present
+func completeness(r record) float64 {
+	c := r.company
+	present := []bool{
+		c.LegalName.Valid,
+		c.Website.Valid,
+		c.Phone.Valid,
+		c.Industry.Valid,
+		c.EmployeeCount.Valid,
+		c.FoundedYear.Valid,
+		c.Description.Valid,
+		c.Status.Valid,
+		r.hasRegistration,
+		r.address.AddressLine1.Valid,
+		r.address.City.Valid,
+		r.address.PostalCode.Valid,
+		r.address.Country.Valid,
+	}
+
+	filled := 0
+	for _, ok := range present {
+		if ok {
+			filled++
+		}
+	}
+	return float64(filled) / float64(len(present))
+}
+
+// validity is the share of checkable values that have a valid format;
+// records with nothing to check are fully valid
+func validity(r record, now time.Time) (float64, []string) {
+	var checked, valid int
+	var issues []string
+
+	check := func(ok bool, issue string) {
+		checked++
+		if ok {
+			valid++
+		} else {
+			issues = append(issues, issue)
+		}
+	}
+
+	if r.address.PostalCode.Valid {
+		if ok, known := address.ValidPostcode(r.country(), r.address.PostalCode.String); known {
+			check(ok, IssueInvalidPostcode)
+		}
+	}
+	if r.company.Phone.Valid {
+		_, err := phone.Parse(r.company.Phone.String, r.country())
+		check(err == nil, IssueInvalidPhone)
+	}
+	if r.company.Website.Valid {
+		_, err := website.Parse(r.company.Website.String)
+		check(err == nil, IssueInvalidWebsite)
+	}
+	if r.company.FoundedYear.Valid {
+		year := r.company.FoundedYear.Int64
+		check(year >= 1600 && year <= int64(now.Year()), IssueInvalidFoundedYear)
+	}
+
+	if checked == 0 {
+		return 1, nil
+	}
+	return float64(valid) / float64(checked), issues
+}
+
+// freshness falls from 1 to 0 as the record ages from FreshFor to StaleAfter
+func freshness(age time.Duration) float64 {
+	switch {
+	case age <= FreshFor:
+		return 1
+	case age >= StaleAfter:
+		return 0
+	}
+	return 1 - float64(age-FreshFor)/float64(StaleAfter-FreshFor)
+}
+
+// agreement is the share of values that other sources for the same entity
+// confirm. Records without peers, or without values to compare, score 0.5:
+// they are neither confirmed nor contradicted.
+func agreement(r record, peers []*record) (float64, []string) {
+	var compared, agreed int
+	conflicts := make(map[string]bool)
+	var issues []string
+
+	for _, peer := range peers {
+		for _, field := range comparedFields {
+			a, b := field.value(r), field.value(*peer)
+			if a == "" || b == "" {
+				continue
+			}
+			compared++
+			if a == b {
+				agreed++
+			} else if !conflicts[field.name] {
+				conflicts[field.name] = true
+				issues = append(issues, IssueConflict+field.name)
+			}
+		}
+	}
+
+	if compared == 0 {
+		return 0.5, nil
+	}
+	return float64(agreed) / float64(compared), issues
+}
+
+// comparedFields are the values compared between sources, in a form where
+// equal values compare equal
+var comparedFields = []struct {
+	name  string
+	value func(r record) string
+}{
+	{"name", func(r record) string {
+		name := r.company.Name
+		if r.company.LegalName.Valid {
+			name = r.company.LegalName.String
+		}
+		return names.Standardize(name, r.country()).MatchKey
+	}},
+	{"website", func(r record) string {
+		if r.company.Domain.Valid {
+			return r.company.Domain.String
+		}
+		domain, _ := website.LookupDomain(r.company.Website.String)
+		return domain
+	}},
+	{"postal_code", func(r record) string {
+		return strings.ToUpper(strings.ReplaceAll(r.address.PostalCode.String, " ", ""))
+	}},
+	{"founded_year", func(r record) string {
+		if !r.company.FoundedYear.Valid {
+			return ""
+		}
+		return fmt.Sprint(r.company.FoundedYear.Int64)
+	}},
+	{"status", func(r record) string { return r.company.Status.String }},
+}
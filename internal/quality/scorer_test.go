@@ -0,0 +1,173 @@
+package quality
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestScore(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	complete := record{
+		company: repository.Company{
+			ID: 1, Name: "Example Ltd",
+			LegalName:     repository.NullString("Example Ltd"),
+			Website:       repository.NullString("https://www.example.co.uk"),
+			Phone:         repository.NullString("+44 20 7946 0000"),
+			Industry:      repository.NullString("Software"),
+			EmployeeCount: repository.NullInt64(50),
+			FoundedYear:   repository.NullInt64(2001),
+			Description:   repository.NullString("Makes examples"),
+			Status:        repository.NullString("active"),
+		},
+		address: repository.Address{
+			AddressLine1: repository.NullString("1 High Street"),
+			City:         repository.NullString("London"),
+			PostalCode:   repository.NullString("EC1A 1BB"),
+			Country:      repository.NullString("GB"),
+		},
+		hasRegistration: true,
+		lastCollected:   now.Add(-24 * time.Hour),
+	}
+
+	score := Score(complete, nil, now)
+	if score.Completeness != 1 || score.Validity != 1 || score.Freshness != 1 || score.Agreement != 0.5 {
+		t.Errorf("Expected complete, valid, fresh and unconfirmed record, got %+v", score)
+	}
+	if score.Score != 90 {
+		t.Errorf("Expected score 90, got %.1f", score.Score)
+	}
+
+	// A second source confirms the record except for its founded year
+	peer := complete
+	peer.company.ID = 2
+	peer.company.Website = repository.NullString("example.co.uk")
+	peer.company.FoundedYear = repository.NullInt64(2002)
+	score = Score(complete, []*record{&peer}, now)
+	if score.Agreement != 0.8 {
+		t.Errorf("Expected agreement 0.8, got %.2f", score.Agreement)
+	}
+	if score.Issues.String != IssueConflict+"founded_year" {
+		t.Errorf("Expected founded year conflict, got '%s'", score.Issues.String)
+	}
+
+	broken := complete
+	broken.company.Phone = repository.NullString("call us")
+	broken.company.Website = repository.NullString("not a website")
+	broken.address.PostalCode = repository.NullString("12345")
+	broken.lastCollected = now.Add(-2 * StaleAfter)
+	score = Score(broken, nil, now)
+	if score.Validity != 0.25 || score.Freshness != 0 {
+		t.Errorf("Expected validity 0.25 and freshness 0, got %.2f and %.2f", score.Validity, score.Freshness)
+	}
+	for _, issue := range []string{IssueInvalidPostcode, IssueInvalidPhone, IssueInvalidWebsite, IssueStale} {
+		if !strings.Contains(score.Issues.String, issue) {
+			t.Errorf("Expected issue %s, got '%s'", issue, score.Issues.String)
+		}
+	}
+	// Countries stored by name are checked like their ISO codes
+	named := broken
+	named.address.Country = repository.NullString("England")
+	score = Score(named, nil, now)
+	if score.Validity != 0.25 || !strings.Contains(score.Issues.String, IssueInvalidPostcode) {
+		t.Errorf("Expected invalid English postcode, got %.2f and '%s'", score.Validity, score.Issues.String)
+	}
+}
+
+func TestFreshness(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		age  time.Duration
+		want float64
+	}{
+		{0, 1},
+		{FreshFor, 1},
+		{FreshFor + (StaleAfter-FreshFor)/2, 0.5},
+		{StaleAfter, 0},
+		{1000 * day, 0},
+	}
+	for _, tt := range tests {
+		if got := freshness(tt.age); got != tt.want {
+			t.Errorf("Expected freshness %.2f at %s, got %.2f", tt.want, tt.age, got)
+		}
+	}
+}
+
+func TestScorer_Run(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	err := repository.NewCompanyRepository(db).SaveBatch(ctx, []repository.CompanyRecord{
+		{
+			Company: repository.Company{
+				ExternalID: repository.NullString("ch_1"), Name: "Example Ltd", DataSource: "companies_house",
+				Website: repository.NullString("https://example.co.uk"),
+			},
+			Addresses: []repository.Address{{
+				AddressLine1: repository.NullString("1 High Street"),
+				PostalCode:   repository.NullString("EC1A 1BB"),
+				Country:      repository.NullString("GB"),
+				IsPrimary:    true,
+			}},
+			CollectedAt: now,
+		},
+		{
+			Company: repository.Company{
+				ExternalID: repository.NullString("oc_1"), Name: "Example GmbH", DataSource: "opencorporates",
+				Phone: repository.NullString("12"),
+			},
+			Addresses: []repository.Address{{
+				PostalCode: repository.NullString("1234"),
+				Country:    repository.NullString("DE"),
+				IsPrimary:  true,
+			}},
+			CollectedAt: now,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save companies: %v", err)
+	}
+
+	count, _ := repository.NewCompanyRepository(db).Count(ctx)
+
+	stats, err := NewScorer(db).Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Scored != count {
+		t.Errorf("Expected %d scored companies, got %d", count, stats.Scored)
+	}
+
+	reports, err := repository.NewQualityRepository(db).Report(ctx, repository.ReportByCountry)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	byCountry := make(map[string]repository.QualityReport)
+	for _, report := range reports {
+		byCountry[report.Group] = report
+	}
+	if byCountry["DE"].Companies != 1 || byCountry["GB"].Companies != 1 {
+		t.Fatalf("Expected one company in DE and GB, got %+v", reports)
+	}
+	if byCountry["DE"].Validity != 0 || byCountry["GB"].Validity != 1 {
+		t.Errorf("Expected validity 0 for DE and 1 for GB, got %.2f and %.2f", byCountry["DE"].Validity, byCountry["GB"].Validity)
+	}
+	if reports[0].Group != "DE" {
+		t.Errorf("Expected lowest scoring country DE first, got %s", reports[0].Group)
+	}
+
+	id, _ := repository.NewCompanyRepository(db).IDByExternalID(ctx, "oc_1")
+	score, err := repository.NewQualityRepository(db).Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Expected stored score, got %v", err)
+	}
+	if score.Issues.String != IssueInvalidPostcode+","+IssueInvalidPhone {
+		t.Errorf("Expected invalid postcode and phone, got '%s'", score.Issues.String)
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Category represents a row in the categories table, a node of the unified
+// industry taxonomy
+type Category struct {
+	ID        int64         `db:"id"`
+	Code      string        `db:"code"`
+	Name      string        `db:"name"`
+	Level     int           `db:"level"`
+	ParentID  sql.NullInt64 `db:"parent_id"`
+	CreatedAt time.Time     `db:"created_at"`
+	// Parent is saved before the category when set
+	Parent *Category `db:"-"`
+}
+
+// CategoryRepository reads and writes the categories and company_categories
+// tables
+type CategoryRepository struct {
+	db DBTX
+}
+
+// NewCategoryRepository creates a category repository on a connection or transaction
+func NewCategoryRepository(db DBTX) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// Save inserts a category and its parents, or updates the existing rows
+// with the same codes
+func (r *CategoryRepository) Save(ctx context.Context, category *Category) (int64, error) {
+	if category.Parent != nil {
+		parentID, err := r.Save(ctx, category.Parent)
+		if err != nil {
+			return 0, err
+		}
+		category.ParentID = NullInt64(parentID)
+	}
+
+	var id int64
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO categories (code, name, level, parent_id)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(code) DO UPDATE SET
+		    name = excluded.name,
+		    level = excluded.level,
+		    parent_id = excluded.parent_id
+		RETURNING id`,
+		category.Code, category.Name, category.Level, category.ParentID,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save category %s: %w", category.Code, err)
+	}
+
+	category.ID = id
+	return id, nil
+}
+
+// ReplaceCompany links a company to the given categories instead of its
+// current ones. The first category is marked as primary.
+func (r *CategoryRepository) ReplaceCompany(ctx context.Context, companyID int64, categories []Category) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM company_categories WHERE company_id = ?", companyID); err != nil {
+		return fmt.Errorf("failed to delete categories of company %d: %w", companyID, err)
+	}
+
+	for i := range categories {
+		id, err := r.Save(ctx, &categories[i])
+		if err != nil {
+			return err
+		}
+
+		_, err = r.db.ExecContext(ctx, `
+			INSERT INTO company_categories (company_id, category_id, is_primary)
+			VALUES (?, ?, ?)
+			ON CONFLICT(company_id, category_id) DO NOTHING`,
+			companyID, id, i == 0,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to link company %d to category %s: %w", companyID, categories[i].Code, err)
+		}
+	}
+
+	return nil
+}
+
+// ListByCompany returns the categories of a company, primary first
+func (r *CategoryRepository) ListByCompany(ctx context.Context, companyID int64) ([]Category, error) {
+	var categories []Category
+	err := sqlx.SelectContext(ctx, r.db, &categories, `
+		SELECT c.*
+		FROM categories c
+		JOIN company_categories cc ON cc.category_id = c.id
+		WHERE cc.company_id = ?
+		ORDER BY cc.is_primary DESC, c.code`, companyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories of company %d: %w", companyID, err)
+	}
+	return categories, nil
+}
+
+// CompanyIDs returns the companies linked to a category or to any of its
+// descendants
+func (r *CategoryRepository) CompanyIDs(ctx context.Context, code string) ([]int64, error) {
+	var ids []int64
+	err := sqlx.SelectContext(ctx, r.db, &ids, `
+		WITH RECURSIVE tree(id) AS (
+		    SELECT id FROM categories WHERE code = ?
+		    UNION
+		    SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT DISTINCT cc.company_id
+		FROM company_categories cc
+		JOIN tree t ON t.id = cc.category_id
+		ORDER BY cc.company_id`, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies in category %s: %w", code, err)
+	}
+	return ids, nil
+}
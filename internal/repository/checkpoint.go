@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ImportCheckpoint records how far an import got through one file entry
+type ImportCheckpoint struct {
+	Source    string    `db:"source"`
+	File      string    `db:"file"`
+	Entry     string    `db:"entry"`
+	RowsDone  int       `db:"rows_done"`
+	Completed bool      `db:"completed"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// CheckpointRepository reads and writes the import_checkpoints table
+type CheckpointRepository struct {
+	db DBTX
+}
+
+// NewCheckpointRepository creates a checkpoint repository on a connection or transaction
+func NewCheckpointRepository(db DBTX) *CheckpointRepository {
+	return &CheckpointRepository{db: db}
+}
+
+// Get returns the checkpoint for a file entry, or a zero checkpoint if the
+// entry has not been started
+func (r *CheckpointRepository) Get(ctx context.Context, source, file, entry string) (ImportCheckpoint, error) {
+	var cp ImportCheckpoint
+	err := r.db.QueryRowxContext(ctx, `
+		SELECT source, file, entry, rows_done, completed, updated_at
+		FROM import_checkpoints
+		WHERE source = ? AND file = ? AND entry = ?`,
+		source, file, entry,
+	).StructScan(&cp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ImportCheckpoint{Source: source, File: file, Entry: entry}, nil
+	}
+	if err != nil {
+		return cp, fmt.Errorf("failed to load checkpoint for %s/%s: %w", file, entry, err)
+	}
+
+	return cp, nil
+}
+
+// Save stores the progress of a file entry
+func (r *CheckpointRepository) Save(ctx context.Context, cp ImportCheckpoint) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO import_checkpoints (source, file, entry, rows_done, completed)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(source, file, entry) DO UPDATE SET
+		    rows_done = excluded.rows_done,
+		    completed = excluded.completed,
+		    updated_at = CURRENT_TIMESTAMP`,
+		cp.Source, cp.File, cp.Entry, cp.RowsDone, cp.Completed,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint for %s/%s: %w", cp.File, cp.Entry, err)
+	}
+
+	return nil
+}
+
+// GetTimepoint returns the last timepoint processed on a stream, or zero if
+// the stream has not been consumed yet
+func (r *CheckpointRepository) GetTimepoint(ctx context.Context, stream string) (int64, error) {
+	var timepoint int64
+	err := r.db.QueryRowxContext(ctx, "SELECT timepoint FROM stream_checkpoints WHERE stream = ?", stream).Scan(&timepoint)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load timepoint for %s: %w", stream, err)
+	}
+
+	return timepoint, nil
+}
+
+// SaveTimepoint stores the last timepoint processed on a stream
+func (r *CheckpointRepository) SaveTimepoint(ctx context.Context, stream string, timepoint int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO stream_checkpoints (stream, timepoint)
+		VALUES (?, ?)
+		ON CONFLICT(stream) DO UPDATE SET
+		    timepoint = excluded.timepoint,
+		    updated_at = CURRENT_TIMESTAMP`,
+		stream, timepoint,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save timepoint for %s: %w", stream, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Cluster represents a row in the company_clusters table: the golden record
+// of the companies that were resolved to the same entity
+type Cluster struct {
+	ID            int64          `db:"id"`
+	Name          string         `db:"name"`
+	LegalName     sql.NullString `db:"legal_name"`
+	Description   sql.NullString `db:"description"`
+	Website       sql.NullString `db:"website"`
+	Phone         sql.NullString `db:"phone"`
+	Industry      sql.NullString `db:"industry"`
+	EmployeeCount sql.NullInt64  `db:"employee_count"`
+	FoundedYear   sql.NullInt64  `db:"founded_year"`
+	Status        sql.NullString `db:"status"`
+	LegalForm     sql.NullString `db:"legal_form"`
+	LegalFormCode sql.NullString `db:"legal_form_code"`
+	MemberCount   int            `db:"member_count"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+}
+
+// ResolutionCandidate is a company with its primary address, as compared
+// during entity resolution
+type ResolutionCandidate struct {
+	ID           int64          `db:"id"`
+	Name         string         `db:"name"`
+	LegalName    sql.NullString `db:"legal_name"`
+	MatchKey     sql.NullString `db:"match_key"`
+	ClusterID    sql.NullInt64  `db:"cluster_id"`
+	AddressLine1 sql.NullString `db:"address_line1"`
+	City         sql.NullString `db:"city"`
+	PostalCode   sql.NullString `db:"postal_code"`
+	Country      sql.NullString `db:"country"`
+}
+
+// ClusterRepository reads and writes the company_clusters table and the
+// cluster of each company
+type ClusterRepository struct {
+	db DBTX
+}
+
+// NewClusterRepository creates a cluster repository on a connection or transaction
+func NewClusterRepository(db DBTX) *ClusterRepository {
+	return &ClusterRepository{db: db}
+}
+
+// ListCandidates returns every company with its primary address
+func (r *ClusterRepository) ListCandidates(ctx context.Context) ([]ResolutionCandidate, error) {
+	var candidates []ResolutionCandidate
+	err := sqlx.SelectContext(ctx, r.db, &candidates, `
+		SELECT c.id, c.name, c.legal_name, c.match_key, c.cluster_id,
+		       a.address_line1, a.city, a.postal_code, a.country
+		FROM companies c
+		LEFT JOIN addresses a ON a.company_id = c.id AND a.is_primary = 1
+		GROUP BY c.id
+		ORDER BY c.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resolution candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// IdentifierLinks returns the pairs of companies that share an identifier
+// of one of the given schemes, lower ID first
+func (r *ClusterRepository) IdentifierLinks(ctx context.Context, schemes []string) ([][2]int64, error) {
+	query, args, err := sqlx.In(`
+		SELECT DISTINCT a.company_id, b.company_id
+		FROM company_identifiers a
+		JOIN company_identifiers b
+		  ON b.scheme = a.scheme AND b.value = a.value AND b.company_id > a.company_id
+		WHERE a.scheme IN (?)
+		ORDER BY a.company_id, b.company_id`, schemes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build identifier link query: %w", err)
+	}
+
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identifier links: %w", err)
+	}
+	defer rows.Close()
+
+	var links [][2]int64
+	for rows.Next() {
+		var link [2]int64
+		if err := rows.Scan(&link[0], &link[1]); err != nil {
+			return nil, fmt.Errorf("failed to scan identifier link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// Create inserts an empty cluster named after its first member and returns
+// its ID
+func (r *ClusterRepository) Create(ctx context.Context, name string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowxContext(ctx,
+		"INSERT INTO company_clusters (name) VALUES (?) RETURNING id", name,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create cluster for %q: %w", name, err)
+	}
+	return id, nil
+}
+
+// Assign moves companies into a cluster
+func (r *ClusterRepository) Assign(ctx context.Context, clusterID int64, companyIDs []int64) error {
+	query, args, err := sqlx.In(
+		"UPDATE companies SET cluster_id = ? WHERE id IN (?)", clusterID, companyIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to build cluster assignment: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to assign companies to cluster %d: %w", clusterID, err)
+	}
+	return nil
+}
+
+// Members returns the companies of a cluster
+func (r *ClusterRepository) Members(ctx context.Context, clusterID int64) ([]Company, error) {
+	var companies []Company
+	err := sqlx.SelectContext(ctx, r.db, &companies,
+		"SELECT * FROM companies WHERE cluster_id = ? ORDER BY id", clusterID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members of cluster %d: %w", clusterID, err)
+	}
+	return companies, nil
+}
+
+// MembersOf returns the companies of the given clusters
+func (r *ClusterRepository) MembersOf(ctx context.Context, clusterIDs []int64) ([]Company, error) {
+	if len(clusterIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In("SELECT * FROM companies WHERE cluster_id IN (?) ORDER BY id", clusterIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build cluster member query: %w", err)
+	}
+
+	var companies []Company
+	if err := sqlx.SelectContext(ctx, r.db, &companies, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list cluster members: %w", err)
+	}
+	return companies, nil
+}
+
+// Get returns a cluster, wrapping sql.ErrNoRows when there is none
+func (r *ClusterRepository) Get(ctx context.Context, id int64) (Cluster, error) {
+	var cluster Cluster
+	if err := sqlx.GetContext(ctx, r.db, &cluster, "SELECT * FROM company_clusters WHERE id = ?", id); err != nil {
+		return cluster, fmt.Errorf("failed to get cluster %d: %w", id, err)
+	}
+	return cluster, nil
+}
+
+// SaveGolden stores the golden record fields of a cluster
+func (r *ClusterRepository) SaveGolden(ctx context.Context, cluster Cluster) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE company_clusters SET
+		    name = ?, legal_name = ?, description = ?, website = ?, phone = ?,
+		    industry = ?, employee_count = ?, founded_year = ?, status = ?,
+		    legal_form = ?, legal_form_code = ?, member_count = ?,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`,
+		cluster.Name, cluster.LegalName, cluster.Description, cluster.Website, cluster.Phone,
+		cluster.Industry, cluster.EmployeeCount, cluster.FoundedYear, cluster.Status,
+		cluster.LegalForm, cluster.LegalFormCode, cluster.MemberCount,
+		cluster.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save golden record of cluster %d: %w", cluster.ID, err)
+	}
+	return nil
+}
+
+// Stale returns the clusters with a member updated after the golden record
+func (r *ClusterRepository) Stale(ctx context.Context) ([]int64, error) {
+	var ids []int64
+	err := sqlx.SelectContext(ctx, r.db, &ids, `
+		SELECT DISTINCT k.id
+		FROM company_clusters k
+		JOIN companies c ON c.cluster_id = k.id
+		WHERE c.updated_at > k.updated_at
+		ORDER BY k.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale clusters: %w", err)
+	}
+	return ids, nil
+}
+
+// DeleteEmpty removes clusters left without members, with the provenance of
+// their golden records, and returns how many were removed
+func (r *ClusterRepository) DeleteEmpty(ctx context.Context) (int64, error) {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM cluster_provenance
+		WHERE cluster_id NOT IN (SELECT cluster_id FROM companies WHERE cluster_id IS NOT NULL)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete provenance of empty clusters: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM company_clusters
+		WHERE id NOT IN (SELECT cluster_id FROM companies WHERE cluster_id IS NOT NULL)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete empty clusters: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,582 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Company represents a row in the companies table
+type Company struct {
+	ID            int64          `db:"id"`
+	ExternalID    sql.NullString `db:"external_id"`
+	Name          string         `db:"name"`
+	LegalName     sql.NullString `db:"legal_name"`
+	Description   sql.NullString `db:"description"`
+	Website       sql.NullString `db:"website"`
+	Domain        sql.NullString `db:"domain"`
+	Phone         sql.NullString `db:"phone"`
+	PhoneType     sql.NullString `db:"phone_type"`
+	PhoneValid    sql.NullBool   `db:"phone_valid"`
+	Industry      sql.NullString `db:"industry"`
+	EmployeeCount sql.NullInt64  `db:"employee_count"`
+	RevenueRange  sql.NullString `db:"revenue_range"`
+	FoundedYear   sql.NullInt64  `db:"founded_year"`
+	Status        sql.NullString `db:"status"`
+	StatusRaw     sql.NullString `db:"status_raw"`
+	LegalForm     sql.NullString `db:"legal_form"`
+	LegalFormCode sql.NullString `db:"legal_form_code"`
+	MatchKey      sql.NullString `db:"match_key"`
+	ClusterID     sql.NullInt64  `db:"cluster_id"`
+	DataSource    string         `db:"data_source"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+}
+
+// Address represents a row in the addresses table
+type Address struct {
+	ID           int64           `db:"id"`
+	CompanyID    int64           `db:"company_id"`
+	AddressLine1 sql.NullString  `db:"address_line1"`
+	AddressLine2 sql.NullString  `db:"address_line2"`
+	City         sql.NullString  `db:"city"`
+	State        sql.NullString  `db:"state"`
+	PostalCode   sql.NullString  `db:"postal_code"`
+	Country      sql.NullString  `db:"country"`
+	Latitude     sql.NullFloat64 `db:"latitude"`
+	Longitude    sql.NullFloat64 `db:"longitude"`
+	IsPrimary    bool            `db:"is_primary"`
+	// GeocodePrecision is the level the coordinates are accurate to, see
+	// package geocode; GeocodedAt is set once geocoding was attempted
+	GeocodePrecision sql.NullString `db:"geocode_precision"`
+	GeocodeSource    sql.NullString `db:"geocode_source"`
+	GeocodedAt       sql.NullTime   `db:"geocoded_at"`
+}
+
+// sameAs reports whether two addresses have the same text
+func (a Address) sameAs(other Address) bool {
+	return a.AddressLine1 == other.AddressLine1 && a.AddressLine2 == other.AddressLine2 &&
+		a.City == other.City && a.State == other.State &&
+		a.PostalCode == other.PostalCode && a.Country == other.Country
+}
+
+// CompanyRecord is a company together with the addresses and identifiers
+// stored alongside it
+type CompanyRecord struct {
+	Company     Company
+	Addresses   []Address
+	Identifiers []Identifier
+	// Categories replace the company's taxonomy links when not empty
+	Categories []Category
+	// Contacts are upserted by external ID; contacts missing from a later
+	// record are kept with their opt-outs
+	Contacts []Contact
+	// CollectedAt is when the source record was collected, recorded as the
+	// provenance of the supplied columns; the save time when zero
+	CollectedAt time.Time
+}
+
+// SuppliedFields returns the names of the columns tracked in
+// field_provenance that have a value
+func (c Company) SuppliedFields() []string {
+	columns := []struct {
+		name  string
+		valid bool
+	}{
+		{"name", c.Name != ""},
+		{"legal_name", c.LegalName.Valid},
+		{"description", c.Description.Valid},
+		{"website", c.Website.Valid},
+		{"phone", c.Phone.Valid},
+		{"industry", c.Industry.Valid},
+		{"employee_count", c.EmployeeCount.Valid},
+		{"revenue_range", c.RevenueRange.Valid},
+		{"founded_year", c.FoundedYear.Valid},
+		{"status", c.Status.Valid},
+		{"legal_form", c.LegalForm.Valid},
+		{"legal_form_code", c.LegalFormCode.Valid},
+	}
+
+	var fields []string
+	for _, column := range columns {
+		if column.valid {
+			fields = append(fields, column.name)
+		}
+	}
+	return fields
+}
+
+// CompanyRepository reads and writes the companies and addresses tables
+type CompanyRepository struct {
+	db DBTX
+}
+
+// NewCompanyRepository creates a company repository on a connection or transaction
+func NewCompanyRepository(db DBTX) *CompanyRepository {
+	return &CompanyRepository{db: db}
+}
+
+// upsertCompanyQuery defaults the status of new rows to 'active'; updates
+// read the bound status (?14) instead of excluded.status so that a record
+// without one keeps the stored status and raw status
+const upsertCompanyQuery = `
+INSERT INTO companies (
+    external_id, name, legal_name, description, website, domain, phone,
+    phone_type, phone_valid, industry, employee_count, revenue_range,
+    founded_year, status, status_raw, legal_form, legal_form_code, match_key,
+    data_source
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(?, 'active'), ?, ?, ?, ?, ?)
+ON CONFLICT(external_id) DO UPDATE SET
+    name = excluded.name,
+    legal_name = COALESCE(excluded.legal_name, companies.legal_name),
+    description = COALESCE(excluded.description, companies.description),
+    website = COALESCE(excluded.website, companies.website),
+    domain = CASE WHEN excluded.website IS NULL THEN companies.domain ELSE excluded.domain END,
+    phone = COALESCE(excluded.phone, companies.phone),
+    phone_type = CASE WHEN excluded.phone IS NULL THEN companies.phone_type ELSE excluded.phone_type END,
+    phone_valid = CASE WHEN excluded.phone IS NULL THEN companies.phone_valid ELSE excluded.phone_valid END,
+    industry = COALESCE(excluded.industry, companies.industry),
+    employee_count = COALESCE(excluded.employee_count, companies.employee_count),
+    revenue_range = COALESCE(excluded.revenue_range, companies.revenue_range),
+    founded_year = COALESCE(excluded.founded_year, companies.founded_year),
+    status = COALESCE(?14, companies.status),
+    status_raw = COALESCE(excluded.status_raw, companies.status_raw),
+    legal_form = COALESCE(excluded.legal_form, companies.legal_form),
+    legal_form_code = COALESCE(excluded.legal_form_code, companies.legal_form_code),
+    match_key = COALESCE(excluded.match_key, companies.match_key),
+    data_source = excluded.data_source,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id`
+
+// Upsert inserts a company or updates the existing row with the same
+// external ID, leaving columns the caller did not supply untouched
+func (r *CompanyRepository) Upsert(ctx context.Context, company *Company) (int64, error) {
+	if !company.ExternalID.Valid {
+		return 0, fmt.Errorf("company %q has no external ID", company.Name)
+	}
+
+	var id int64
+	err := r.db.QueryRowxContext(ctx, upsertCompanyQuery,
+		company.ExternalID, company.Name, company.LegalName, company.Description,
+		company.Website, company.Domain, company.Phone, company.PhoneType, company.PhoneValid, company.Industry, company.EmployeeCount,
+		company.RevenueRange, company.FoundedYear, company.Status, company.StatusRaw,
+		company.LegalForm, company.LegalFormCode, company.MatchKey, company.DataSource,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upsert company %s: %w", company.ExternalID.String, err)
+	}
+
+	company.ID = id
+	return id, nil
+}
+
+// ReplaceAddresses deletes the addresses of a company and inserts the given
+// ones. Addresses that are unchanged keep their geocoded coordinates.
+func (r *CompanyRepository) ReplaceAddresses(ctx context.Context, companyID int64, addresses []Address) error {
+	var geocoded []Address
+	err := sqlx.SelectContext(ctx, r.db, &geocoded,
+		"SELECT * FROM addresses WHERE company_id = ? AND geocoded_at IS NOT NULL", companyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list geocoded addresses for company %d: %w", companyID, err)
+	}
+	for i := range addresses {
+		for _, old := range geocoded {
+			if addresses[i].GeocodedAt.Valid || !addresses[i].sameAs(old) {
+				continue
+			}
+			addresses[i].Latitude, addresses[i].Longitude = old.Latitude, old.Longitude
+			addresses[i].GeocodePrecision, addresses[i].GeocodeSource = old.GeocodePrecision, old.GeocodeSource
+			addresses[i].GeocodedAt = old.GeocodedAt
+		}
+	}
+
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM addresses WHERE company_id = ?", companyID); err != nil {
+		return fmt.Errorf("failed to delete addresses for company %d: %w", companyID, err)
+	}
+
+	for i := range addresses {
+		addr := &addresses[i]
+		addr.CompanyID = companyID
+
+		result, err := r.db.ExecContext(ctx, `
+			INSERT INTO addresses (
+			    company_id, address_line1, address_line2, city, state,
+			    postal_code, country, latitude, longitude, is_primary,
+			    geocode_precision, geocode_source, geocoded_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			addr.CompanyID, addr.AddressLine1, addr.AddressLine2, addr.City, addr.State,
+			addr.PostalCode, addr.Country, addr.Latitude, addr.Longitude, addr.IsPrimary,
+			addr.GeocodePrecision, addr.GeocodeSource, addr.GeocodedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert address for company %d: %w", companyID, err)
+		}
+
+		addr.ID, _ = result.LastInsertId()
+	}
+
+	return nil
+}
+
+// SaveBatch upserts each company, records the provenance of its supplied
+// columns, replaces its addresses and categories when any are supplied,
+// attaches its identifiers and upserts its contacts. Callers bind
+// the repository to a transaction so that a batch is applied atomically.
+func (r *CompanyRepository) SaveBatch(ctx context.Context, records []CompanyRecord) error {
+	identifiers := NewIdentifierRepository(r.db)
+	provenance := NewProvenanceRepository(r.db)
+	categories := NewCategoryRepository(r.db)
+	contacts := NewContactRepository(r.db)
+
+	for i := range records {
+		company := &records[i].Company
+		id, err := r.Upsert(ctx, company)
+		if err != nil {
+			return err
+		}
+
+		err = provenance.Record(ctx, id, company.SuppliedFields(), company.DataSource, company.ExternalID.String, records[i].CollectedAt)
+		if err != nil {
+			return err
+		}
+
+		// Sources such as Wikidata report no address, which must not
+		// remove the one stored from another source
+		if len(records[i].Addresses) > 0 {
+			if err := r.ReplaceAddresses(ctx, id, records[i].Addresses); err != nil {
+				return err
+			}
+		}
+
+		if len(records[i].Categories) > 0 {
+			if err := categories.ReplaceCompany(ctx, id, records[i].Categories); err != nil {
+				return err
+			}
+		}
+
+		for j := range records[i].Identifiers {
+			identifier := &records[i].Identifiers[j]
+			identifier.CompanyID = id
+			if err := identifiers.Attach(ctx, identifier); err != nil {
+				return err
+			}
+		}
+
+		for j := range records[i].Contacts {
+			contact := &records[i].Contacts[j]
+			contact.CompanyID = id
+			if err := contacts.Upsert(ctx, contact); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// SaveCompany saves a company with its addresses, identifiers, categories
+// and contacts in one transaction and returns its ID
+func SaveCompany(ctx context.Context, db *sqlx.DB, record *CompanyRecord) (int64, error) {
+	err := Transact(ctx, db, func(tx *sqlx.Tx) error {
+		records := []CompanyRecord{*record}
+		if err := NewCompanyRepository(tx).SaveBatch(ctx, records); err != nil {
+			return err
+		}
+		*record = records[0]
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return record.Company.ID, nil
+}
+
+// saveAllBatchSize is the default number of records SaveAll commits per
+// transaction
+const saveAllBatchSize = 1000
+
+// SaveAll saves records in transactions of up to batchSize records, so that
+// thousands of rows are written without a transaction per row. A failed
+// batch is rolled back and returned with the number of records saved
+// before it.
+func SaveAll(ctx context.Context, db *sqlx.DB, records []CompanyRecord, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = saveAllBatchSize
+	}
+
+	saved := 0
+	for start := 0; start < len(records); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return saved, err
+		}
+
+		batch := records[start:min(start+batchSize, len(records))]
+		err := Transact(ctx, db, func(tx *sqlx.Tx) error {
+			return NewCompanyRepository(tx).SaveBatch(ctx, batch)
+		})
+		if err != nil {
+			return saved, err
+		}
+		saved += len(batch)
+	}
+	return saved, nil
+}
+
+// Get returns the company with the given ID, wrapping sql.ErrNoRows when
+// there is none
+func (r *CompanyRepository) Get(ctx context.Context, id int64) (Company, error) {
+	var company Company
+	if err := sqlx.GetContext(ctx, r.db, &company, "SELECT * FROM companies WHERE id = ?", id); err != nil {
+		return company, fmt.Errorf("failed to get company %d: %w", id, err)
+	}
+	return company, nil
+}
+
+// GetByExternalID returns the company with the given external ID, wrapping
+// sql.ErrNoRows when there is none
+func (r *CompanyRepository) GetByExternalID(ctx context.Context, externalID string) (Company, error) {
+	var company Company
+	err := sqlx.GetContext(ctx, r.db, &company, "SELECT * FROM companies WHERE external_id = ?", externalID)
+	if err != nil {
+		return company, fmt.Errorf("failed to get company %s: %w", externalID, err)
+	}
+	return company, nil
+}
+
+// ListAddresses returns the addresses of a company, the primary one first
+func (r *CompanyRepository) ListAddresses(ctx context.Context, companyID int64) ([]Address, error) {
+	var addresses []Address
+	err := sqlx.SelectContext(ctx, r.db, &addresses,
+		"SELECT * FROM addresses WHERE company_id = ? ORDER BY is_primary DESC, id", companyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list addresses for company %d: %w", companyID, err)
+	}
+	return addresses, nil
+}
+
+// companyTables are the tables holding rows of a company, deleted with it
+// as SQLite does not enforce foreign keys on this connection
+var companyTables = []string{
+	"addresses", "people", "filings", "company_identifiers", "field_provenance",
+	"cluster_provenance", "quality_scores", "company_categories", "contacts", "lookup_attempts",
+}
+
+// Delete removes a company and the rows that belong to it, and reports
+// whether it existed. Callers bind the repository to a transaction so that
+// nothing is left half deleted.
+func (r *CompanyRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	for _, table := range companyTables {
+		if _, err := r.db.ExecContext(ctx, "DELETE FROM "+table+" WHERE company_id = ?", id); err != nil {
+			return false, fmt.Errorf("failed to delete %s of company %d: %w", table, id, err)
+		}
+	}
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM match_reviews WHERE company_id = ? OR candidate_id = ?", id, id); err != nil {
+		return false, fmt.Errorf("failed to delete match reviews of company %d: %w", id, err)
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM companies WHERE id = ?", id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete company %d: %w", id, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete company %d: %w", id, err)
+	}
+	return affected > 0, nil
+}
+
+// IDByExternalID returns the ID of the company with the given external ID,
+// wrapping sql.ErrNoRows when there is none
+func (r *CompanyRepository) IDByExternalID(ctx context.Context, externalID string) (int64, error) {
+	var id int64
+	err := r.db.QueryRowxContext(ctx, "SELECT id FROM companies WHERE external_id = ?", externalID).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find company %s: %w", externalID, err)
+	}
+	return id, nil
+}
+
+// UpdateStatus sets the normalized and reported status of the company with
+// the given external ID and reports whether such a company exists
+func (r *CompanyRepository) UpdateStatus(ctx context.Context, externalID, status, raw string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE companies SET status = ?, status_raw = ?, updated_at = CURRENT_TIMESTAMP WHERE external_id = ?",
+		status, NullString(raw), externalID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update status of company %s: %w", externalID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update status of company %s: %w", externalID, err)
+	}
+	return affected > 0, nil
+}
+
+// Count returns the number of companies
+func (r *CompanyRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := r.db.QueryRowxContext(ctx, "SELECT COUNT(*) FROM companies").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count companies: %w", err)
+	}
+	return count, nil
+}
+
+// FillBlanks sets the website, industry, employee count, founded year and
+// description of the company with company.ID where they are still empty,
+// and returns the names of the columns it filled
+func (r *CompanyRepository) FillBlanks(ctx context.Context, company Company) ([]string, error) {
+	var current Company
+	err := sqlx.GetContext(ctx, r.db, &current, `
+		SELECT website, industry, employee_count, founded_year, description
+		FROM companies WHERE id = ?`,
+		company.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fill blanks of company %d: %w", company.ID, err)
+	}
+
+	var filled []string
+	for _, column := range []struct {
+		name            string
+		empty, supplied bool
+	}{
+		{"website", !current.Website.Valid, company.Website.Valid},
+		{"industry", !current.Industry.Valid, company.Industry.Valid},
+		{"employee_count", !current.EmployeeCount.Valid, company.EmployeeCount.Valid},
+		{"founded_year", !current.FoundedYear.Valid, company.FoundedYear.Valid},
+		{"description", !current.Description.Valid, company.Description.Valid},
+	} {
+		if column.empty && column.supplied {
+			filled = append(filled, column.name)
+		}
+	}
+	if len(filled) == 0 {
+		return nil, nil
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE companies SET
+		    domain = CASE WHEN website IS NULL THEN ? ELSE domain END,
+		    website = COALESCE(website, ?),
+		    industry = COALESCE(industry, ?),
+		    employee_count = COALESCE(employee_count, ?),
+		    founded_year = COALESCE(founded_year, ?),
+		    description = COALESCE(description, ?),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`,
+		company.Domain, company.Website, company.Industry, company.EmployeeCount, company.FoundedYear, company.Description,
+		company.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fill blanks of company %d: %w", company.ID, err)
+	}
+
+	return filled, nil
+}
+
+// ListWithoutIdentifier returns up to limit companies that have no
+// identifier of the given scheme and still lack a website, industry,
+// employee count or founded year. Companies with a lookup attempt at or
+// after retryBefore are left out, and those never tried come first.
+func (r *CompanyRepository) ListWithoutIdentifier(ctx context.Context, scheme, lookup string, retryBefore time.Time, limit int) ([]Company, error) {
+	var companies []Company
+	err := sqlx.SelectContext(ctx, r.db, &companies, `
+		SELECT c.* FROM companies c
+		LEFT JOIN lookup_attempts la ON la.company_id = c.id AND la.lookup = ?
+		WHERE c.id NOT IN (SELECT company_id FROM company_identifiers WHERE scheme = ?)
+		  AND (c.website IS NULL OR c.industry IS NULL OR c.employee_count IS NULL OR c.founded_year IS NULL)
+		  AND (la.attempted_at IS NULL OR la.attempted_at < ?)
+		ORDER BY la.attempted_at IS NOT NULL, la.attempted_at, c.id
+		LIMIT ?`,
+		lookup, scheme, retryBefore.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies without %s identifier: %w", scheme, err)
+	}
+
+	return companies, nil
+}
+
+// ListByDomain returns the companies whose website is on a registrable
+// domain, e.g. "example.co.uk"
+func (r *CompanyRepository) ListByDomain(ctx context.Context, domain string) ([]Company, error) {
+	var companies []Company
+	err := sqlx.SelectContext(ctx, r.db, &companies,
+		"SELECT * FROM companies WHERE domain = ? ORDER BY id", domain,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies on domain %s: %w", domain, err)
+	}
+	return companies, nil
+}
+
+// SetWebsite stores the canonical website and domain of a company
+func (r *CompanyRepository) SetWebsite(ctx context.Context, companyID int64, website, domain string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE companies SET website = ?, domain = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`,
+		website, NullString(domain), companyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set website of company %d: %w", companyID, err)
+	}
+	return nil
+}
+
+// SetStatus stores the normalized status of a company
+func (r *CompanyRepository) SetStatus(ctx context.Context, companyID int64, status string) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE companies SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", status, companyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set status of company %d: %w", companyID, err)
+	}
+	return nil
+}
+
+// ListAfter returns up to limit companies with an ID above afterID, in ID
+// order, for walking the table in pages
+func (r *CompanyRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]Company, error) {
+	var companies []Company
+	err := sqlx.SelectContext(ctx, r.db, &companies,
+		"SELECT * FROM companies WHERE id > ? ORDER BY id LIMIT ?", afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies after %d: %w", afterID, err)
+	}
+	return companies, nil
+}
+
+// PrimaryAddresses returns the primary address of each of the given
+// companies that has one
+func (r *CompanyRepository) PrimaryAddresses(ctx context.Context, companyIDs []int64) (map[int64]Address, error) {
+	addresses := make(map[int64]Address)
+	if len(companyIDs) == 0 {
+		return addresses, nil
+	}
+
+	query, args, err := sqlx.In(
+		"SELECT * FROM addresses WHERE is_primary = 1 AND company_id IN (?) ORDER BY id", companyIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build primary address query: %w", err)
+	}
+
+	var rows []Address
+	if err := sqlx.SelectContext(ctx, r.db, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list primary addresses: %w", err)
+	}
+	for _, addr := range rows {
+		if _, ok := addresses[addr.CompanyID]; !ok {
+			addresses[addr.CompanyID] = addr
+		}
+	}
+	return addresses, nil
+}
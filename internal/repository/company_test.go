@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+)
+
+func TestSaveCompany(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	companies := NewCompanyRepository(db)
+
+	record := CompanyRecord{
+		Company: Company{ExternalID: NullString("ch_00445790"), Name: "Tesco PLC", DataSource: "companies_house"},
+		Addresses: []Address{
+			{AddressLine1: NullString("Tesco House"), City: NullString("Welwyn Garden City"), Country: NullString("United Kingdom"), IsPrimary: true},
+		},
+		Identifiers: []Identifier{{Scheme: SchemeRegistration, Value: "GB:00445790", DataSource: "companies_house"}},
+	}
+	id, err := SaveCompany(ctx, db, &record)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	company, err := companies.GetByExternalID(ctx, "ch_00445790")
+	if err != nil {
+		t.Fatalf("Expected company to be saved, got %v", err)
+	}
+	if company.ID != id || company.Status.String != "active" {
+		t.Errorf("Expected active company %d, got %+v", id, company)
+	}
+	addresses, _ := companies.ListAddresses(ctx, id)
+	if len(addresses) != 1 || addresses[0].City.String != "Welwyn Garden City" {
+		t.Errorf("Expected the address to be saved, got %+v", addresses)
+	}
+
+	// Saving the same external ID updates the row
+	record.Company.Name = "Tesco Plc"
+	record.Company.EmployeeCount = NullInt64(330000)
+	record.Addresses = nil
+	again, err := SaveCompany(ctx, db, &record)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	updated, _ := companies.Get(ctx, id)
+	if again != id || updated.Name != "Tesco Plc" || updated.EmployeeCount.Int64 != 330000 {
+		t.Errorf("Expected company %d to be updated, got %d: %+v", id, again, updated)
+	}
+	if updated.UpdatedAt.Before(company.UpdatedAt) {
+		t.Errorf("Expected updated_at to move forward, got %v after %v", updated.UpdatedAt, company.UpdatedAt)
+	}
+
+	deleted, err := companies.Delete(ctx, id)
+	if err != nil || !deleted {
+		t.Fatalf("Expected company to be deleted, got %v, %v", deleted, err)
+	}
+	if _, err := companies.Get(ctx, id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows after delete, got %v", err)
+	}
+	if ids, _ := NewIdentifierRepository(db).CompanyIDs(ctx, SchemeRegistration, "GB:00445790"); len(ids) != 0 {
+		t.Errorf("Expected identifiers to be deleted, got %v", ids)
+	}
+}
+
+func TestCompanyRepository_UpsertKeepsStatus(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	companies := NewCompanyRepository(db)
+
+	dissolved := Company{
+		ExternalID: NullString("ch_01234567"), Name: "Gone Ltd", DataSource: "companies_house",
+		Status: NullString("dissolved"), StatusRaw: NullString("dissolved"),
+	}
+	if _, err := companies.Upsert(ctx, &dissolved); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Sources such as EDGAR and Wikidata report no status
+	update := Company{ExternalID: NullString("ch_01234567"), Name: "Gone Ltd", DataSource: "wikidata"}
+	if _, err := SaveCompany(ctx, db, &CompanyRecord{Company: update}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	company, _ := companies.GetByExternalID(ctx, "ch_01234567")
+	if company.Status.String != "dissolved" || company.StatusRaw.String != "dissolved" {
+		t.Errorf("Expected status and raw status 'dissolved' to be kept, got '%s' and '%s'", company.Status.String, company.StatusRaw.String)
+	}
+
+	fresh := Company{ExternalID: NullString("test_new"), Name: "New Ltd", DataSource: "test"}
+	companies.Upsert(ctx, &fresh)
+	if company, _ := companies.Get(ctx, fresh.ID); company.Status.String != "active" {
+		t.Errorf("Expected new companies to default to 'active', got '%s'", company.Status.String)
+	}
+}
+
+func TestSaveCompany_KeepsAddresses(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	record := CompanyRecord{
+		Company: Company{ExternalID: NullString("ch_01234567"), Name: "Example Ltd", DataSource: "companies_house"},
+		Addresses: []Address{{
+			AddressLine1: NullString("1 High Street"),
+			City:         NullString("London"),
+			PostalCode:   NullString("EC1A 1BB"),
+			Country:      NullString("GB"),
+			IsPrimary:    true,
+		}},
+	}
+	id, err := SaveCompany(ctx, db, &record)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A record without addresses keeps the stored ones
+	update := CompanyRecord{Company: Company{ExternalID: NullString("ch_01234567"), Name: "Example Ltd", DataSource: "wikidata"}}
+	if _, err := SaveCompany(ctx, db, &update); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	addresses, err := NewCompanyRepository(db).ListAddresses(ctx, id)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(addresses) != 1 || addresses[0].PostalCode.String != "EC1A 1BB" {
+		t.Errorf("Expected the stored address to be kept, got %+v", addresses)
+	}
+}
+
+func TestSaveAll(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	records := make([]CompanyRecord, 2500)
+	for i := range records {
+		records[i] = CompanyRecord{Company: Company{
+			ExternalID: NullString(fmt.Sprintf("test_%d", i)),
+			Name:       fmt.Sprintf("Company %d", i),
+			DataSource: "test",
+		}}
+	}
+
+	saved, err := SaveAll(ctx, db, records, 1000)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	count, _ := NewCompanyRepository(db).Count(ctx)
+	if saved != 2500 || count != 2503 {
+		t.Errorf("Expected 2500 saved and 2503 companies, got %d and %d", saved, count)
+	}
+}
+
+func TestCompanyRepository_Find(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	companies := NewCompanyRepository(db)
+
+	records := []CompanyRecord{
+		{
+			Company:   Company{ExternalID: NullString("test_1"), Name: "100%_Pure Ltd", DataSource: "test", EmployeeCount: NullInt64(12)},
+			Addresses: []Address{{City: NullString("London"), Country: NullString("UK"), IsPrimary: true}},
+		},
+		{
+			Company:   Company{ExternalID: NullString("test_2"), Name: "Pure Foods Ltd", DataSource: "test", EmployeeCount: NullInt64(500)},
+			Addresses: []Address{{City: NullString("Leeds"), Country: NullString("GB"), IsPrimary: true}},
+		},
+	}
+	if _, err := SaveAll(ctx, db, records, 0); err != nil {
+		t.Fatalf("Failed to save companies: %v", err)
+	}
+
+	tests := []struct {
+		query CompanyQuery
+		want  []string
+	}{
+		{CompanyQuery{Country: "US"}, []string{"Apple", "Google", "Microsoft"}},
+		{CompanyQuery{Country: "United Kingdom", Sort: "-employee_count"}, []string{"Pure Foods Ltd", "100%_Pure Ltd"}},
+		{CompanyQuery{Search: "%_"}, []string{"100%_Pure Ltd"}},
+		{CompanyQuery{Search: "pure", MinEmployees: 100}, []string{"Pure Foods Ltd"}},
+		{CompanyQuery{Industry: "technology", Sort: "-name", Limit: 2}, []string{"Microsoft", "Google"}},
+		{CompanyQuery{DataSource: "test", Sort: "id", Offset: 1}, []string{"Pure Foods Ltd"}},
+	}
+
+	for _, tt := range tests {
+		found, _, err := companies.Find(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("Expected no error for %+v, got %v", tt.query, err)
+		}
+		var names []string
+		for _, company := range found {
+			names = append(names, company.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(tt.want) {
+			t.Errorf("Expected %v for %+v, got %v", tt.want, tt.query, names)
+		}
+	}
+
+	if _, total, _ := companies.Find(ctx, CompanyQuery{Limit: 1}); total != 5 {
+		t.Errorf("Expected a total of 5, got %d", total)
+	}
+	for _, query := range []CompanyQuery{{Sort: "name; DROP TABLE companies"}, {Country: "Atlantis"}, {Limit: -1}} {
+		if _, _, err := companies.Find(ctx, query); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for %+v, got %v", query, err)
+		}
+	}
+}
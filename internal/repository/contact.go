@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Verification statuses of contacts, from the email checks of their address
+const (
+	ContactUnverified = "unverified"
+	// ContactVerified addresses are deliverable
+	ContactVerified = "verified"
+	// ContactRisky addresses are valid but not known to be deliverable, e.g.
+	// on catch-all or disposable domains
+	ContactRisky   = "risky"
+	ContactInvalid = "invalid"
+)
+
+// Suppression kinds
+const (
+	SuppressEmail  = "email"
+	SuppressDomain = "domain"
+)
+
+// Contact represents a row in the contacts table: a person or role-based
+// contact point of a company
+type Contact struct {
+	ID                 int64           `db:"id"`
+	ExternalID         string          `db:"external_id"`
+	CompanyID          int64           `db:"company_id"`
+	Kind               string          `db:"kind"`
+	Name               sql.NullString  `db:"name"`
+	JobTitle           sql.NullString  `db:"job_title"`
+	Department         sql.NullString  `db:"department"`
+	Email              sql.NullString  `db:"email"`
+	Phone              sql.NullString  `db:"phone"`
+	VerificationStatus string          `db:"verification_status"`
+	VerificationScore  sql.NullFloat64 `db:"verification_score"`
+	VerifiedAt         sql.NullTime    `db:"verified_at"`
+	// OptedOut is set when the contact asked not to be contacted; its email
+	// is then also suppressed
+	OptedOut   bool         `db:"opted_out"`
+	OptedOutAt sql.NullTime `db:"opted_out_at"`
+	DataSource string       `db:"data_source"`
+	// SourceRecordID and CollectedAt identify the source record the contact
+	// came with
+	SourceRecordID sql.NullString `db:"source_record_id"`
+	CollectedAt    sql.NullTime   `db:"collected_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+// Suppression represents a row in the suppressions table: an email address
+// or domain that must not be contacted or exported
+type Suppression struct {
+	Value     string    `db:"value"`
+	Kind      string    `db:"kind"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ContactRepository reads and writes the contacts and suppressions tables
+type ContactRepository struct {
+	db DBTX
+}
+
+// NewContactRepository creates a contact repository on a connection or transaction
+func NewContactRepository(db DBTX) *ContactRepository {
+	return &ContactRepository{db: db}
+}
+
+// Upsert inserts a contact or updates the existing row with the same
+// external ID. Opt-outs are kept, and the verification is kept unless the
+// email changed.
+func (r *ContactRepository) Upsert(ctx context.Context, contact *Contact) error {
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO contacts (
+		    external_id, company_id, kind, name, job_title, department, email, phone,
+		    data_source, source_record_id, collected_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(external_id) DO UPDATE SET
+		    company_id = excluded.company_id,
+		    kind = excluded.kind,
+		    name = excluded.name,
+		    job_title = excluded.job_title,
+		    department = excluded.department,
+		    verification_status = CASE WHEN contacts.email IS excluded.email
+		        THEN contacts.verification_status ELSE 'unverified' END,
+		    verification_score = CASE WHEN contacts.email IS excluded.email
+		        THEN contacts.verification_score END,
+		    verified_at = CASE WHEN contacts.email IS excluded.email
+		        THEN contacts.verified_at END,
+		    email = excluded.email,
+		    phone = excluded.phone,
+		    data_source = excluded.data_source,
+		    source_record_id = excluded.source_record_id,
+		    collected_at = excluded.collected_at,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING id`,
+		contact.ExternalID, contact.CompanyID, contact.Kind, contact.Name, contact.JobTitle,
+		contact.Department, contact.Email, contact.Phone, contact.DataSource,
+		contact.SourceRecordID, contact.CollectedAt,
+	).Scan(&contact.ID)
+	if err != nil {
+		return fmt.Errorf("failed to upsert contact %s: %w", contact.ExternalID, err)
+	}
+
+	return nil
+}
+
+// ListByCompany returns all contacts of a company, people first
+func (r *ContactRepository) ListByCompany(ctx context.Context, companyID int64) ([]Contact, error) {
+	var contacts []Contact
+	err := sqlx.SelectContext(ctx, r.db, &contacts, `
+		SELECT * FROM contacts
+		WHERE company_id = ?
+		ORDER BY kind, name, email`,
+		companyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contacts for company %d: %w", companyID, err)
+	}
+
+	return contacts, nil
+}
+
+// ListExportable returns the contacts of a company that may be exported:
+// not opted out, without a suppressed email address or domain, and not
+// known to be invalid
+func (r *ContactRepository) ListExportable(ctx context.Context, companyID int64) ([]Contact, error) {
+	var contacts []Contact
+	err := sqlx.SelectContext(ctx, r.db, &contacts, `
+		SELECT c.* FROM contacts c
+		WHERE c.company_id = ?
+		  AND NOT c.opted_out
+		  AND c.verification_status != 'invalid'
+		  AND NOT EXISTS (
+		      SELECT 1 FROM suppressions s
+		      WHERE (s.kind = 'email' AND s.value = LOWER(c.email))
+		         OR (s.kind = 'domain' AND s.value = LOWER(SUBSTR(c.email, INSTR(c.email, '@') + 1)))
+		  )
+		ORDER BY c.kind, c.name, c.email`,
+		companyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exportable contacts for company %d: %w", companyID, err)
+	}
+
+	return contacts, nil
+}
+
+// ListUnverifiedEmails returns up to limit distinct email addresses of
+// contacts that have not been verified and have not opted out
+func (r *ContactRepository) ListUnverifiedEmails(ctx context.Context, limit int) ([]string, error) {
+	var emails []string
+	err := sqlx.SelectContext(ctx, r.db, &emails, `
+		SELECT DISTINCT email FROM contacts
+		WHERE email IS NOT NULL AND verification_status = 'unverified' AND NOT opted_out
+		ORDER BY email
+		LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unverified contact emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// SetVerification records the verification of an email address on every
+// contact using it and returns how many were updated
+func (r *ContactRepository) SetVerification(ctx context.Context, email, status string, score float64, verifiedAt time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE contacts SET
+		    verification_status = ?, verification_score = ?, verified_at = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE email = ?`,
+		status, score, verifiedAt.UTC(), email,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to set verification of %s: %w", email, err)
+	}
+	return result.RowsAffected()
+}
+
+// OptOut suppresses an email address and marks the contacts using it as
+// opted out, returning how many contacts were marked
+func (r *ContactRepository) OptOut(ctx context.Context, email, reason string) (int64, error) {
+	if err := r.Suppress(ctx, email, reason); err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE contacts SET
+		    opted_out = TRUE, opted_out_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE LOWER(email) = ? AND NOT opted_out`,
+		strings.ToLower(strings.TrimSpace(email)),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to opt out %s: %w", email, err)
+	}
+	return result.RowsAffected()
+}
+
+// Suppress adds an email address, or a domain when value has no "@", to the
+// suppression list. Values are compared without case.
+func (r *ContactRepository) Suppress(ctx context.Context, value, reason string) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+	kind := SuppressDomain
+	if strings.Contains(value, "@") {
+		kind = SuppressEmail
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO suppressions (value, kind, reason) VALUES (?, ?, ?)
+		ON CONFLICT(value) DO UPDATE SET reason = excluded.reason`,
+		value, kind, reason,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to suppress %s: %w", value, err)
+	}
+	return nil
+}
+
+// IsSuppressed reports whether an email address or its domain is suppressed
+func (r *ContactRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	_, domain, _ := strings.Cut(email, "@")
+
+	var count int
+	err := r.db.QueryRowxContext(ctx, `
+		SELECT COUNT(*) FROM suppressions
+		WHERE (kind = 'email' AND value = ?) OR (kind = 'domain' AND value = ?)`,
+		email, domain,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check suppression of %s: %w", email, err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EmailVerification represents a row in the email_verifications table
+type EmailVerification struct {
+	Email           string    `db:"email"`
+	Domain          string    `db:"domain"`
+	IsValid         bool      `db:"is_valid"`
+	IsDeliverable   bool      `db:"is_deliverable"`
+	Score           float64   `db:"score"`
+	SyntaxValid     bool      `db:"syntax_valid"`
+	DomainExists    bool      `db:"domain_exists"`
+	MXRecordExists  bool      `db:"mx_record_exists"`
+	SMTPConnectable bool      `db:"smtp_connectable"`
+	Mailbox         string    `db:"mailbox"`
+	IsCatchAll      bool      `db:"is_catch_all"`
+	IsDisposable    bool      `db:"is_disposable"`
+	IsRoleAccount   bool      `db:"is_role_account"`
+	VerifiedAt      time.Time `db:"verified_at"`
+	ExpiresAt       time.Time `db:"expires_at"`
+}
+
+// EmailVerificationRepository reads and writes the email_verifications table
+type EmailVerificationRepository struct {
+	db DBTX
+}
+
+// NewEmailVerificationRepository creates an email verification repository on a connection or transaction
+func NewEmailVerificationRepository(db DBTX) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+// GetFresh returns the cached verification of an email address if it has
+// not expired at now, reporting false when there is none
+func (r *EmailVerificationRepository) GetFresh(ctx context.Context, email string, now time.Time) (EmailVerification, bool, error) {
+	var verification EmailVerification
+	err := r.db.QueryRowxContext(ctx, `
+		SELECT * FROM email_verifications
+		WHERE email = ? AND expires_at > ?`,
+		email, now.UTC(),
+	).StructScan(&verification)
+	if errors.Is(err, sql.ErrNoRows) {
+		return verification, false, nil
+	}
+	if err != nil {
+		return verification, false, fmt.Errorf("failed to load email verification %s: %w", email, err)
+	}
+
+	return verification, true, nil
+}
+
+// Save stores a verification, replacing any earlier one of the same address
+func (r *EmailVerificationRepository) Save(ctx context.Context, v EmailVerification) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO email_verifications (
+		    email, domain, is_valid, is_deliverable, score, syntax_valid, domain_exists,
+		    mx_record_exists, smtp_connectable, mailbox, is_catch_all, is_disposable,
+		    is_role_account, verified_at, expires_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(email) DO UPDATE SET
+		    is_valid = excluded.is_valid,
+		    is_deliverable = excluded.is_deliverable,
+		    score = excluded.score,
+		    syntax_valid = excluded.syntax_valid,
+		    domain_exists = excluded.domain_exists,
+		    mx_record_exists = excluded.mx_record_exists,
+		    smtp_connectable = excluded.smtp_connectable,
+		    mailbox = excluded.mailbox,
+		    is_catch_all = excluded.is_catch_all,
+		    is_disposable = excluded.is_disposable,
+		    is_role_account = excluded.is_role_account,
+		    verified_at = excluded.verified_at,
+		    expires_at = excluded.expires_at`,
+		v.Email, v.Domain, v.IsValid, v.IsDeliverable, v.Score, v.SyntaxValid, v.DomainExists,
+		v.MXRecordExists, v.SMTPConnectable, v.Mailbox, v.IsCatchAll, v.IsDisposable,
+		v.IsRoleAccount, v.VerifiedAt.UTC(), v.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save email verification %s: %w", v.Email, err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes verifications that expired before now and returns
+// how many were removed
+func (r *EmailVerificationRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM email_verifications WHERE expires_at <= ?", now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired email verifications: %w", err)
+	}
+	return result.RowsAffected()
+}
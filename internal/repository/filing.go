@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Filing represents a row in the filings table
+type Filing struct {
+	ID            int64          `db:"id"`
+	ExternalID    string         `db:"external_id"`
+	CompanyID     int64          `db:"company_id"`
+	TransactionID sql.NullString `db:"transaction_id"`
+	Category      sql.NullString `db:"category"`
+	Type          sql.NullString `db:"type"`
+	Description   sql.NullString `db:"description"`
+	FilingDate    sql.NullString `db:"filing_date"`
+	ActionDate    sql.NullString `db:"action_date"`
+	Pages         sql.NullInt64  `db:"pages"`
+	DataSource    string         `db:"data_source"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+}
+
+// FilingRepository reads and writes the filings table
+type FilingRepository struct {
+	db DBTX
+}
+
+// NewFilingRepository creates a filing repository on a connection or transaction
+func NewFilingRepository(db DBTX) *FilingRepository {
+	return &FilingRepository{db: db}
+}
+
+// Upsert inserts a filing or updates the existing row with the same external ID
+func (r *FilingRepository) Upsert(ctx context.Context, filing *Filing) error {
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO filings (
+		    external_id, company_id, transaction_id, category, type, description,
+		    filing_date, action_date, pages, data_source
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(external_id) DO UPDATE SET
+		    company_id = excluded.company_id,
+		    transaction_id = excluded.transaction_id,
+		    category = excluded.category,
+		    type = excluded.type,
+		    description = excluded.description,
+		    filing_date = excluded.filing_date,
+		    action_date = excluded.action_date,
+		    pages = excluded.pages,
+		    data_source = excluded.data_source,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING id`,
+		filing.ExternalID, filing.CompanyID, filing.TransactionID, filing.Category, filing.Type,
+		filing.Description, filing.FilingDate, filing.ActionDate, filing.Pages, filing.DataSource,
+	).Scan(&filing.ID)
+	if err != nil {
+		return fmt.Errorf("failed to upsert filing %s: %w", filing.ExternalID, err)
+	}
+
+	return nil
+}
+
+// ListByCompany returns the filings of a company, newest first
+func (r *FilingRepository) ListByCompany(ctx context.Context, companyID int64, limit int) ([]Filing, error) {
+	var filings []Filing
+	err := sqlx.SelectContext(ctx, r.db, &filings, `
+		SELECT * FROM filings
+		WHERE company_id = ?
+		ORDER BY filing_date DESC
+		LIMIT ?`,
+		companyID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list filings for company %d: %w", companyID, err)
+	}
+
+	return filings, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stkisengese/B2B-Data-Platform/internal/model"
+)
+
+// Identifier schemes stored in the company_identifiers table
+const (
+	// SchemeRegistration is a registry number qualified by its jurisdiction,
+	// see model.RegistrationValue
+	SchemeRegistration = model.IdentifierRegistration
+	// SchemeLEI is a Legal Entity Identifier
+	SchemeLEI = model.IdentifierLEI
+	// SchemeVAT is an EU VAT ID with its country prefix, e.g. "DE123456789"
+	SchemeVAT = model.IdentifierVAT
+	// SchemeWikidata is a Wikidata item ID, e.g. "Q312"
+	SchemeWikidata = model.IdentifierWikidata
+)
+
+// Identifier represents a row in the company_identifiers table
+type Identifier struct {
+	ID         int64     `db:"id"`
+	CompanyID  int64     `db:"company_id"`
+	Scheme     string    `db:"scheme"`
+	Value      string    `db:"value"`
+	DataSource string    `db:"data_source"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// IdentifierRepository reads and writes the company_identifiers table
+type IdentifierRepository struct {
+	db DBTX
+}
+
+// NewIdentifierRepository creates an identifier repository on a connection or transaction
+func NewIdentifierRepository(db DBTX) *IdentifierRepository {
+	return &IdentifierRepository{db: db}
+}
+
+// Attach records an identifier of a company. Attaching an identifier the
+// company already has only refreshes its source and timestamp.
+func (r *IdentifierRepository) Attach(ctx context.Context, identifier *Identifier) error {
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO company_identifiers (company_id, scheme, value, data_source)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(company_id, scheme, value) DO UPDATE SET
+		    data_source = excluded.data_source,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING id`,
+		identifier.CompanyID, identifier.Scheme, identifier.Value, identifier.DataSource,
+	).Scan(&identifier.ID)
+	if err != nil {
+		return fmt.Errorf("failed to attach %s identifier %s to company %d: %w",
+			identifier.Scheme, identifier.Value, identifier.CompanyID, err)
+	}
+
+	return nil
+}
+
+// CompanyIDs returns the IDs of the companies carrying an identifier
+func (r *IdentifierRepository) CompanyIDs(ctx context.Context, scheme, value string) ([]int64, error) {
+	var ids []int64
+	err := sqlx.SelectContext(ctx, r.db, &ids, `
+		SELECT company_id FROM company_identifiers
+		WHERE scheme = ? AND value = ?
+		ORDER BY company_id`,
+		scheme, value,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find companies with %s identifier %s: %w", scheme, value, err)
+	}
+
+	return ids, nil
+}
+
+// ListByCompany returns the identifiers of a company
+func (r *IdentifierRepository) ListByCompany(ctx context.Context, companyID int64) ([]Identifier, error) {
+	var identifiers []Identifier
+	err := sqlx.SelectContext(ctx, r.db, &identifiers, `
+		SELECT * FROM company_identifiers
+		WHERE company_id = ?
+		ORDER BY scheme, value`,
+		companyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identifiers for company %d: %w", companyID, err)
+	}
+
+	return identifiers, nil
+}
+
+// CompaniesWithScheme returns which of the given companies have an
+// identifier of a scheme
+func (r *IdentifierRepository) CompaniesWithScheme(ctx context.Context, scheme string, companyIDs []int64) (map[int64]bool, error) {
+	found := make(map[int64]bool)
+	if len(companyIDs) == 0 {
+		return found, nil
+	}
+
+	query, args, err := sqlx.In(
+		"SELECT DISTINCT company_id FROM company_identifiers WHERE scheme = ? AND company_id IN (?)", scheme, companyIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s identifier query: %w", scheme, err)
+	}
+
+	var ids []int64
+	if err := sqlx.SelectContext(ctx, r.db, &ids, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find companies with %s identifier: %w", scheme, err)
+	}
+	for _, id := range ids {
+		found[id] = true
+	}
+	return found, nil
+}
+
+// ListMissing returns the registration identifiers of companies that have
+// no identifier of the given scheme yet, up to limit rows. Companies with a
+// lookup attempt at or after retryBefore are left out, and those never
+// tried come first, followed by the longest ago tried.
+func (r *IdentifierRepository) ListMissing(ctx context.Context, scheme, lookup string, retryBefore time.Time, limit int) ([]Identifier, error) {
+	var identifiers []Identifier
+	err := sqlx.SelectContext(ctx, r.db, &identifiers, `
+		SELECT ci.* FROM company_identifiers ci
+		LEFT JOIN lookup_attempts la ON la.company_id = ci.company_id AND la.lookup = ?
+		WHERE ci.scheme = ?
+		  AND ci.company_id NOT IN (SELECT company_id FROM company_identifiers WHERE scheme = ?)
+		  AND (la.attempted_at IS NULL OR la.attempted_at < ?)
+		ORDER BY la.attempted_at IS NOT NULL, la.attempted_at, ci.company_id
+		LIMIT ?`,
+		lookup, SchemeRegistration, scheme, retryBefore.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list companies without %s identifier: %w", scheme, err)
+	}
+
+	return identifiers, nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PostcodeCentroid represents a row in the postcode_centroids table.
+// Postcodes are stored uppercased without spaces, e.g. "EC1A1BB".
+type PostcodeCentroid struct {
+	Country   string         `db:"country"`
+	Postcode  string         `db:"postcode"`
+	Latitude  float64        `db:"latitude"`
+	Longitude float64        `db:"longitude"`
+	PlaceName sql.NullString `db:"place_name"`
+	Region    sql.NullString `db:"region"`
+	Source    string         `db:"source"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+// Centroid is the average position of a set of postcode centroids
+type Centroid struct {
+	Latitude  float64 `db:"latitude"`
+	Longitude float64 `db:"longitude"`
+	Postcodes int     `db:"postcodes"`
+}
+
+// LocationRepository reads and writes postcode centroids and the
+// coordinates of addresses
+type LocationRepository struct {
+	db DBTX
+}
+
+// NewLocationRepository creates a location repository on a connection or transaction
+func NewLocationRepository(db DBTX) *LocationRepository {
+	return &LocationRepository{db: db}
+}
+
+// SaveCentroids inserts postcode centroids or replaces existing ones
+func (r *LocationRepository) SaveCentroids(ctx context.Context, centroids []PostcodeCentroid) error {
+	for _, c := range centroids {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO postcode_centroids (country, postcode, latitude, longitude, place_name, region, source)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(country, postcode) DO UPDATE SET
+			    latitude = excluded.latitude,
+			    longitude = excluded.longitude,
+			    place_name = COALESCE(excluded.place_name, postcode_centroids.place_name),
+			    region = COALESCE(excluded.region, postcode_centroids.region),
+			    source = excluded.source,
+			    updated_at = CURRENT_TIMESTAMP`,
+			c.Country, c.Postcode, c.Latitude, c.Longitude, c.PlaceName, c.Region, c.Source,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save centroid of %s %s: %w", c.Country, c.Postcode, err)
+		}
+	}
+	return nil
+}
+
+// Centroid returns the centroid of a postcode, wrapping sql.ErrNoRows when
+// it is not known
+func (r *LocationRepository) Centroid(ctx context.Context, country, postcode string) (PostcodeCentroid, error) {
+	var c PostcodeCentroid
+	err := sqlx.GetContext(ctx, r.db, &c,
+		"SELECT * FROM postcode_centroids WHERE country = ? AND postcode = ?", country, postcode,
+	)
+	if err != nil {
+		return c, fmt.Errorf("failed to get centroid of %s %s: %w", country, postcode, err)
+	}
+	return c, nil
+}
+
+// AreaCentroid averages the centroids of the postcodes matching a GLOB
+// pattern, e.g. "EC1A???" for a UK postcode district
+func (r *LocationRepository) AreaCentroid(ctx context.Context, country, pattern string) (Centroid, error) {
+	return r.average(ctx, "postcode GLOB ?", country, pattern)
+}
+
+// PlaceCentroid averages the centroids of the postcodes of a place
+func (r *LocationRepository) PlaceCentroid(ctx context.Context, country, place string) (Centroid, error) {
+	return r.average(ctx, "place_name = ? COLLATE NOCASE", country, place)
+}
+
+// average averages the centroids matching a condition on one argument;
+// Postcodes is zero when none match
+func (r *LocationRepository) average(ctx context.Context, condition, country, arg string) (Centroid, error) {
+	var c Centroid
+	err := sqlx.GetContext(ctx, r.db, &c, `
+		SELECT COALESCE(AVG(latitude), 0) AS latitude,
+		       COALESCE(AVG(longitude), 0) AS longitude,
+		       COUNT(*) AS postcodes
+		FROM postcode_centroids
+		WHERE country = ? AND `+condition, country, arg)
+	if err != nil {
+		return c, fmt.Errorf("failed to average centroids in %s: %w", country, err)
+	}
+	return c, nil
+}
+
+// CountCentroids returns the number of postcode centroids per country
+func (r *LocationRepository) CountCentroids(ctx context.Context) (map[string]int, error) {
+	var rows []struct {
+		Country string `db:"country"`
+		Count   int    `db:"count"`
+	}
+	err := sqlx.SelectContext(ctx, r.db, &rows,
+		"SELECT country, COUNT(*) AS count FROM postcode_centroids GROUP BY country",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count postcode centroids: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Country] = row.Count
+	}
+	return counts, nil
+}
+
+// ListUngeocoded returns up to limit addresses that have no coordinates
+// and have not been geocoded yet, in ID order
+func (r *LocationRepository) ListUngeocoded(ctx context.Context, limit int) ([]Address, error) {
+	var addresses []Address
+	err := sqlx.SelectContext(ctx, r.db, &addresses, `
+		SELECT * FROM addresses
+		WHERE latitude IS NULL AND geocoded_at IS NULL
+		ORDER BY id
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ungeocoded addresses: %w", err)
+	}
+	return addresses, nil
+}
+
+// SetLocation stores the coordinates of an address. Addresses that could not
+// be geocoded are recorded with a NULL precision so they are not retried.
+func (r *LocationRepository) SetLocation(ctx context.Context, addressID int64, latitude, longitude sql.NullFloat64, precision, source string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE addresses SET
+		    latitude = ?, longitude = ?, geocode_precision = ?, geocode_source = ?, geocoded_at = ?
+		WHERE id = ?`,
+		latitude, longitude, NullString(precision), NullString(source), time.Now().UTC(), addressID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set location of address %d: %w", addressID, err)
+	}
+	return nil
+}
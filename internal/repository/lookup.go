@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Lookups recorded in the lookup_attempts table
+const (
+	LookupGLEIF    = "gleif"
+	LookupWikidata = "wikidata"
+)
+
+// LookupRepository records when companies were last looked up in an
+// external register, so that a company without a match is not looked up
+// again on every run ahead of the companies never tried
+type LookupRepository struct {
+	db DBTX
+}
+
+// NewLookupRepository creates a lookup attempt repository on a connection or transaction
+func NewLookupRepository(db DBTX) *LookupRepository {
+	return &LookupRepository{db: db}
+}
+
+// RecordAttempt stores that the companies were looked up at the given time
+func (r *LookupRepository) RecordAttempt(ctx context.Context, lookup string, at time.Time, companyIDs ...int64) error {
+	for _, id := range companyIDs {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO lookup_attempts (company_id, lookup, attempted_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT(company_id, lookup) DO UPDATE SET attempted_at = excluded.attempted_at`,
+			id, lookup, at.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record %s lookup of company %d: %w", lookup, id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Kinds of people linked to a company
+const (
+	PersonKindOfficer = "officer"
+	PersonKindPSC     = "psc"
+)
+
+// Person represents a row in the people table: an officer or a person with
+// significant control of a company
+type Person struct {
+	ID                 int64          `db:"id"`
+	ExternalID         string         `db:"external_id"`
+	CompanyID          int64          `db:"company_id"`
+	Kind               string         `db:"kind"`
+	Name               string         `db:"name"`
+	Role               sql.NullString `db:"role"`
+	Nationality        sql.NullString `db:"nationality"`
+	CountryOfResidence sql.NullString `db:"country_of_residence"`
+	Occupation         sql.NullString `db:"occupation"`
+	BirthMonth         sql.NullInt64  `db:"birth_month"`
+	BirthYear          sql.NullInt64  `db:"birth_year"`
+	NaturesOfControl   sql.NullString `db:"natures_of_control"`
+	StartedOn          sql.NullString `db:"started_on"`
+	EndedOn            sql.NullString `db:"ended_on"`
+	DataSource         string         `db:"data_source"`
+	CreatedAt          time.Time      `db:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at"`
+}
+
+// PersonRepository reads and writes the people table
+type PersonRepository struct {
+	db DBTX
+}
+
+// NewPersonRepository creates a person repository on a connection or transaction
+func NewPersonRepository(db DBTX) *PersonRepository {
+	return &PersonRepository{db: db}
+}
+
+// Upsert inserts a person or updates the existing row with the same external ID
+func (r *PersonRepository) Upsert(ctx context.Context, person *Person) error {
+	err := r.db.QueryRowxContext(ctx, `
+		INSERT INTO people (
+		    external_id, company_id, kind, name, role, nationality, country_of_residence,
+		    occupation, birth_month, birth_year, natures_of_control, started_on, ended_on, data_source
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(external_id) DO UPDATE SET
+		    company_id = excluded.company_id,
+		    kind = excluded.kind,
+		    name = excluded.name,
+		    role = excluded.role,
+		    nationality = excluded.nationality,
+		    country_of_residence = excluded.country_of_residence,
+		    occupation = excluded.occupation,
+		    birth_month = excluded.birth_month,
+		    birth_year = excluded.birth_year,
+		    natures_of_control = excluded.natures_of_control,
+		    started_on = excluded.started_on,
+		    ended_on = excluded.ended_on,
+		    data_source = excluded.data_source,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING id`,
+		person.ExternalID, person.CompanyID, person.Kind, person.Name, person.Role,
+		person.Nationality, person.CountryOfResidence, person.Occupation, person.BirthMonth,
+		person.BirthYear, person.NaturesOfControl, person.StartedOn, person.EndedOn, person.DataSource,
+	).Scan(&person.ID)
+	if err != nil {
+		return fmt.Errorf("failed to upsert person %s: %w", person.ExternalID, err)
+	}
+
+	return nil
+}
+
+// ListByCompany returns the people linked to a company, current ones first
+func (r *PersonRepository) ListByCompany(ctx context.Context, companyID int64) ([]Person, error) {
+	var people []Person
+	err := sqlx.SelectContext(ctx, r.db, &people, `
+		SELECT * FROM people
+		WHERE company_id = ?
+		ORDER BY ended_on IS NOT NULL, kind, name`,
+		companyID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list people for company %d: %w", companyID, err)
+	}
+
+	return people, nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Provenance represents a row in the field_provenance table: the source
+// record that supplied the current value of one company column
+type Provenance struct {
+	CompanyID      int64          `db:"company_id"`
+	Field          string         `db:"field"`
+	DataSource     string         `db:"data_source"`
+	SourceRecordID sql.NullString `db:"source_record_id"`
+	CollectedAt    time.Time      `db:"collected_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
+
+// ClusterProvenance represents a row in the cluster_provenance table: the
+// member company whose value a golden record field was taken from, and the
+// survivorship rule that chose it
+type ClusterProvenance struct {
+	ClusterID      int64          `db:"cluster_id"`
+	Field          string         `db:"field"`
+	CompanyID      int64          `db:"company_id"`
+	DataSource     string         `db:"data_source"`
+	SourceRecordID sql.NullString `db:"source_record_id"`
+	CollectedAt    time.Time      `db:"collected_at"`
+	Rule           string         `db:"rule"`
+}
+
+// ProvenanceRepository reads and writes the field_provenance and
+// cluster_provenance tables
+type ProvenanceRepository struct {
+	db DBTX
+}
+
+// NewProvenanceRepository creates a provenance repository on a connection or transaction
+func NewProvenanceRepository(db DBTX) *ProvenanceRepository {
+	return &ProvenanceRepository{db: db}
+}
+
+// Record stores that a source record supplied the given fields of a
+// company. A zero collectedAt is recorded as the current time.
+func (r *ProvenanceRepository) Record(ctx context.Context, companyID int64, fields []string, source, recordID string, collectedAt time.Time) error {
+	if collectedAt.IsZero() {
+		collectedAt = time.Now()
+	}
+
+	for _, field := range fields {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO field_provenance (company_id, field, data_source, source_record_id, collected_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(company_id, field) DO UPDATE SET
+			    data_source = excluded.data_source,
+			    source_record_id = excluded.source_record_id,
+			    collected_at = excluded.collected_at,
+			    updated_at = CURRENT_TIMESTAMP`,
+			companyID, field, source, NullString(recordID), collectedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record provenance of %s for company %d: %w", field, companyID, err)
+		}
+	}
+
+	return nil
+}
+
+// ListByCompanies returns the provenance of every field of the given companies
+func (r *ProvenanceRepository) ListByCompanies(ctx context.Context, companyIDs []int64) ([]Provenance, error) {
+	if len(companyIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(
+		"SELECT * FROM field_provenance WHERE company_id IN (?) ORDER BY company_id, field", companyIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build provenance query: %w", err)
+	}
+
+	var provenance []Provenance
+	if err := sqlx.SelectContext(ctx, r.db, &provenance, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list field provenance: %w", err)
+	}
+	return provenance, nil
+}
+
+// LatestCollected returns, for each of the given companies with recorded
+// provenance, when its most recently collected value was collected
+func (r *ProvenanceRepository) LatestCollected(ctx context.Context, companyIDs []int64) (map[int64]time.Time, error) {
+	latest := make(map[int64]time.Time)
+	if len(companyIDs) == 0 {
+		return latest, nil
+	}
+
+	query, args, err := sqlx.In(
+		"SELECT company_id, collected_at FROM field_provenance WHERE company_id IN (?)", companyIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build collection time query: %w", err)
+	}
+
+	var rows []Provenance
+	if err := sqlx.SelectContext(ctx, r.db, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list collection times: %w", err)
+	}
+	for _, row := range rows {
+		if row.CollectedAt.After(latest[row.CompanyID]) {
+			latest[row.CompanyID] = row.CollectedAt
+		}
+	}
+	return latest, nil
+}
+
+// ReplaceCluster replaces the provenance of a cluster's golden record
+func (r *ProvenanceRepository) ReplaceCluster(ctx context.Context, clusterID int64, provenance []ClusterProvenance) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM cluster_provenance WHERE cluster_id = ?", clusterID); err != nil {
+		return fmt.Errorf("failed to delete provenance of cluster %d: %w", clusterID, err)
+	}
+
+	for _, p := range provenance {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO cluster_provenance (cluster_id, field, company_id, data_source, source_record_id, collected_at, rule)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			clusterID, p.Field, p.CompanyID, p.DataSource, p.SourceRecordID, p.CollectedAt.UTC(), p.Rule,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record provenance of %s for cluster %d: %w", p.Field, clusterID, err)
+		}
+	}
+
+	return nil
+}
+
+// ListByCluster returns the provenance of a cluster's golden record
+func (r *ProvenanceRepository) ListByCluster(ctx context.Context, clusterID int64) ([]ClusterProvenance, error) {
+	var provenance []ClusterProvenance
+	err := sqlx.SelectContext(ctx, r.db, &provenance,
+		"SELECT * FROM cluster_provenance WHERE cluster_id = ? ORDER BY field", clusterID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list provenance of cluster %d: %w", clusterID, err)
+	}
+	return provenance, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Quality report groupings
+const (
+	ReportBySource  = "source"
+	ReportByCountry = "country"
+)
+
+// QualityScore represents a row in the quality_scores table. Score ranges
+// from 0 to 100; the dimensions it is computed from range from 0 to 1.
+type QualityScore struct {
+	CompanyID    int64          `db:"company_id"`
+	Score        float64        `db:"score"`
+	Completeness float64        `db:"completeness"`
+	Validity     float64        `db:"validity"`
+	Freshness    float64        `db:"freshness"`
+	Agreement    float64        `db:"agreement"`
+	Issues       sql.NullString `db:"issues"`
+	ScoredAt     time.Time      `db:"scored_at"`
+}
+
+// QualityReport aggregates the quality scores of one source or country
+type QualityReport struct {
+	Group        string  `db:"grp" json:"group"`
+	Companies    int     `db:"companies" json:"companies"`
+	Score        float64 `db:"score" json:"score"`
+	Completeness float64 `db:"completeness" json:"completeness"`
+	Validity     float64 `db:"validity" json:"validity"`
+	Freshness    float64 `db:"freshness" json:"freshness"`
+	Agreement    float64 `db:"agreement" json:"agreement"`
+	// HighQuality is the share of companies scoring 80 or more
+	HighQuality float64 `db:"high_quality" json:"high_quality"`
+}
+
+// QualityRepository reads and writes the quality_scores table
+type QualityRepository struct {
+	db DBTX
+}
+
+// NewQualityRepository creates a quality repository on a connection or transaction
+func NewQualityRepository(db DBTX) *QualityRepository {
+	return &QualityRepository{db: db}
+}
+
+// Save stores the score of a company, replacing the previous one
+func (r *QualityRepository) Save(ctx context.Context, score QualityScore) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO quality_scores (company_id, score, completeness, validity, freshness, agreement, issues, scored_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(company_id) DO UPDATE SET
+		    score = excluded.score,
+		    completeness = excluded.completeness,
+		    validity = excluded.validity,
+		    freshness = excluded.freshness,
+		    agreement = excluded.agreement,
+		    issues = excluded.issues,
+		    scored_at = excluded.scored_at`,
+		score.CompanyID, score.Score, score.Completeness, score.Validity, score.Freshness,
+		score.Agreement, score.Issues, score.ScoredAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save quality score of company %d: %w", score.CompanyID, err)
+	}
+	return nil
+}
+
+// Get returns the score of a company, wrapping sql.ErrNoRows when it has
+// not been scored
+func (r *QualityRepository) Get(ctx context.Context, companyID int64) (QualityScore, error) {
+	var score QualityScore
+	err := sqlx.GetContext(ctx, r.db, &score, "SELECT * FROM quality_scores WHERE company_id = ?", companyID)
+	if err != nil {
+		return score, fmt.Errorf("failed to get quality score of company %d: %w", companyID, err)
+	}
+	return score, nil
+}
+
+// Report aggregates the scores by data source or by the country of the
+// primary address, lowest average score first
+func (r *QualityRepository) Report(ctx context.Context, groupBy string) ([]QualityReport, error) {
+	var group, join string
+	switch groupBy {
+	case ReportBySource:
+		group = "c.data_source"
+	case ReportByCountry:
+		group = "COALESCE(a.country, '')"
+		join = "LEFT JOIN addresses a ON a.company_id = c.id AND a.is_primary = 1"
+	default:
+		return nil, fmt.Errorf("unknown quality report grouping %q", groupBy)
+	}
+
+	var reports []QualityReport
+	err := sqlx.SelectContext(ctx, r.db, &reports, fmt.Sprintf(`
+		SELECT %[1]s AS grp,
+		       COUNT(*) AS companies,
+		       AVG(q.score) AS score,
+		       AVG(q.completeness) AS completeness,
+		       AVG(q.validity) AS validity,
+		       AVG(q.freshness) AS freshness,
+		       AVG(q.agreement) AS agreement,
+		       AVG(CASE WHEN q.score >= 80 THEN 1.0 ELSE 0.0 END) AS high_quality
+		FROM quality_scores q
+		JOIN companies c ON c.id = q.company_id
+		%[2]s
+		GROUP BY %[1]s
+		ORDER BY score, grp`, group, join))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build quality report by %s: %w", groupBy, err)
+	}
+	return reports, nil
+}
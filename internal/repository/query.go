@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stkisengese/B2B-Data-Platform/internal/address"
+)
+
+// Page sizes of company queries
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// ErrInvalidQuery is returned for a query with an unknown sort or country,
+// or a negative page
+var ErrInvalidQuery = errors.New("invalid query")
+
+// companySorts maps the sort keys of a CompanyQuery to ORDER BY clauses; a
+// leading "-" sorts descending
+var companySorts = map[string]string{
+	"name":            "c.name COLLATE NOCASE, c.id",
+	"-name":           "c.name COLLATE NOCASE DESC, c.id",
+	"founded_year":    "c.founded_year IS NULL, c.founded_year, c.id",
+	"-founded_year":   "c.founded_year IS NULL, c.founded_year DESC, c.id",
+	"employee_count":  "c.employee_count IS NULL, c.employee_count, c.id",
+	"-employee_count": "c.employee_count IS NULL, c.employee_count DESC, c.id",
+	"updated_at":      "c.updated_at, c.id",
+	"-updated_at":     "c.updated_at DESC, c.id",
+	"id":              "c.id",
+	"-id":             "c.id DESC",
+}
+
+// CompanyQuery filters, sorts and pages companies. Zero fields do not
+// filter.
+type CompanyQuery struct {
+	// Search matches a substring of the name or legal name
+	Search string
+	// Country is an ISO 3166 code or country name matched against the
+	// country of any address
+	Country  string
+	Status   string
+	Industry string
+	// Category is a taxonomy code; companies in its descendants match too
+	Category     string
+	Domain       string
+	DataSource   string
+	MinEmployees int
+	MaxEmployees int
+
+	// Sort is one of the keys of companySorts, "name" by default
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// build returns the WHERE and ORDER BY clauses of the query and their
+// arguments
+func (q CompanyQuery) build() (where, order string, args []interface{}, err error) {
+	var conds []string
+
+	if search := strings.TrimSpace(q.Search); search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		conds = append(conds, `(c.name LIKE ? ESCAPE '\' OR c.legal_name LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern)
+	}
+	if q.Country != "" {
+		code, ok := address.CountryCode(q.Country)
+		if !ok {
+			return "", "", nil, fmt.Errorf("%w: unknown country %q", ErrInvalidQuery, q.Country)
+		}
+		names := address.CountryNames(code)
+		conds = append(conds, "c.id IN (SELECT company_id FROM addresses WHERE LOWER(TRIM(country)) IN (?"+strings.Repeat(", ?", len(names)-1)+"))")
+		for _, name := range names {
+			args = append(args, name)
+		}
+	}
+	if q.Status != "" {
+		conds = append(conds, "c.status = ?")
+		args = append(args, q.Status)
+	}
+	if q.Industry != "" {
+		conds = append(conds, "c.industry = ? COLLATE NOCASE")
+		args = append(args, q.Industry)
+	}
+	if q.Category != "" {
+		conds = append(conds, `c.id IN (
+			WITH RECURSIVE tree(id) AS (
+			    SELECT id FROM categories WHERE code = ?
+			    UNION
+			    SELECT cat.id FROM categories cat JOIN tree t ON cat.parent_id = t.id
+			)
+			SELECT cc.company_id FROM company_categories cc JOIN tree t ON t.id = cc.category_id)`)
+		args = append(args, q.Category)
+	}
+	if q.Domain != "" {
+		conds = append(conds, "c.domain = ?")
+		args = append(args, strings.ToLower(q.Domain))
+	}
+	if q.DataSource != "" {
+		conds = append(conds, "c.data_source = ?")
+		args = append(args, q.DataSource)
+	}
+	if q.MinEmployees > 0 {
+		conds = append(conds, "c.employee_count >= ?")
+		args = append(args, q.MinEmployees)
+	}
+	if q.MaxEmployees > 0 {
+		conds = append(conds, "c.employee_count <= ?")
+		args = append(args, q.MaxEmployees)
+	}
+
+	sort := q.Sort
+	if sort == "" {
+		sort = "name"
+	}
+	order, ok := companySorts[sort]
+	if !ok {
+		return "", "", nil, fmt.Errorf("%w: unknown sort %q", ErrInvalidQuery, q.Sort)
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return "", "", nil, fmt.Errorf("%w: limit and offset must not be negative", ErrInvalidQuery)
+	}
+
+	if len(conds) > 0 {
+		where = "WHERE " + strings.Join(conds, " AND ")
+	}
+	return where, order, args, nil
+}
+
+// escapeLike escapes the LIKE wildcards of s with backslashes
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Find returns a page of the companies matching q and the total number of
+// matches
+func (r *CompanyRepository) Find(ctx context.Context, q CompanyQuery) ([]Company, int, error) {
+	where, order, args, err := q.build()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := sqlx.GetContext(ctx, r.db, &total, "SELECT COUNT(*) FROM companies c "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count companies: %w", err)
+	}
+
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	var companies []Company
+	err = sqlx.SelectContext(ctx, r.db, &companies,
+		fmt.Sprintf("SELECT c.* FROM companies c %s ORDER BY %s LIMIT ? OFFSET ?", where, order),
+		append(args, limit, q.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find companies: %w", err)
+	}
+	return companies, total, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RawRecord represents a row in the raw_records table: one version of a
+// record as a source returned it. Identical payloads of a record are stored
+// once; LastSeenAt is the last time the payload was collected.
+type RawRecord struct {
+	ID           int64          `db:"id"`
+	Source       string         `db:"source"`
+	RecordID     string         `db:"record_id"`
+	Payload      string         `db:"payload"`
+	PayloadHash  string         `db:"payload_hash"`
+	CollectedAt  time.Time      `db:"collected_at"`
+	LastSeenAt   time.Time      `db:"last_seen_at"`
+	ProcessedAt  sql.NullTime   `db:"processed_at"`
+	ProcessError sql.NullString `db:"process_error"`
+}
+
+// RawRecordRepository reads and writes the raw_records table
+type RawRecordRepository struct {
+	db DBTX
+}
+
+// NewRawRecordRepository creates a raw record repository on a connection or transaction
+func NewRawRecordRepository(db DBTX) *RawRecordRepository {
+	return &RawRecordRepository{db: db}
+}
+
+// Insert stores a record version and reports whether it was new. A payload
+// already stored for the record only moves its LastSeenAt forward, and is
+// queued for processing again when a newer version had replaced it.
+func (r *RawRecordRepository) Insert(ctx context.Context, record *RawRecord) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO raw_records (source, record_id, payload, payload_hash, collected_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(source, record_id, payload_hash) DO NOTHING`,
+		record.Source, record.RecordID, record.Payload, record.PayloadHash,
+		record.CollectedAt.UTC(), record.CollectedAt.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert raw record %s: %w", record.RecordID, err)
+	}
+	if inserted, _ := result.RowsAffected(); inserted == 1 {
+		record.ID, err = result.LastInsertId()
+		return true, err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE raw_records SET
+		    processed_at = CASE WHEN EXISTS (
+		        SELECT 1 FROM raw_records newer
+		        WHERE newer.source = raw_records.source AND newer.record_id = raw_records.record_id
+		          AND newer.id != raw_records.id AND newer.last_seen_at > raw_records.last_seen_at
+		    ) THEN NULL ELSE processed_at END,
+		    last_seen_at = MAX(last_seen_at, ?)
+		WHERE source = ? AND record_id = ? AND payload_hash = ?`,
+		record.CollectedAt.UTC(), record.Source, record.RecordID, record.PayloadHash,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update raw record %s: %w", record.RecordID, err)
+	}
+	return false, nil
+}
+
+// ListPending returns up to limit unprocessed records in the order they were
+// last seen
+func (r *RawRecordRepository) ListPending(ctx context.Context, limit int) ([]RawRecord, error) {
+	var records []RawRecord
+	err := sqlx.SelectContext(ctx, r.db, &records, `
+		SELECT * FROM raw_records
+		WHERE processed_at IS NULL
+		ORDER BY last_seen_at, id
+		LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending raw records: %w", err)
+	}
+	return records, nil
+}
+
+// ListLatest returns up to limit records after afterID, in ID order, that
+// are the latest version of their record, optionally of one source only
+func (r *RawRecordRepository) ListLatest(ctx context.Context, source string, afterID int64, limit int) ([]RawRecord, error) {
+	var records []RawRecord
+	err := sqlx.SelectContext(ctx, r.db, &records, `
+		SELECT * FROM raw_records r
+		WHERE r.id > ? AND (? = '' OR r.source = ?)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM raw_records newer
+		      WHERE newer.source = r.source AND newer.record_id = r.record_id
+		        AND (newer.last_seen_at > r.last_seen_at
+		             OR (newer.last_seen_at = r.last_seen_at AND newer.id > r.id))
+		  )
+		ORDER BY r.id
+		LIMIT ?`,
+		afterID, source, source, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list raw records: %w", err)
+	}
+	return records, nil
+}
+
+// MarkProcessed records that a record was processed, with the reason it
+// could not be used when processErr is not empty
+func (r *RawRecordRepository) MarkProcessed(ctx context.Context, id int64, processErr string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE raw_records SET processed_at = ?, process_error = ?
+		WHERE id = ?`,
+		at.UTC(), NullString(processErr), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark raw record %d processed: %w", id, err)
+	}
+	return nil
+}
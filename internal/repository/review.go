@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Match review statuses
+const (
+	ReviewPending  = "pending"
+	ReviewAccepted = "accepted"
+	ReviewRejected = "rejected"
+)
+
+// MatchReview represents a row in the match_reviews table: a pair of
+// companies that may be the same entity and waits for a decision
+type MatchReview struct {
+	ID           int64        `db:"id"`
+	CompanyID    int64        `db:"company_id"`
+	CandidateID  int64        `db:"candidate_id"`
+	Score        float64      `db:"score"`
+	NameScore    float64      `db:"name_score"`
+	AddressScore float64      `db:"address_score"`
+	Status       string       `db:"status"`
+	CreatedAt    time.Time    `db:"created_at"`
+	ReviewedAt   sql.NullTime `db:"reviewed_at"`
+}
+
+// MatchReviewRepository reads and writes the match_reviews table
+type MatchReviewRepository struct {
+	db DBTX
+}
+
+// NewMatchReviewRepository creates a match review repository on a connection or transaction
+func NewMatchReviewRepository(db DBTX) *MatchReviewRepository {
+	return &MatchReviewRepository{db: db}
+}
+
+// Add queues a pair for review unless it was queued before, and reports
+// whether it was added. The lower company ID is stored first.
+func (r *MatchReviewRepository) Add(ctx context.Context, review *MatchReview) (bool, error) {
+	if review.CompanyID > review.CandidateID {
+		review.CompanyID, review.CandidateID = review.CandidateID, review.CompanyID
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO match_reviews (company_id, candidate_id, score, name_score, address_score)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(company_id, candidate_id) DO NOTHING`,
+		review.CompanyID, review.CandidateID, review.Score, review.NameScore, review.AddressScore,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to queue review of companies %d and %d: %w", review.CompanyID, review.CandidateID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to queue review of companies %d and %d: %w", review.CompanyID, review.CandidateID, err)
+	}
+	return affected > 0, nil
+}
+
+// List returns up to limit reviews with the given status, highest score first
+func (r *MatchReviewRepository) List(ctx context.Context, status string, limit int) ([]MatchReview, error) {
+	var reviews []MatchReview
+	err := sqlx.SelectContext(ctx, r.db, &reviews, `
+		SELECT * FROM match_reviews
+		WHERE status = ?
+		ORDER BY score DESC, id
+		LIMIT ?`,
+		status, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s match reviews: %w", status, err)
+	}
+	return reviews, nil
+}
+
+// Decided returns every accepted or rejected review
+func (r *MatchReviewRepository) Decided(ctx context.Context) ([]MatchReview, error) {
+	var reviews []MatchReview
+	err := sqlx.SelectContext(ctx, r.db, &reviews,
+		"SELECT * FROM match_reviews WHERE status <> ? ORDER BY id", ReviewPending,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decided match reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// SetStatus records the decision on a review and reports whether it exists
+func (r *MatchReviewRepository) SetStatus(ctx context.Context, id int64, status string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE match_reviews SET status = ?, reviewed_at = CURRENT_TIMESTAMP WHERE id = ?",
+		status, id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to set status of match review %d: %w", id, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to set status of match review %d: %w", id, err)
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// VATValidation represents a row in the vat_validations table
+type VATValidation struct {
+	VATID       string         `db:"vat_id"`
+	CountryCode string         `db:"country_code"`
+	VATNumber   string         `db:"vat_number"`
+	Valid       bool           `db:"valid"`
+	Name        sql.NullString `db:"name"`
+	Address     sql.NullString `db:"address"`
+	CheckedAt   time.Time      `db:"checked_at"`
+	ExpiresAt   time.Time      `db:"expires_at"`
+}
+
+// VATValidationRepository reads and writes the vat_validations table
+type VATValidationRepository struct {
+	db DBTX
+}
+
+// NewVATValidationRepository creates a VAT validation repository on a connection or transaction
+func NewVATValidationRepository(db DBTX) *VATValidationRepository {
+	return &VATValidationRepository{db: db}
+}
+
+// GetFresh returns the cached validation of a VAT ID if it has not expired
+// at now, reporting false when there is none
+func (r *VATValidationRepository) GetFresh(ctx context.Context, vatID string, now time.Time) (VATValidation, bool, error) {
+	var validation VATValidation
+	err := r.db.QueryRowxContext(ctx, `
+		SELECT * FROM vat_validations
+		WHERE vat_id = ? AND expires_at > ?`,
+		vatID, now.UTC(),
+	).StructScan(&validation)
+	if errors.Is(err, sql.ErrNoRows) {
+		return validation, false, nil
+	}
+	if err != nil {
+		return validation, false, fmt.Errorf("failed to load VAT validation %s: %w", vatID, err)
+	}
+
+	return validation, true, nil
+}
+
+// Save stores a validation, replacing any earlier one of the same VAT ID
+func (r *VATValidationRepository) Save(ctx context.Context, validation VATValidation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO vat_validations (
+		    vat_id, country_code, vat_number, valid, name, address, checked_at, expires_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(vat_id) DO UPDATE SET
+		    valid = excluded.valid,
+		    name = excluded.name,
+		    address = excluded.address,
+		    checked_at = excluded.checked_at,
+		    expires_at = excluded.expires_at`,
+		validation.VATID, validation.CountryCode, validation.VATNumber, validation.Valid,
+		validation.Name, validation.Address, validation.CheckedAt.UTC(), validation.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save VAT validation %s: %w", validation.VATID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package resolution
+
+import (
+	"context"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// goldenField copies one column from a member company into the golden record
+type goldenField struct {
+	name  string
+	valid func(c repository.Company) bool
+	set   func(cluster *repository.Cluster, c repository.Company)
+}
+
+// goldenFields are the columns of the golden record
+var goldenFields = []goldenField{
+	{"name", func(c repository.Company) bool { return c.Name != "" },
+		func(g *repository.Cluster, c repository.Company) { g.Name = c.Name }},
+	{"legal_name", func(c repository.Company) bool { return c.LegalName.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.LegalName = c.LegalName }},
+	{"description", func(c repository.Company) bool { return c.Description.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.Description = c.Description }},
+	{"website", func(c repository.Company) bool { return c.Website.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.Website = c.Website }},
+	{"phone", func(c repository.Company) bool { return c.Phone.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.Phone = c.Phone }},
+	{"industry", func(c repository.Company) bool { return c.Industry.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.Industry = c.Industry }},
+	{"employee_count", func(c repository.Company) bool { return c.EmployeeCount.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.EmployeeCount = c.EmployeeCount }},
+	{"founded_year", func(c repository.Company) bool { return c.FoundedYear.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.FoundedYear = c.FoundedYear }},
+	{"status", func(c repository.Company) bool { return c.Status.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.Status = c.Status }},
+	{"legal_form", func(c repository.Company) bool { return c.LegalForm.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.LegalForm = c.LegalForm }},
+	{"legal_form_code", func(c repository.Company) bool { return c.LegalFormCode.Valid },
+		func(g *repository.Cluster, c repository.Company) { g.LegalFormCode = c.LegalFormCode }},
+}
+
+// RefreshGolden rebuilds the golden record of a cluster from its members,
+// choosing each field by its survivorship rule, and records which member
+// supplied it
+func RefreshGolden(ctx context.Context, db repository.DBTX, clusterID int64, rules Rules) error {
+	clusters := repository.NewClusterRepository(db)
+	provenance := repository.NewProvenanceRepository(db)
+
+	members, err := clusters.Members(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(members))
+	for i, m := range members {
+		ids[i] = m.ID
+	}
+	fields, err := provenance.ListByCompanies(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	cluster, sources := golden(clusterID, members, fields, rules)
+	if err := clusters.SaveGolden(ctx, cluster); err != nil {
+		return err
+	}
+	return provenance.ReplaceCluster(ctx, clusterID, sources)
+}
+
+// golden merges the members of a cluster into one record
+func golden(clusterID int64, members []repository.Company, fields []repository.Provenance, rules Rules) (repository.Cluster, []repository.ClusterProvenance) {
+	origins := make(map[int64]map[string]repository.Provenance)
+	for _, p := range fields {
+		if origins[p.CompanyID] == nil {
+			origins[p.CompanyID] = make(map[string]repository.Provenance)
+		}
+		origins[p.CompanyID][p.Field] = p
+	}
+
+	// Columns without recorded provenance, such as those of seed rows, are
+	// attributed to the company's own source as of its last update
+	origin := func(c repository.Company, field string) repository.Provenance {
+		if p, ok := origins[c.ID][field]; ok {
+			return p
+		}
+		return repository.Provenance{
+			CompanyID:      c.ID,
+			Field:          field,
+			DataSource:     c.DataSource,
+			SourceRecordID: c.ExternalID,
+			CollectedAt:    c.UpdatedAt,
+		}
+	}
+
+	completeness := make(map[int64]int, len(members))
+	for _, m := range members {
+		completeness[m.ID] = len(m.SuppliedFields())
+	}
+
+	cluster := repository.Cluster{ID: clusterID, MemberCount: len(members)}
+	var sources []repository.ClusterProvenance
+
+	for _, field := range goldenFields {
+		rule := rules.For(field.name)
+
+		var best repository.Company
+		var bestOrigin repository.Provenance
+		found := false
+		for _, m := range members {
+			if !field.valid(m) {
+				continue
+			}
+			o := origin(m, field.name)
+			if !found || better(rule, m, o, best, bestOrigin, completeness) {
+				best, bestOrigin, found = m, o, true
+			}
+		}
+		if !found {
+			continue
+		}
+
+		field.set(&cluster, best)
+		sources = append(sources, repository.ClusterProvenance{
+			ClusterID:      clusterID,
+			Field:          field.name,
+			CompanyID:      best.ID,
+			DataSource:     bestOrigin.DataSource,
+			SourceRecordID: bestOrigin.SourceRecordID,
+			CollectedAt:    bestOrigin.CollectedAt,
+			Rule:           rule.Strategy,
+		})
+	}
+
+	return cluster, sources
+}
+
+// better reports whether member a's value beats member b's under rule.
+// Each strategy falls back to source priority and then recency, and
+// finally to the lower company ID so the result is deterministic.
+func better(rule Rule, a repository.Company, pa repository.Provenance, b repository.Company, pb repository.Provenance, completeness map[int64]int) bool {
+	switch rule.Strategy {
+	case StrategyMostRecent:
+		if !pa.CollectedAt.Equal(pb.CollectedAt) {
+			return pa.CollectedAt.After(pb.CollectedAt)
+		}
+	case StrategyMostComplete:
+		if completeness[a.ID] != completeness[b.ID] {
+			return completeness[a.ID] > completeness[b.ID]
+		}
+	}
+
+	if ra, rb := rule.rank(pa.DataSource), rule.rank(pb.DataSource); ra != rb {
+		return ra < rb
+	}
+	if !pa.CollectedAt.Equal(pb.CollectedAt) {
+		return pa.CollectedAt.After(pb.CollectedAt)
+	}
+	return a.ID < b.ID
+}
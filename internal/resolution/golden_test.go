@@ -0,0 +1,92 @@
+package resolution
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestGolden(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	members := []repository.Company{
+		{
+			ID: 1, Name: "EXAMPLE LIMITED", DataSource: "opencorporates",
+			Website:       repository.NullString("https://example.org"),
+			EmployeeCount: repository.NullInt64(50),
+			Description:   repository.NullString("Example"),
+			Industry:      repository.NullString("Software"),
+			Phone:         repository.NullString("+44 20 7946 0000"),
+		},
+		{
+			ID: 2, Name: "Example Ltd", DataSource: "companies_house",
+			EmployeeCount: repository.NullInt64(40),
+			Description:   repository.NullString("Registered example"),
+		},
+	}
+	provenance := []repository.Provenance{
+		{CompanyID: 1, Field: "employee_count", DataSource: "opencorporates", CollectedAt: older},
+		{CompanyID: 2, Field: "employee_count", DataSource: "companies_house", CollectedAt: older},
+		// Filled in later by enrichment
+		{CompanyID: 2, Field: "description", DataSource: "wikidata", CollectedAt: newer},
+	}
+
+	rules := DefaultRules()
+	rules.Fields["employee_count"] = Rule{Strategy: StrategyMostRecent}
+	provenance[0].CollectedAt = newer
+
+	cluster, sources := golden(7, members, provenance, rules)
+
+	if cluster.Name != "Example Ltd" {
+		t.Errorf("Expected name from the higher priority source, got '%s'", cluster.Name)
+	}
+	if cluster.Website.String != "https://example.org" {
+		t.Errorf("Expected website from the only member that has one, got '%s'", cluster.Website.String)
+	}
+	if cluster.EmployeeCount.Int64 != 50 {
+		t.Errorf("Expected most recent employee count 50, got %d", cluster.EmployeeCount.Int64)
+	}
+	if cluster.Description.String != "Example" {
+		t.Errorf("Expected description from the most complete member, got '%s'", cluster.Description.String)
+	}
+	if cluster.MemberCount != 2 {
+		t.Errorf("Expected 2 members, got %d", cluster.MemberCount)
+	}
+
+	bySource := make(map[string]repository.ClusterProvenance)
+	for _, p := range sources {
+		bySource[p.Field] = p
+	}
+	if p := bySource["employee_count"]; p.CompanyID != 1 || p.Rule != StrategyMostRecent || !p.CollectedAt.Equal(newer) {
+		t.Errorf("Unexpected employee count provenance %+v", p)
+	}
+	if p := bySource["name"]; p.DataSource != "companies_house" || p.Rule != StrategySourcePriority {
+		t.Errorf("Unexpected name provenance %+v", p)
+	}
+
+	// A configured priority overrides the default order
+	rules.Default = Rule{Strategy: StrategySourcePriority, Sources: []string{"opencorporates"}}
+	if cluster, _ := golden(7, members, provenance, rules); cluster.Name != "EXAMPLE LIMITED" {
+		t.Errorf("Expected name from the configured source, got '%s'", cluster.Name)
+	}
+}
+
+func TestRules_Validate(t *testing.T) {
+	rules := DefaultRules()
+	if err := rules.Validate(); err != nil {
+		t.Errorf("Expected default rules to be valid, got %v", err)
+	}
+
+	rules.Fields["website"] = Rule{Strategy: "longest"}
+	if err := rules.Validate(); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+
+	rules = DefaultRules()
+	rules.Fields["homepage"] = Rule{Strategy: StrategyMostRecent}
+	if err := rules.Validate(); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
@@ -0,0 +1,369 @@
+// Package resolution links company records from different sources that
+// describe the same entity into clusters with a golden record.
+package resolution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	addr "github.com/stkisengese/B2B-Data-Platform/internal/address"
+	"github.com/stkisengese/B2B-Data-Platform/internal/names"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+// Default thresholds of the combined name and address score
+const (
+	DefaultMatchThreshold  = 0.92
+	DefaultReviewThreshold = 0.80
+)
+
+// Weights of the name and address scores in the combined score
+const (
+	nameWeight    = 0.7
+	addressWeight = 0.3
+)
+
+// maxBlockSize bounds the number of companies compared pairwise under one
+// blocking key; larger blocks are skipped as too common to be useful
+const maxBlockSize = 500
+
+// StrongSchemes are the identifier schemes that link companies without
+// comparing names
+var StrongSchemes = []string{
+	repository.SchemeRegistration,
+	repository.SchemeLEI,
+	repository.SchemeVAT,
+}
+
+// Stats counts the outcome of a resolution run
+type Stats struct {
+	Companies       int
+	Clusters        int
+	IdentifierLinks int
+	FuzzyMatches    int
+	Queued          int
+}
+
+// Resolver clusters stored companies. Companies sharing a strong identifier
+// are always linked. Others are compared by name and primary address: pairs
+// scoring at least MatchThreshold are linked, and pairs scoring at least
+// ReviewThreshold are queued in match_reviews for a decision. Accepted
+// reviews link their pair on the next run; rejected ones keep it apart
+// unless an identifier links it. Golden records are built with Rules.
+type Resolver struct {
+	db              *sqlx.DB
+	MatchThreshold  float64
+	ReviewThreshold float64
+	Rules           Rules
+	logger          *logrus.Logger
+}
+
+// NewResolver creates a resolver with the default thresholds and
+// survivorship rules
+func NewResolver(db *sqlx.DB) *Resolver {
+	return &Resolver{
+		db:              db,
+		MatchThreshold:  DefaultMatchThreshold,
+		ReviewThreshold: DefaultReviewThreshold,
+		Rules:           DefaultRules(),
+		logger:          logrus.New(),
+	}
+}
+
+// address is the part of a primary address compared between companies
+type address struct {
+	line1, city, postalCode, country string
+}
+
+// candidate is a company prepared for comparison
+type candidate struct {
+	id        int64
+	name      string
+	matchKey  string
+	clusterID int64
+	address   address
+}
+
+// Score is the similarity of two companies
+type Score struct {
+	Name    float64
+	Address float64
+	Total   float64
+}
+
+// Run resolves every stored company into a cluster and refreshes the golden
+// records of clusters whose membership changed
+func (r *Resolver) Run(ctx context.Context) (Stats, error) {
+	var stats Stats
+
+	if err := r.Rules.Validate(); err != nil {
+		return stats, err
+	}
+
+	rows, err := repository.NewClusterRepository(r.db).ListCandidates(ctx)
+	if err != nil {
+		return stats, err
+	}
+	stats.Companies = len(rows)
+
+	candidates := make(map[int64]*candidate, len(rows))
+	sets := newUnionFind()
+	for _, row := range rows {
+		c := newCandidate(row)
+		candidates[c.id] = c
+		sets.add(c.id)
+	}
+
+	links, err := repository.NewClusterRepository(r.db).IdentifierLinks(ctx, StrongSchemes)
+	if err != nil {
+		return stats, err
+	}
+	for _, link := range links {
+		if sets.union(link[0], link[1]) {
+			stats.IdentifierLinks++
+		}
+	}
+
+	decided, err := repository.NewMatchReviewRepository(r.db).Decided(ctx)
+	if err != nil {
+		return stats, err
+	}
+	rejected := make(map[[2]int64]bool)
+	for _, review := range decided {
+		switch review.Status {
+		case repository.ReviewAccepted:
+			sets.union(review.CompanyID, review.CandidateID)
+		case repository.ReviewRejected:
+			rejected[[2]int64{review.CompanyID, review.CandidateID}] = true
+		}
+	}
+
+	var reviews []repository.MatchReview
+	for key, block := range blocks(candidates) {
+		if len(block) > maxBlockSize {
+			r.logger.WithFields(logrus.Fields{
+				"key":       key,
+				"companies": len(block),
+			}).Debug("Skipping oversized block")
+			continue
+		}
+
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				a, b := block[i], block[j]
+				if rejected[[2]int64{a.id, b.id}] || sets.find(a.id) == sets.find(b.id) {
+					continue
+				}
+
+				score, ok := compare(a, b)
+				if !ok {
+					continue
+				}
+
+				switch {
+				case score.Total >= r.MatchThreshold:
+					sets.union(a.id, b.id)
+					stats.FuzzyMatches++
+				case score.Total >= r.ReviewThreshold:
+					reviews = append(reviews, repository.MatchReview{
+						CompanyID:    a.id,
+						CandidateID:  b.id,
+						Score:        score.Total,
+						NameScore:    score.Name,
+						AddressScore: score.Address,
+					})
+				}
+			}
+		}
+	}
+
+	err = repository.Transact(ctx, r.db, func(tx *sqlx.Tx) error {
+		queue := repository.NewMatchReviewRepository(tx)
+		for i := range reviews {
+			// The pair may have been linked through other companies since
+			if sets.find(reviews[i].CompanyID) == sets.find(reviews[i].CandidateID) {
+				continue
+			}
+			added, err := queue.Add(ctx, &reviews[i])
+			if err != nil {
+				return err
+			}
+			if added {
+				stats.Queued++
+			}
+		}
+
+		clusters, err := r.assign(ctx, tx, candidates, sets)
+		stats.Clusters = clusters
+		return err
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"companies":        stats.Companies,
+		"clusters":         stats.Clusters,
+		"identifier_links": stats.IdentifierLinks,
+		"fuzzy_matches":    stats.FuzzyMatches,
+		"queued":           stats.Queued,
+	}).Info("Entity resolution finished")
+
+	return stats, nil
+}
+
+// assign stores the cluster of every company, keeping the lowest existing
+// cluster ID of a group, refreshes the golden records of changed clusters
+// and of clusters with updated members, and returns the number of clusters
+func (r *Resolver) assign(ctx context.Context, tx *sqlx.Tx, candidates map[int64]*candidate, sets *unionFind) (int, error) {
+	clusters := repository.NewClusterRepository(tx)
+
+	groups := make(map[int64][]int64)
+	for id := range candidates {
+		root := sets.find(id)
+		groups[root] = append(groups[root], id)
+	}
+
+	stale, err := clusters.Stale(ctx)
+	if err != nil {
+		return 0, err
+	}
+	refresh := make(map[int64]bool, len(stale))
+	for _, id := range stale {
+		refresh[id] = true
+	}
+
+	for _, members := range groups {
+		sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+
+		var clusterID int64
+		for _, id := range members {
+			if c := candidates[id].clusterID; c != 0 && (clusterID == 0 || c < clusterID) {
+				clusterID = c
+			}
+		}
+
+		changed := clusterID == 0
+		if changed {
+			var err error
+			if clusterID, err = clusters.Create(ctx, candidates[members[0]].name); err != nil {
+				return 0, err
+			}
+		}
+
+		var moved []int64
+		for _, id := range members {
+			if candidates[id].clusterID != clusterID {
+				moved = append(moved, id)
+			}
+		}
+		if len(moved) > 0 {
+			if err := clusters.Assign(ctx, clusterID, moved); err != nil {
+				return 0, err
+			}
+			changed = true
+		}
+
+		if changed || refresh[clusterID] {
+			if err := RefreshGolden(ctx, tx, clusterID, r.Rules); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	// Clusters whose members all moved elsewhere
+	if _, err := clusters.DeleteEmpty(ctx); err != nil {
+		return 0, err
+	}
+
+	return len(groups), nil
+}
+
+// Review records the decision on a queued match. Accepted matches are
+// linked on the next run.
+func (r *Resolver) Review(ctx context.Context, reviewID int64, accept bool) error {
+	status := repository.ReviewRejected
+	if accept {
+		status = repository.ReviewAccepted
+	}
+
+	found, err := repository.NewMatchReviewRepository(r.db).SetStatus(ctx, reviewID, status)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("match review %d not found", reviewID)
+	}
+	return nil
+}
+
+func newCandidate(row repository.ResolutionCandidate) *candidate {
+	country := countryCode(row.Country.String)
+	matchKey := row.MatchKey.String
+	if matchKey == "" {
+		name := row.Name
+		if row.LegalName.Valid {
+			name = row.LegalName.String
+		}
+		matchKey = names.Standardize(name, country).MatchKey
+	}
+
+	return &candidate{
+		id:        row.ID,
+		name:      row.Name,
+		matchKey:  matchKey,
+		clusterID: row.ClusterID.Int64,
+		address: address{
+			line1:      row.AddressLine1.String,
+			city:       row.City.String,
+			postalCode: row.PostalCode.String,
+			country:    country,
+		},
+	}
+}
+
+// countryCode returns the ISO code of a country, so that "United Kingdom"
+// from Companies House and "GB" from other sources compare equal. Unknown
+// countries are compared by their upper-cased value.
+func countryCode(country string) string {
+	if code, ok := addr.CountryCode(country); ok {
+		return code
+	}
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// blocks groups candidates by the first word of their match key, so only
+// companies with a chance of matching are compared
+func blocks(candidates map[int64]*candidate) map[string][]*candidate {
+	result := make(map[string][]*candidate)
+	for _, c := range candidates {
+		if key, _, _ := strings.Cut(c.matchKey, " "); key != "" {
+			result[key] = append(result[key], c)
+		}
+	}
+	for _, block := range result {
+		sort.Slice(block, func(i, j int) bool { return block[i].id < block[j].id })
+	}
+	return result
+}
+
+// compare scores two companies. Companies in different countries are not
+// compared. When addresses cannot be compared the address score counts as
+// neutral, so that a name alone can reach the review queue but is never
+// enough for an automatic match with the default thresholds.
+func compare(a, b *candidate) (Score, bool) {
+	if a.address.country != "" && b.address.country != "" && a.address.country != b.address.country {
+		return Score{}, false
+	}
+
+	score := Score{Name: JaroWinkler(a.matchKey, b.matchKey), Address: 0.5}
+	if similarity, ok := addressSimilarity(a.address, b.address); ok {
+		score.Address = similarity
+	}
+	score.Total = nameWeight*score.Name + addressWeight*score.Address
+	return score, true
+}
@@ -0,0 +1,149 @@
+package resolution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func company(externalID, name, source, registration, postcode string) repository.CompanyRecord {
+	record := repository.CompanyRecord{
+		Company: repository.Company{
+			ExternalID: repository.NullString(externalID),
+			Name:       name,
+			DataSource: source,
+		},
+	}
+	if registration != "" {
+		record.Identifiers = []repository.Identifier{{Scheme: repository.SchemeRegistration, Value: registration, DataSource: source}}
+	}
+	if postcode != "" {
+		record.Addresses = []repository.Address{{
+			AddressLine1: repository.NullString("1 High Street"),
+			City:         repository.NullString("London"),
+			PostalCode:   repository.NullString(postcode),
+			Country:      repository.NullString("GB"),
+			IsPrimary:    true,
+		}}
+	}
+	return record
+}
+
+func TestResolver_Run(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	records := []repository.CompanyRecord{
+		// Linked by registration number
+		company("ch_12345678", "TEST COMPANY LIMITED", "companies_house", "GB:12345678", "EC1A 1BB"),
+		company("oc_gb_12345678", "Test Company Ltd", "opencorporates", "GB:12345678", ""),
+		// Linked by name and address
+		company("ch_11111111", "EXAMPLE TRADING LIMITED", "companies_house", "GB:11111111", "SW1A 2AA"),
+		company("manual_example", "Example Trading Ltd.", "manual", "", "SW1A 2AA"),
+		// Similar names without addresses go to review
+		company("manual_north", "Northwind Traders", "manual", "", ""),
+		company("manual_northwind", "Northwind Trader", "manual", "", ""),
+	}
+	if err := repository.NewCompanyRepository(db).SaveBatch(ctx, records); err != nil {
+		t.Fatalf("Failed to save companies: %v", err)
+	}
+
+	resolver := NewResolver(db)
+	stats, err := resolver.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// 3 seed companies plus 6 saved ones in 7 clusters
+	if stats.Companies != 9 || stats.Clusters != 7 {
+		t.Errorf("Expected 9 companies in 7 clusters, got %+v", stats)
+	}
+	if stats.IdentifierLinks != 1 || stats.FuzzyMatches != 1 || stats.Queued != 1 {
+		t.Errorf("Expected 1 identifier link, 1 fuzzy match and 1 queued review, got %+v", stats)
+	}
+
+	clusterOf := func(externalID string) int64 {
+		var id int64
+		db.Get(&id, "SELECT cluster_id FROM companies WHERE external_id = ?", externalID)
+		return id
+	}
+	if clusterOf("ch_12345678") == 0 || clusterOf("ch_12345678") != clusterOf("oc_gb_12345678") {
+		t.Errorf("Expected registration matches in one cluster")
+	}
+	if clusterOf("ch_11111111") != clusterOf("manual_example") {
+		t.Errorf("Expected name and address matches in one cluster")
+	}
+
+	golden, err := repository.NewClusterRepository(db).Get(ctx, clusterOf("manual_example"))
+	if err != nil {
+		t.Fatalf("Expected golden record, got %v", err)
+	}
+	if golden.Name != "EXAMPLE TRADING LIMITED" || golden.MemberCount != 2 {
+		t.Errorf("Expected golden record from Companies House with 2 members, got %+v", golden)
+	}
+
+	reviews, err := repository.NewMatchReviewRepository(db).List(ctx, repository.ReviewPending, 10)
+	if err != nil || len(reviews) != 1 {
+		t.Fatalf("Expected 1 pending review, got %d (%v)", len(reviews), err)
+	}
+
+	// Accepting the review links the pair on the next run, which is
+	// otherwise idempotent
+	if err := resolver.Review(ctx, reviews[0].ID, true); err != nil {
+		t.Fatalf("Expected no error accepting review, got %v", err)
+	}
+	stats, err = resolver.Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.Clusters != 6 || stats.Queued != 0 {
+		t.Errorf("Expected 6 clusters and no new reviews, got %+v", stats)
+	}
+	if clusterOf("manual_north") != clusterOf("manual_northwind") {
+		t.Errorf("Expected accepted match in one cluster")
+	}
+
+	var clusters int
+	db.Get(&clusters, "SELECT COUNT(*) FROM company_clusters")
+	if clusters != 6 {
+		t.Errorf("Expected empty clusters to be removed, got %d clusters", clusters)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	if s := JaroWinkler("martha", "marhta"); s < 0.96 || s > 0.97 {
+		t.Errorf("Expected similarity of about 0.961, got %.3f", s)
+	}
+	if s := JaroWinkler("abc", "xyz"); s != 0 {
+		t.Errorf("Expected similarity 0, got %.3f", s)
+	}
+}
+
+func TestResolver_Run_CountryNames(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	// Companies House writes countries as names, other sources as ISO codes
+	house := company("ch_22222222", "ACME WIDGETS LIMITED", "companies_house", "", "M1 1AE")
+	house.Addresses[0].Country = repository.NullString("United Kingdom")
+	gleif := company("gleif_acme", "Acme Widgets Ltd", "gleif", "", "M1 1AE")
+	if err := repository.NewCompanyRepository(db).SaveBatch(ctx, []repository.CompanyRecord{house, gleif}); err != nil {
+		t.Fatalf("Failed to save companies: %v", err)
+	}
+
+	stats, err := NewResolver(db).Run(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.FuzzyMatches != 1 {
+		t.Errorf("Expected 1 fuzzy match, got %+v", stats)
+	}
+
+	var clusters []int64
+	db.Select(&clusters, "SELECT cluster_id FROM companies WHERE external_id IN ('ch_22222222', 'gleif_acme')")
+	if len(clusters) != 2 || clusters[0] == 0 || clusters[0] != clusters[1] {
+		t.Errorf("Expected both companies in one cluster, got %v", clusters)
+	}
+}
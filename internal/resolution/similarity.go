@@ -0,0 +1,133 @@
+package resolution
+
+import (
+	"strings"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/names"
+)
+
+// JaroWinkler returns the Jaro-Winkler similarity of two strings, from 0
+// for nothing in common to 1 for equal strings
+func JaroWinkler(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	s1, s2 := []rune(a), []rune(b)
+	if len(s1) == 0 || len(s2) == 0 {
+		return 0
+	}
+
+	window := max(len(s1), len(s2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matched1 := make([]bool, len(s1))
+	matched2 := make([]bool, len(s2))
+	matches := 0
+	for i := range s1 {
+		for j := max(0, i-window); j < min(len(s2), i+window+1); j++ {
+			if !matched2[j] && s1[i] == s2[j] {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range s1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if s1[i] != s2[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(s1)) + m/float64(len(s2)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(s1), len(s2)) && s1[prefix] == s2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// tokenSimilarity returns the share of distinct words two texts have in
+// common (Jaccard index)
+func tokenSimilarity(a, b string) float64 {
+	ta, tb := strings.Fields(names.MatchKey(a)), strings.Fields(names.MatchKey(b))
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(ta))
+	for _, t := range ta {
+		set[t] = true
+	}
+
+	common, union := 0, len(set)
+	seen := make(map[string]bool, len(tb))
+	for _, t := range tb {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if set[t] {
+			common++
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
+
+// addressSimilarity compares two addresses by postcode, city and street,
+// and reports false when either side has too little to compare
+func addressSimilarity(a, b address) (float64, bool) {
+	postcodeA, postcodeB := normalizePostcode(a.postalCode), normalizePostcode(b.postalCode)
+	cityA, cityB := names.MatchKey(a.city), names.MatchKey(b.city)
+
+	hasPostcode := postcodeA != "" && postcodeB != ""
+	hasCity := cityA != "" && cityB != ""
+	hasStreet := a.line1 != "" && b.line1 != ""
+	if !hasPostcode && !hasCity {
+		return 0, false
+	}
+
+	street := 0.0
+	if hasStreet {
+		street = tokenSimilarity(a.line1, b.line1)
+	}
+
+	if hasPostcode {
+		score := 0.2 * street
+		if postcodeA == postcodeB {
+			score += 0.6
+		}
+		if hasCity && cityA == cityB {
+			score += 0.2
+		}
+		return score, true
+	}
+
+	score := 0.5 * street
+	if cityA == cityB {
+		score += 0.5
+	}
+	return score, true
+}
+
+func normalizePostcode(postcode string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(postcode), ""))
+}
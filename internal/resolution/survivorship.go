@@ -0,0 +1,103 @@
+package resolution
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Survivorship strategies
+const (
+	// StrategySourcePriority takes the value of the source listed first in
+	// the rule's Sources; unlisted sources follow in DefaultSourcePriority
+	// order, and ties go to the most recent value
+	StrategySourcePriority = "source_priority"
+	// StrategyMostRecent takes the most recently collected value
+	StrategyMostRecent = "most_recent"
+	// StrategyMostComplete takes the value of the member company with the
+	// most columns filled, so related values tend to come from one record
+	StrategyMostComplete = "most_complete"
+)
+
+// DefaultSourcePriority orders sources by how much their values are
+// trusted; official registers come before aggregators
+var DefaultSourcePriority = []string{
+	"companies_house",
+	"gleif",
+	"sec_edgar",
+	"vies",
+	"opencorporates",
+	"wikidata",
+	"manual",
+}
+
+// Rule selects which member's value survives into the golden record
+type Rule struct {
+	Strategy string
+	Sources  []string
+}
+
+// Rules holds the default rule and per-field overrides, keyed by column
+// name such as "employee_count"
+type Rules struct {
+	Default Rule
+	Fields  map[string]Rule
+}
+
+// DefaultRules trusts registers first, but takes employee counts, statuses
+// and descriptions from the most recent or most complete record
+func DefaultRules() Rules {
+	return Rules{
+		Default: Rule{Strategy: StrategySourcePriority},
+		Fields: map[string]Rule{
+			"employee_count": {Strategy: StrategyMostRecent},
+			"status":         {Strategy: StrategyMostRecent},
+			"description":    {Strategy: StrategyMostComplete},
+		},
+	}
+}
+
+// For returns the rule of a field
+func (r Rules) For(field string) Rule {
+	if rule, ok := r.Fields[field]; ok && rule.Strategy != "" {
+		return rule
+	}
+	if r.Default.Strategy == "" {
+		return Rule{Strategy: StrategySourcePriority, Sources: r.Default.Sources}
+	}
+	return r.Default
+}
+
+// Validate checks that every rule names a known strategy and field
+func (r Rules) Validate() error {
+	if err := r.Default.validate(); err != nil {
+		return fmt.Errorf("default survivorship rule: %w", err)
+	}
+	for field, rule := range r.Fields {
+		if !slices.ContainsFunc(goldenFields, func(f goldenField) bool { return f.name == field }) {
+			return fmt.Errorf("survivorship rule for unknown field %q", field)
+		}
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("survivorship rule for %s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	switch r.Strategy {
+	case "", StrategySourcePriority, StrategyMostRecent, StrategyMostComplete:
+		return nil
+	}
+	return fmt.Errorf("unknown strategy %q", r.Strategy)
+}
+
+// rank returns the position of a source under the rule's priority
+func (r Rule) rank(source string) int {
+	if i := slices.Index(r.Sources, source); i >= 0 {
+		return i
+	}
+	if i := slices.Index(DefaultSourcePriority, source); i >= 0 {
+		return len(r.Sources) + i
+	}
+	return len(r.Sources) + len(DefaultSourcePriority)
+}
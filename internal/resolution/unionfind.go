@@ -0,0 +1,40 @@
+package resolution
+
+// unionFind tracks which companies have been linked into the same group
+type unionFind struct {
+	parent map[int64]int64
+}
+
+func newUnionFind() *unionFind {
+	return &unionFind{parent: make(map[int64]int64)}
+}
+
+func (u *unionFind) add(id int64) {
+	if _, ok := u.parent[id]; !ok {
+		u.parent[id] = id
+	}
+}
+
+// find returns the representative of id's group
+func (u *unionFind) find(id int64) int64 {
+	u.add(id)
+	for u.parent[id] != id {
+		u.parent[id] = u.parent[u.parent[id]]
+		id = u.parent[id]
+	}
+	return id
+}
+
+// union merges the groups of a and b and reports whether they were apart
+func (u *unionFind) union(a, b int64) bool {
+	ra, rb := u.find(a), u.find(b)
+	if ra == rb {
+		return false
+	}
+	if ra < rb {
+		u.parent[rb] = ra
+	} else {
+		u.parent[ra] = rb
+	}
+	return true
+}
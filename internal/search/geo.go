@@ -0,0 +1,256 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// EarthRadiusKm is the mean radius of the Earth used for distances
+const EarthRadiusKm = 6371.0088
+
+// MaxRadiusKm is the largest radius of a geo search
+const MaxRadiusKm = 1000
+
+// Geo search sort orders
+const (
+	SortDistance = "distance"
+	SortName     = "name"
+)
+
+// ErrInvalidGeoQuery is returned for coordinates out of range, a missing or
+// too large radius, or an unknown sort
+var ErrInvalidGeoQuery = errors.New("invalid geo query")
+
+// BoundingBox is an area between two latitudes and two longitudes. MinLon
+// is greater than MaxLon for a box crossing the antimeridian.
+type BoundingBox struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+// GeoQuery finds companies with an address within RadiusKm of a point, or
+// within Box when it is set
+type GeoQuery struct {
+	// Latitude and Longitude are the point distances are measured from; for
+	// box searches the centre of the box is used
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	Box       *BoundingBox
+	// Sort is SortDistance, the default, or SortName
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// GeoResult is a company with the address nearest to the point of a
+// GeoQuery
+type GeoResult struct {
+	CompanyID  int64   `db:"company_id" json:"company_id"`
+	ExternalID string  `db:"external_id" json:"external_id"`
+	Name       string  `db:"name" json:"name"`
+	AddressID  int64   `db:"address_id" json:"address_id"`
+	City       string  `db:"city" json:"city"`
+	Country    string  `db:"country" json:"country"`
+	Latitude   float64 `db:"latitude" json:"latitude"`
+	Longitude  float64 `db:"longitude" json:"longitude"`
+	DistanceKm float64 `db:"-" json:"distance_km"`
+}
+
+// GeoService searches companies by the coordinates of their addresses with
+// the R*Tree index in address_locations
+type GeoService struct {
+	db *sqlx.DB
+}
+
+// NewGeoService creates a geo search service
+func NewGeoService(db *sqlx.DB) *GeoService {
+	return &GeoService{db: db}
+}
+
+// Search returns a page of the companies matching q and the total number
+// of matches. Candidates are read from the R*Tree by bounding box and then
+// kept when their haversine distance is within the radius.
+func (s *GeoService) Search(ctx context.Context, q GeoQuery) ([]GeoResult, int, error) {
+	if err := q.validate(); err != nil {
+		return nil, 0, err
+	}
+
+	var boxes []BoundingBox
+	if q.Box != nil {
+		q.Latitude, q.Longitude = q.Box.center()
+		boxes = q.Box.split()
+	} else {
+		boxes = radiusBox(q.Latitude, q.Longitude, q.RadiusKm).split()
+	}
+
+	// Each box is a separate lookup, as the R*Tree only uses constraints
+	// joined by AND
+	var lookups []string
+	var args []interface{}
+	for _, box := range boxes {
+		lookups = append(lookups, "SELECT id FROM address_locations WHERE min_lat <= ? AND max_lat >= ? AND min_lon <= ? AND max_lon >= ?")
+		args = append(args, box.MaxLat, box.MinLat, box.MaxLon, box.MinLon)
+	}
+
+	var candidates []GeoResult
+	err := sqlx.SelectContext(ctx, s.db, &candidates, `
+		SELECT c.id AS company_id,
+		       COALESCE(c.external_id, '') AS external_id,
+		       c.name,
+		       a.id AS address_id,
+		       COALESCE(a.city, '') AS city,
+		       COALESCE(a.country, '') AS country,
+		       a.latitude,
+		       a.longitude
+		FROM addresses a
+		JOIN companies c ON c.id = a.company_id
+		WHERE a.id IN (`+strings.Join(lookups, " UNION ALL ")+`)`, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search companies near %.5f,%.5f: %w", q.Latitude, q.Longitude, err)
+	}
+
+	// Keep the nearest matching address of each company
+	nearest := make(map[int64]GeoResult)
+	for _, candidate := range candidates {
+		if q.Box != nil && !q.Box.contains(candidate.Latitude, candidate.Longitude) {
+			continue
+		}
+		candidate.DistanceKm = Distance(q.Latitude, q.Longitude, candidate.Latitude, candidate.Longitude)
+		if q.Box == nil && candidate.DistanceKm > q.RadiusKm {
+			continue
+		}
+		if best, ok := nearest[candidate.CompanyID]; !ok || candidate.DistanceKm < best.DistanceKm {
+			nearest[candidate.CompanyID] = candidate
+		}
+	}
+
+	results := make([]GeoResult, 0, len(nearest))
+	for _, result := range nearest {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if q.Sort == SortName && !strings.EqualFold(a.Name, b.Name) {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+		if a.DistanceKm != b.DistanceKm {
+			return a.DistanceKm < b.DistanceKm
+		}
+		return a.CompanyID < b.CompanyID
+	})
+
+	total := len(results)
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+	start := min(max(q.Offset, 0), total)
+	return results[start:min(start+limit, total)], total, nil
+}
+
+// validate checks the coordinates, radius and sort of the query
+func (q GeoQuery) validate() error {
+	if q.Sort != "" && q.Sort != SortDistance && q.Sort != SortName {
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidGeoQuery, q.Sort)
+	}
+	if q.Box != nil {
+		b := q.Box
+		if !validLatitude(b.MinLat) || !validLatitude(b.MaxLat) || b.MinLat > b.MaxLat ||
+			!validLongitude(b.MinLon) || !validLongitude(b.MaxLon) {
+			return fmt.Errorf("%w: bounding box out of range", ErrInvalidGeoQuery)
+		}
+		return nil
+	}
+	if !validLatitude(q.Latitude) || !validLongitude(q.Longitude) {
+		return fmt.Errorf("%w: coordinates out of range", ErrInvalidGeoQuery)
+	}
+	if q.RadiusKm <= 0 || q.RadiusKm > MaxRadiusKm {
+		return fmt.Errorf("%w: radius must be between 0 and %d km", ErrInvalidGeoQuery, MaxRadiusKm)
+	}
+	return nil
+}
+
+func validLatitude(lat float64) bool  { return lat >= -90 && lat <= 90 }
+func validLongitude(lon float64) bool { return lon >= -180 && lon <= 180 }
+
+// Distance returns the great-circle distance in km between two points with
+// the haversine formula
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const rad = math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// radiusBox returns the bounding box of the circle of radiusKm around a
+// point. Near the poles the box covers all longitudes.
+func radiusBox(lat, lon, radiusKm float64) BoundingBox {
+	dLat := radiusKm / EarthRadiusKm * 180 / math.Pi
+	box := BoundingBox{MinLat: math.Max(lat-dLat, -90), MaxLat: math.Min(lat+dLat, 90), MinLon: -180, MaxLon: 180}
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		return box
+	}
+
+	// The widest point of the circle is nearer the pole than its centre
+	maxLat := math.Max(math.Abs(box.MinLat), math.Abs(box.MaxLat))
+	dLon := dLat / math.Cos(maxLat*math.Pi/180)
+	if dLon >= 180 {
+		return box
+	}
+	box.MinLon, box.MaxLon = wrapLongitude(lon-dLon), wrapLongitude(lon+dLon)
+	return box
+}
+
+// wrapLongitude brings a longitude back into [-180, 180]
+func wrapLongitude(lon float64) float64 {
+	switch {
+	case lon < -180:
+		return lon + 360
+	case lon > 180:
+		return lon - 360
+	}
+	return lon
+}
+
+// split returns the box as one or, when it crosses the antimeridian, two
+// boxes that do not
+func (b BoundingBox) split() []BoundingBox {
+	if b.MinLon <= b.MaxLon {
+		return []BoundingBox{b}
+	}
+	east, west := b, b
+	east.MaxLon = 180
+	west.MinLon = -180
+	return []BoundingBox{east, west}
+}
+
+// contains reports whether a point lies in the box. The R*Tree stores
+// 32-bit coordinates, so its matches are checked again.
+func (b BoundingBox) contains(lat, lon float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon <= b.MaxLon {
+		return lon >= b.MinLon && lon <= b.MaxLon
+	}
+	return lon >= b.MinLon || lon <= b.MaxLon
+}
+
+// center returns the centre of the box
+func (b BoundingBox) center() (lat, lon float64) {
+	lat = (b.MinLat + b.MaxLat) / 2
+	if b.MinLon <= b.MaxLon {
+		return lat, (b.MinLon + b.MaxLon) / 2
+	}
+	return lat, wrapLongitude((b.MinLon + b.MaxLon + 360) / 2)
+}
@@ -0,0 +1,135 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestDistance(t *testing.T) {
+	// London to Paris
+	if d := Distance(51.5074, -0.1278, 48.8566, 2.3522); math.Abs(d-343.5) > 1 {
+		t.Errorf("Expected about 343.5 km, got %.1f", d)
+	}
+	// Across the antimeridian
+	if d := Distance(0, 179.9, 0, -179.9); math.Abs(d-22.24) > 0.1 {
+		t.Errorf("Expected about 22.2 km, got %.2f", d)
+	}
+}
+
+func TestRadiusBox(t *testing.T) {
+	box := radiusBox(51.5074, -0.1278, 10)
+	if box.MinLat > 51.42 || box.MaxLat < 51.59 || box.MinLon > -0.27 || box.MaxLon < 0.01 {
+		t.Errorf("Expected the box to cover 10 km around London, got %+v", box)
+	}
+
+	box = radiusBox(0, 179.95, 20)
+	if boxes := box.split(); len(boxes) != 2 || boxes[0].MaxLon != 180 || boxes[1].MinLon != -180 {
+		t.Errorf("Expected the box to be split at the antimeridian, got %+v", boxes)
+	}
+
+	box = radiusBox(89.95, 0, 10)
+	if box.MaxLat != 90 || box.MinLon != -180 || box.MaxLon != 180 {
+		t.Errorf("Expected a polar box over all longitudes, got %+v", box)
+	}
+}
+
+func TestGeoService_Search(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+
+	point := func(lat, lon float64) []repository.Address {
+		return []repository.Address{{
+			Latitude:  sql.NullFloat64{Float64: lat, Valid: true},
+			Longitude: sql.NullFloat64{Float64: lon, Valid: true},
+			IsPrimary: true,
+		}}
+	}
+	records := []repository.CompanyRecord{
+		{Company: repository.Company{ExternalID: repository.NullString("test_city"), Name: "City Ltd", DataSource: "test"}, Addresses: point(51.5155, -0.0922)},
+		{Company: repository.Company{ExternalID: repository.NullString("test_corner"), Name: "Corner Ltd", DataSource: "test"}, Addresses: point(51.5800, -0.0100)},
+		{Company: repository.Company{ExternalID: repository.NullString("test_croydon"), Name: "Croydon Ltd", DataSource: "test"}, Addresses: point(51.3762, -0.0982)},
+		{Company: repository.Company{ExternalID: repository.NullString("test_oxford"), Name: "Oxford Ltd", DataSource: "test"}, Addresses: point(51.7520, -1.2577)},
+		{Company: repository.Company{ExternalID: repository.NullString("test_fiji"), Name: "Fiji Ltd", DataSource: "test"}, Addresses: point(-16.5, 179.99)},
+	}
+	if _, err := repository.SaveAll(ctx, db, records, 0); err != nil {
+		t.Fatalf("Failed to save companies: %v", err)
+	}
+
+	service := NewGeoService(db)
+	names := func(results []GeoResult) []string {
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+
+	// Companies within 20 km of Charing Cross, nearest first
+	results, total, err := service.Search(ctx, GeoQuery{Latitude: 51.5074, Longitude: -0.1278, RadiusKm: 20})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if total != 3 || len(results) != 3 || results[0].Name != "City Ltd" || results[1].Name != "Corner Ltd" || results[2].Name != "Croydon Ltd" {
+		t.Fatalf("Expected City, Corner and Croydon by distance, got %v", names(results))
+	}
+	if math.Abs(results[0].DistanceKm-2.7) > 0.2 {
+		t.Errorf("Expected City about 2.7 km away, got %.2f", results[0].DistanceKm)
+	}
+
+	// Corner is inside the bounding box of a 10 km radius but 11.5 km away
+	if results, _, _ := service.Search(ctx, GeoQuery{Latitude: 51.5074, Longitude: -0.1278, RadiusKm: 10}); len(results) != 1 {
+		t.Errorf("Expected only City within 10 km, got %v", names(results))
+	}
+
+	results, total, _ = service.Search(ctx, GeoQuery{Latitude: 51.5074, Longitude: -0.1278, RadiusKm: 100, Sort: SortName, Limit: 2, Offset: 1})
+	if total != 4 || len(results) != 2 || results[0].Name != "Corner Ltd" || results[1].Name != "Croydon Ltd" {
+		t.Errorf("Expected the second page by name, got %v of %d", names(results), total)
+	}
+
+	results, _, _ = service.Search(ctx, GeoQuery{Box: &BoundingBox{MinLat: 51, MaxLat: 52, MinLon: -1.5, MaxLon: -1}})
+	if len(results) != 1 || results[0].Name != "Oxford Ltd" {
+		t.Errorf("Expected Oxford in the box, got %v", names(results))
+	}
+
+	results, _, _ = service.Search(ctx, GeoQuery{Latitude: -16.5, Longitude: -179.99, RadiusKm: 10})
+	if len(results) != 1 || results[0].Name != "Fiji Ltd" {
+		t.Errorf("Expected Fiji across the antimeridian, got %v", names(results))
+	}
+
+	// Triggers keep the index in sync with geocoding and deleted addresses
+	id, _ := repository.NewCompanyRepository(db).IDByExternalID(ctx, "test_oxford")
+	addresses, _ := repository.NewCompanyRepository(db).ListAddresses(ctx, id)
+	err = repository.NewLocationRepository(db).SetLocation(ctx, addresses[0].ID,
+		sql.NullFloat64{Float64: 51.5200, Valid: true}, sql.NullFloat64{Float64: -0.1000, Valid: true}, "postcode", "test",
+	)
+	if err != nil {
+		t.Fatalf("Failed to set location: %v", err)
+	}
+	if results, _, _ := service.Search(ctx, GeoQuery{Latitude: 51.5074, Longitude: -0.1278, RadiusKm: 10}); len(results) != 2 {
+		t.Errorf("Expected the moved address within 10 km, got %v", names(results))
+	}
+	if err := repository.NewCompanyRepository(db).ReplaceAddresses(ctx, id, nil); err != nil {
+		t.Fatalf("Failed to replace addresses: %v", err)
+	}
+	if results, _, _ := service.Search(ctx, GeoQuery{Latitude: 51.5074, Longitude: -0.1278, RadiusKm: 10}); len(results) != 1 {
+		t.Errorf("Expected the deleted address to be gone, got %v", names(results))
+	}
+
+	for _, query := range []GeoQuery{
+		{Latitude: 91, RadiusKm: 10},
+		{Latitude: 51.5, Longitude: -0.1},
+		{Latitude: 51.5, Longitude: -0.1, RadiusKm: 5000},
+		{Latitude: 51.5, Longitude: -0.1, RadiusKm: 10, Sort: "size"},
+		{Box: &BoundingBox{MinLat: 52, MaxLat: 51}},
+	} {
+		if _, _, err := service.Search(ctx, query); !errors.Is(err, ErrInvalidGeoQuery) {
+			t.Errorf("Expected ErrInvalidGeoQuery for %+v, got %v", query, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package search runs ranked full-text queries over companies with the
+// SQLite FTS5 index in company_search, and radius and bounding-box queries
+// with the R*Tree index over address coordinates in address_locations. Both
+// indexes are kept in sync with the companies and addresses tables by
+// triggers.
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stkisengese/B2B-Data-Platform/internal/database"
+)
+
+// Page sizes of searches
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var (
+	// ErrUnavailable is returned when SQLite was built without FTS5 or the
+	// search migrations have not been applied
+	ErrUnavailable = errors.New("full-text search is not available")
+	// ErrEmptyQuery is returned for a query without any searchable term
+	ErrEmptyQuery = errors.New("search query has no terms")
+)
+
+// columnWeights are the BM25 weights of name, legal_name, description and
+// address, so that a match in the name outranks one in the description
+const columnWeights = "10.0, 5.0, 1.0, 2.0"
+
+// Query is a full-text search
+type Query struct {
+	// Text holds words, which all have to match, and "quoted phrases". A
+	// word ending in * matches as a prefix.
+	Text string
+	// Prefix makes the last word match as a prefix, for search-as-you-type
+	Prefix bool
+	Limit  int
+	Offset int
+}
+
+// Result is a company matching a search
+type Result struct {
+	CompanyID  int64  `db:"company_id" json:"company_id"`
+	ExternalID string `db:"external_id" json:"external_id"`
+	Name       string `db:"name" json:"name"`
+	DataSource string `db:"data_source" json:"data_source"`
+	// Highlight is the name with the matched terms marked, and Snippet the
+	// best matching fragment of any column
+	Highlight string `db:"highlight" json:"highlight"`
+	Snippet   string `db:"snippet" json:"snippet"`
+	// Score is the negated BM25 rank, higher for better matches
+	Score float64 `db:"score" json:"score"`
+}
+
+// Service searches the company_search index
+type Service struct {
+	db *sqlx.DB
+	// StartMark and EndMark surround matched terms in highlights and
+	// snippets
+	StartMark string
+	EndMark   string
+}
+
+// NewService creates a search service marking matches with <b> and </b>
+func NewService(db *sqlx.DB) *Service {
+	return &Service{db: db, StartMark: "<b>", EndMark: "</b>"}
+}
+
+// Available reports whether the company_search index can be queried
+func (s *Service) Available(ctx context.Context) bool {
+	if !database.HasFTS5(s.db) {
+		return false
+	}
+	var count int
+	err := sqlx.GetContext(ctx, s.db, &count,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'company_search'",
+	)
+	return err == nil && count > 0
+}
+
+// Search returns the companies matching q, best match first
+func (s *Service) Search(ctx context.Context, q Query) ([]Result, error) {
+	match, err := MatchExpression(q.Text, q.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Available(ctx) {
+		return nil, ErrUnavailable
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	limit = min(limit, MaxLimit)
+
+	var results []Result
+	err = sqlx.SelectContext(ctx, s.db, &results, `
+		SELECT c.id AS company_id,
+		       COALESCE(c.external_id, '') AS external_id,
+		       c.name,
+		       c.data_source,
+		       highlight(company_search, 0, ?, ?) AS highlight,
+		       snippet(company_search, -1, ?, ?, '…', 12) AS snippet,
+		       -bm25(company_search, `+columnWeights+`) AS score
+		FROM company_search
+		JOIN companies c ON c.id = company_search.rowid
+		WHERE company_search MATCH ?
+		ORDER BY bm25(company_search, `+columnWeights+`), c.id
+		LIMIT ? OFFSET ?`,
+		s.StartMark, s.EndMark, s.StartMark, s.EndMark, match, limit, max(q.Offset, 0),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search companies for %q: %w", q.Text, err)
+	}
+	return results, nil
+}
+
+// MatchExpression converts search text into an FTS5 query. Every word and
+// phrase is quoted, so that FTS5 operators and column filters in the text
+// are searched for as words rather than interpreted.
+func MatchExpression(text string, prefix bool) (string, error) {
+	var terms []string
+	var last string // the last bare word, which prefix applies to
+
+	for len(text) > 0 {
+		text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		if text == "" {
+			break
+		}
+
+		if text[0] == '"' {
+			phrase, rest, _ := strings.Cut(text[1:], `"`)
+			text = rest
+			if term := quote(phrase); term != "" {
+				terms = append(terms, term)
+				last = ""
+			}
+			continue
+		}
+
+		end := strings.IndexFunc(text, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+		if end < 0 {
+			end = len(text)
+		}
+		word := text[:end]
+		text = text[end:]
+
+		wildcard := strings.HasSuffix(word, "*")
+		term := quote(strings.TrimRight(word, "*"))
+		if term == "" {
+			continue
+		}
+		if wildcard {
+			term += "*"
+			last = ""
+		} else {
+			last = term
+		}
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return "", ErrEmptyQuery
+	}
+	if prefix && last != "" && terms[len(terms)-1] == last {
+		terms[len(terms)-1] += "*"
+	}
+	return strings.Join(terms, " "), nil
+}
+
+// quote returns text as an FTS5 string, or "" when it has no letters or
+// digits to match
+func quote(text string) string {
+	if strings.IndexFunc(text, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+		return ""
+	}
+	return `"` + strings.ReplaceAll(strings.TrimSpace(text), `"`, `""`) + `"`
+}
@@ -0,0 +1,130 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stkisengese/B2B-Data-Platform/internal/database/dbtest"
+	"github.com/stkisengese/B2B-Data-Platform/internal/repository"
+)
+
+func TestMatchExpression(t *testing.T) {
+	tests := []struct {
+		text   string
+		prefix bool
+		want   string
+	}{
+		{"acme widgets", false, `"acme" "widgets"`},
+		{"acme wid", true, `"acme" "wid"*`},
+		{"wid* ltd", false, `"wid"* "ltd"`},
+		{`"acme widgets" london`, false, `"acme widgets" "london"`},
+		{`london "acme widgets"`, true, `"london" "acme widgets"`},
+		{`name:acme OR NEAR(x)`, false, `"name:acme" "OR" "NEAR(x)"`},
+		{`"unterminated phrase`, false, `"unterminated phrase"`},
+		{`- * "" acme`, false, `"acme"`},
+	}
+
+	for _, tt := range tests {
+		got, err := MatchExpression(tt.text, tt.prefix)
+		if err != nil {
+			t.Errorf("Expected no error for %q, got %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Expected %s for %q, got %s", tt.want, tt.text, got)
+		}
+	}
+
+	if _, err := MatchExpression(` * "" `, false); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("Expected ErrEmptyQuery, got %v", err)
+	}
+}
+
+func TestService_Search(t *testing.T) {
+	db := dbtest.New(t)
+	ctx := context.Background()
+	service := NewService(db)
+
+	if !service.Available(ctx) {
+		if _, err := service.Search(ctx, Query{Text: "apple"}); !errors.Is(err, ErrUnavailable) {
+			t.Errorf("Expected ErrUnavailable without FTS5, got %v", err)
+		}
+		t.Skip("SQLite was built without FTS5, run with -tags sqlite_fts5")
+	}
+
+	records := []repository.CompanyRecord{
+		{
+			Company: repository.Company{
+				ExternalID: repository.NullString("test_1"), Name: "Acme Widgets Ltd", DataSource: "test",
+				Description: repository.NullString("Makers of fine widgets since 1920"),
+			},
+			Addresses: []repository.Address{{City: repository.NullString("Leeds"), Country: repository.NullString("GB"), IsPrimary: true}},
+		},
+		{
+			Company: repository.Company{
+				ExternalID: repository.NullString("test_2"), Name: "Widgetry Supplies", DataSource: "test",
+				Description: repository.NullString("Wholesale of Acme and other widgets"),
+			},
+		},
+	}
+	if _, err := repository.SaveAll(ctx, db, records, 0); err != nil {
+		t.Fatalf("Failed to save companies: %v", err)
+	}
+
+	results, err := service.Search(ctx, Query{Text: "acme widgets"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 2 || results[0].Name != "Acme Widgets Ltd" {
+		t.Fatalf("Expected the name match to rank first, got %+v", results)
+	}
+	if results[0].Highlight != "<b>Acme</b> <b>Widgets</b> Ltd" || results[0].Score <= results[1].Score {
+		t.Errorf("Expected a highlighted, higher scored first result, got %+v", results[0])
+	}
+
+	tests := []struct {
+		query Query
+		want  []string
+	}{
+		{Query{Text: "widg", Prefix: true}, []string{"Widgetry Supplies", "Acme Widgets Ltd"}},
+		{Query{Text: "widg"}, nil},
+		{Query{Text: `"other widgets"`}, []string{"Widgetry Supplies"}},
+		{Query{Text: `"widgets other"`}, nil},
+		{Query{Text: "leeds"}, []string{"Acme Widgets Ltd"}},
+		{Query{Text: "mountain view"}, []string{"Google"}},
+	}
+	for _, tt := range tests {
+		results, err := service.Search(ctx, tt.query)
+		if err != nil {
+			t.Fatalf("Expected no error for %+v, got %v", tt.query, err)
+		}
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Name)
+		}
+		if len(names) != len(tt.want) || (len(names) > 0 && names[0] != tt.want[0]) {
+			t.Errorf("Expected %v for %+v, got %v", tt.want, tt.query, names)
+		}
+	}
+
+	// Triggers keep the index in sync with renames, addresses and deletes
+	id, _ := repository.NewCompanyRepository(db).IDByExternalID(ctx, "test_1")
+	if _, err := db.Exec("UPDATE companies SET name = 'Zenith Gadgets Ltd' WHERE id = ?", id); err != nil {
+		t.Fatalf("Failed to rename company: %v", err)
+	}
+	if err := repository.NewCompanyRepository(db).ReplaceAddresses(ctx, id, []repository.Address{{City: repository.NullString("York")}}); err != nil {
+		t.Fatalf("Failed to replace addresses: %v", err)
+	}
+	for text, want := range map[string]int{"zenith": 1, "york": 1, "leeds": 0} {
+		if results, _ := service.Search(ctx, Query{Text: text}); len(results) != want {
+			t.Errorf("Expected %d results for %q, got %+v", want, text, results)
+		}
+	}
+	if _, err := repository.NewCompanyRepository(db).Delete(ctx, id); err != nil {
+		t.Fatalf("Failed to delete company: %v", err)
+	}
+	if results, _ := service.Search(ctx, Query{Text: "zenith"}); len(results) != 0 {
+		t.Errorf("Expected no results after delete, got %+v", results)
+	}
+}
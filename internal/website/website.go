@@ -0,0 +1,127 @@
+// Package website canonicalizes company website URLs and extracts their
+// registrable domain using the public suffix list.
+package website
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// ErrInvalid is returned for values that are not website URLs
+var ErrInvalid = errors.New("invalid website")
+
+// trackingParams are query parameters that identify campaigns or clicks
+// rather than pages
+var trackingParams = map[string]bool{
+	"gclid":   true,
+	"dclid":   true,
+	"fbclid":  true,
+	"msclkid": true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_ga":     true,
+	"_gl":     true,
+	"ref":     true,
+	"ref_src": true,
+}
+
+// Website is a canonicalized website
+type Website struct {
+	// URL is the canonical URL, e.g. "https://www.example.com/en"
+	URL string
+	// Host is the lowercased ASCII host, e.g. "www.example.com"
+	Host string
+	// Domain is the registrable domain, e.g. "example.com" or
+	// "example.co.uk"
+	Domain string
+}
+
+// Parse canonicalizes a website written as a URL or a bare host. The scheme
+// defaults to https, hosts are lowercased and converted to punycode, default
+// ports, fragments, tracking parameters and trailing slashes are dropped,
+// and the remaining query parameters are sorted.
+func Parse(raw string) (Website, error) {
+	text := strings.TrimSpace(raw)
+	if text == "" || strings.ContainsAny(text, " \t\n") {
+		return Website{}, fmt.Errorf("%w: %q", ErrInvalid, raw)
+	}
+	if !strings.Contains(text, "://") {
+		text = "https://" + strings.TrimPrefix(text, "//")
+	}
+
+	u, err := url.Parse(text)
+	if err != nil {
+		return Website{}, fmt.Errorf("%w: %q: %v", ErrInvalid, raw, err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return Website{}, fmt.Errorf("%w: %q is not an http URL", ErrInvalid, raw)
+	}
+
+	host, err := idna.Lookup.ToASCII(strings.TrimSuffix(u.Hostname(), "."))
+	if err != nil || !strings.Contains(host, ".") || net.ParseIP(host) != nil {
+		return Website{}, fmt.Errorf("%w: %q has no domain name", ErrInvalid, raw)
+	}
+	domain, err := Domain(host)
+	if err != nil {
+		return Website{}, fmt.Errorf("%w: %q: %v", ErrInvalid, raw, err)
+	}
+
+	port := u.Port()
+	u.Host = host
+	if port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		u.Host = net.JoinHostPort(host, port)
+	}
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+
+	query := u.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	return Website{URL: u.String(), Host: host, Domain: domain}, nil
+}
+
+// Domain returns the registrable domain of a host: its public suffix and
+// one more label. Hosts may be written in Unicode or punycode.
+func Domain(host string) (string, error) {
+	host, err := idna.Lookup.ToASCII(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), "."))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return domain, nil
+}
+
+// LookupDomain returns the registrable domain of a URL, host or email
+// address, as used to look companies up by domain
+func LookupDomain(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if _, host, ok := strings.Cut(value, "@"); ok && !strings.Contains(value, "/") {
+		return Domain(host)
+	}
+	site, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return site.Domain, nil
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS import_checkpoints;
\ No newline at end of file
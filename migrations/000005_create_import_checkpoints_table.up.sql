@@ -0,0 +1,9 @@
+CREATE TABLE import_checkpoints (
+    source TEXT NOT NULL,
+    file TEXT NOT NULL,
+    entry TEXT NOT NULL,
+    rows_done INTEGER NOT NULL DEFAULT 0,
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, file, entry)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS filings;
+DROP TABLE IF EXISTS people;
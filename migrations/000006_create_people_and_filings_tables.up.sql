@@ -0,0 +1,43 @@
+CREATE TABLE people (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    external_id TEXT UNIQUE NOT NULL,
+    company_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    name TEXT NOT NULL,
+    role TEXT,
+    nationality TEXT,
+    country_of_residence TEXT,
+    occupation TEXT,
+    birth_month INTEGER,
+    birth_year INTEGER,
+    natures_of_control TEXT,
+    started_on TEXT,
+    ended_on TEXT,
+    data_source TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id)
+);
+
+CREATE INDEX idx_people_company_id ON people(company_id);
+CREATE INDEX idx_people_name ON people(name);
+
+CREATE TABLE filings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    external_id TEXT UNIQUE NOT NULL,
+    company_id INTEGER NOT NULL,
+    transaction_id TEXT,
+    category TEXT,
+    type TEXT,
+    description TEXT,
+    filing_date TEXT,
+    action_date TEXT,
+    pages INTEGER,
+    data_source TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id)
+);
+
+CREATE INDEX idx_filings_company_id ON filings(company_id);
+CREATE INDEX idx_filings_filing_date ON filings(filing_date);
@@ -0,0 +1,5 @@
+CREATE TABLE stream_checkpoints (
+    stream TEXT PRIMARY KEY,
+    timepoint INTEGER NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
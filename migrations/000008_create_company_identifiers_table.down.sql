@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_company_identifiers_scheme_value;
+DROP TABLE IF EXISTS company_identifiers;
@@ -0,0 +1,13 @@
+CREATE TABLE company_identifiers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    company_id INTEGER NOT NULL,
+    scheme TEXT NOT NULL,
+    value TEXT NOT NULL,
+    data_source TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id),
+    UNIQUE (company_id, scheme, value)
+);
+
+CREATE INDEX idx_company_identifiers_scheme_value ON company_identifiers(scheme, value);
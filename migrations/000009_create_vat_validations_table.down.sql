@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_vat_validations_expires_at;
+DROP TABLE IF EXISTS vat_validations;
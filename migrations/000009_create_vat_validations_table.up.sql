@@ -0,0 +1,12 @@
+CREATE TABLE vat_validations (
+    vat_id TEXT PRIMARY KEY,
+    country_code TEXT NOT NULL,
+    vat_number TEXT NOT NULL,
+    valid BOOLEAN NOT NULL,
+    name TEXT,
+    address TEXT,
+    checked_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_vat_validations_expires_at ON vat_validations(expires_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_companies_status;
+UPDATE companies SET status = status_raw WHERE status_raw IS NOT NULL;
+ALTER TABLE companies DROP COLUMN status_raw;
@@ -0,0 +1,16 @@
+ALTER TABLE companies ADD COLUMN status_raw TEXT;
+
+UPDATE companies SET
+    status_raw = status,
+    status = CASE
+        WHEN status IS NULL THEN NULL
+        WHEN lower(status) IN ('active', 'live', 'registered', 'open', 'active-proposal-to-strike-off') THEN 'active'
+        WHEN lower(status) IN ('dormant', 'inactive') THEN 'dormant'
+        WHEN lower(status) IN ('liquidation', 'administration', 'receivership', 'receiver-action',
+                               'insolvency-proceedings', 'voluntary-arrangement', 'in administration') THEN 'in_insolvency'
+        WHEN lower(status) IN ('dissolved', 'closed', 'removed', 'deleted', 'converted-closed') THEN 'dissolved'
+        ELSE 'unknown'
+    END
+WHERE data_source <> 'manual';
+
+CREATE INDEX idx_companies_status ON companies(status);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_companies_match_key;
+ALTER TABLE companies DROP COLUMN match_key;
+ALTER TABLE companies DROP COLUMN legal_form_code;
+ALTER TABLE companies DROP COLUMN legal_form;
@@ -0,0 +1,5 @@
+ALTER TABLE companies ADD COLUMN legal_form TEXT;
+ALTER TABLE companies ADD COLUMN legal_form_code TEXT;
+ALTER TABLE companies ADD COLUMN match_key TEXT;
+
+CREATE INDEX idx_companies_match_key ON companies(match_key);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_match_reviews_status;
+DROP TABLE IF EXISTS match_reviews;
+DROP INDEX IF EXISTS idx_companies_cluster_id;
+ALTER TABLE companies DROP COLUMN cluster_id;
+DROP TABLE IF EXISTS company_clusters;
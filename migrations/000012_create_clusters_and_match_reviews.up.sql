@@ -0,0 +1,38 @@
+CREATE TABLE company_clusters (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    legal_name TEXT,
+    description TEXT,
+    website TEXT,
+    phone TEXT,
+    industry TEXT,
+    employee_count INTEGER,
+    founded_year INTEGER,
+    status TEXT,
+    legal_form TEXT,
+    legal_form_code TEXT,
+    member_count INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE companies ADD COLUMN cluster_id INTEGER REFERENCES company_clusters(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_companies_cluster_id ON companies(cluster_id);
+
+CREATE TABLE match_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    company_id INTEGER NOT NULL,
+    candidate_id INTEGER NOT NULL,
+    score REAL NOT NULL,
+    name_score REAL NOT NULL,
+    address_score REAL NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at DATETIME,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (candidate_id) REFERENCES companies(id) ON DELETE CASCADE,
+    UNIQUE(company_id, candidate_id)
+);
+
+CREATE INDEX idx_match_reviews_status ON match_reviews(status);
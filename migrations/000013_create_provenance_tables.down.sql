@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cluster_provenance;
+DROP TABLE IF EXISTS field_provenance;
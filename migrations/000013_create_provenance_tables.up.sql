@@ -0,0 +1,23 @@
+CREATE TABLE field_provenance (
+    company_id INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    data_source TEXT NOT NULL,
+    source_record_id TEXT,
+    collected_at DATETIME NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (company_id, field),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE TABLE cluster_provenance (
+    cluster_id INTEGER NOT NULL,
+    field TEXT NOT NULL,
+    company_id INTEGER NOT NULL,
+    data_source TEXT NOT NULL,
+    source_record_id TEXT,
+    collected_at DATETIME NOT NULL,
+    rule TEXT NOT NULL,
+    PRIMARY KEY (cluster_id, field),
+    FOREIGN KEY (cluster_id) REFERENCES company_clusters(id) ON DELETE CASCADE,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
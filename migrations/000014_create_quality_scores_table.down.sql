@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_quality_scores_score;
+DROP TABLE IF EXISTS quality_scores;
@@ -0,0 +1,13 @@
+CREATE TABLE quality_scores (
+    company_id INTEGER PRIMARY KEY,
+    score REAL NOT NULL,
+    completeness REAL NOT NULL,
+    validity REAL NOT NULL,
+    freshness REAL NOT NULL,
+    agreement REAL NOT NULL,
+    issues TEXT,
+    scored_at DATETIME NOT NULL,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_quality_scores_score ON quality_scores(score);
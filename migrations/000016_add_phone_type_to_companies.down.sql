@@ -0,0 +1,2 @@
+ALTER TABLE companies DROP COLUMN phone_valid;
+ALTER TABLE companies DROP COLUMN phone_type;
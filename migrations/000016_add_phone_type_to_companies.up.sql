@@ -0,0 +1,2 @@
+ALTER TABLE companies ADD COLUMN phone_type TEXT;
+ALTER TABLE companies ADD COLUMN phone_valid BOOLEAN;
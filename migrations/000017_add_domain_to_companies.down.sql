@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_companies_domain;
+ALTER TABLE companies DROP COLUMN domain;
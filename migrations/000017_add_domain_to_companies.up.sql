@@ -0,0 +1,3 @@
+ALTER TABLE companies ADD COLUMN domain TEXT;
+
+CREATE INDEX idx_companies_domain ON companies(domain);
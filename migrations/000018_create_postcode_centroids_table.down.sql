@@ -0,0 +1,4 @@
+ALTER TABLE addresses DROP COLUMN geocoded_at;
+ALTER TABLE addresses DROP COLUMN geocode_source;
+ALTER TABLE addresses DROP COLUMN geocode_precision;
+DROP TABLE IF EXISTS postcode_centroids;
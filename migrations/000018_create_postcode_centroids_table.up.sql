@@ -0,0 +1,17 @@
+CREATE TABLE postcode_centroids (
+    country TEXT NOT NULL,
+    postcode TEXT NOT NULL,
+    latitude REAL NOT NULL,
+    longitude REAL NOT NULL,
+    place_name TEXT,
+    region TEXT,
+    source TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (country, postcode)
+);
+
+CREATE INDEX idx_postcode_centroids_place_name ON postcode_centroids(country, place_name COLLATE NOCASE);
+
+ALTER TABLE addresses ADD COLUMN geocode_precision TEXT;
+ALTER TABLE addresses ADD COLUMN geocode_source TEXT;
+ALTER TABLE addresses ADD COLUMN geocoded_at DATETIME;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_email_verifications_expires_at;
+DROP INDEX IF EXISTS idx_email_verifications_domain;
+DROP TABLE IF EXISTS email_verifications;
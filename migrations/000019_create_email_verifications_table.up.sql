@@ -0,0 +1,20 @@
+CREATE TABLE email_verifications (
+    email TEXT PRIMARY KEY,
+    domain TEXT NOT NULL,
+    is_valid BOOLEAN NOT NULL,
+    is_deliverable BOOLEAN NOT NULL,
+    score REAL NOT NULL,
+    syntax_valid BOOLEAN NOT NULL,
+    domain_exists BOOLEAN NOT NULL,
+    mx_record_exists BOOLEAN NOT NULL,
+    smtp_connectable BOOLEAN NOT NULL,
+    mailbox TEXT NOT NULL,
+    is_catch_all BOOLEAN NOT NULL,
+    is_disposable BOOLEAN NOT NULL,
+    is_role_account BOOLEAN NOT NULL,
+    verified_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_email_verifications_domain ON email_verifications(domain);
+CREATE INDEX idx_email_verifications_expires_at ON email_verifications(expires_at);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS suppressions;
+DROP INDEX IF EXISTS idx_contacts_verification_status;
+DROP INDEX IF EXISTS idx_contacts_email;
+DROP INDEX IF EXISTS idx_contacts_company_id;
+DROP TABLE IF EXISTS contacts;
@@ -0,0 +1,33 @@
+CREATE TABLE contacts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    external_id TEXT UNIQUE NOT NULL,
+    company_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    name TEXT,
+    job_title TEXT,
+    department TEXT,
+    email TEXT,
+    phone TEXT,
+    verification_status TEXT NOT NULL DEFAULT 'unverified',
+    verification_score REAL,
+    verified_at DATETIME,
+    opted_out BOOLEAN NOT NULL DEFAULT FALSE,
+    opted_out_at DATETIME,
+    data_source TEXT NOT NULL,
+    source_record_id TEXT,
+    collected_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_contacts_company_id ON contacts(company_id);
+CREATE INDEX idx_contacts_email ON contacts(email COLLATE NOCASE);
+CREATE INDEX idx_contacts_verification_status ON contacts(verification_status);
+
+CREATE TABLE suppressions (
+    value TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
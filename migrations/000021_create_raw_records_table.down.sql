@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_raw_records_processed_at;
+DROP INDEX IF EXISTS idx_raw_records_record;
+DROP TABLE IF EXISTS raw_records;
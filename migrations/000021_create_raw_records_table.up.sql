@@ -0,0 +1,15 @@
+CREATE TABLE raw_records (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    source TEXT NOT NULL,
+    record_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    payload_hash TEXT NOT NULL,
+    collected_at DATETIME NOT NULL,
+    last_seen_at DATETIME NOT NULL,
+    processed_at DATETIME,
+    process_error TEXT,
+    UNIQUE (source, record_id, payload_hash)
+);
+
+CREATE INDEX idx_raw_records_record ON raw_records(source, record_id, collected_at);
+CREATE INDEX idx_raw_records_processed_at ON raw_records(processed_at);
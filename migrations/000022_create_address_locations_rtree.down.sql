@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS address_locations_delete;
+DROP TRIGGER IF EXISTS address_locations_update;
+DROP TRIGGER IF EXISTS address_locations_insert;
+DROP TABLE IF EXISTS address_locations;
@@ -0,0 +1,27 @@
+CREATE VIRTUAL TABLE address_locations USING rtree(
+    id,
+    min_lat, max_lat,
+    min_lon, max_lon
+);
+
+INSERT INTO address_locations (id, min_lat, max_lat, min_lon, max_lon)
+SELECT id, latitude, latitude, longitude, longitude
+FROM addresses
+WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+
+CREATE TRIGGER address_locations_insert AFTER INSERT ON addresses
+WHEN new.latitude IS NOT NULL AND new.longitude IS NOT NULL BEGIN
+    INSERT INTO address_locations (id, min_lat, max_lat, min_lon, max_lon)
+    VALUES (new.id, new.latitude, new.latitude, new.longitude, new.longitude);
+END;
+
+CREATE TRIGGER address_locations_update AFTER UPDATE OF latitude, longitude ON addresses BEGIN
+    DELETE FROM address_locations WHERE id = old.id;
+    INSERT INTO address_locations (id, min_lat, max_lat, min_lon, max_lon)
+    SELECT new.id, new.latitude, new.latitude, new.longitude, new.longitude
+    WHERE new.latitude IS NOT NULL AND new.longitude IS NOT NULL;
+END;
+
+CREATE TRIGGER address_locations_delete AFTER DELETE ON addresses BEGIN
+    DELETE FROM address_locations WHERE id = old.id;
+END;
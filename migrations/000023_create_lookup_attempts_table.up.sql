@@ -0,0 +1,9 @@
+CREATE TABLE lookup_attempts (
+    company_id INTEGER NOT NULL,
+    lookup TEXT NOT NULL,
+    attempted_at DATETIME NOT NULL,
+    PRIMARY KEY (company_id, lookup),
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_lookup_attempts_attempted_at ON lookup_attempts(lookup, attempted_at);
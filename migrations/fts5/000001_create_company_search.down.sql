@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS company_search_address_delete;
+DROP TRIGGER IF EXISTS company_search_address_update;
+DROP TRIGGER IF EXISTS company_search_address_insert;
+DROP TRIGGER IF EXISTS company_search_company_delete;
+DROP TRIGGER IF EXISTS company_search_company_update;
+DROP TRIGGER IF EXISTS company_search_company_insert;
+DROP TABLE IF EXISTS company_search;
@@ -0,0 +1,54 @@
+-- Requires SQLite built with FTS5 (go build -tags sqlite_fts5). These
+-- migrations are tracked in schema_migrations_fts5 and skipped otherwise.
+CREATE VIRTUAL TABLE company_search USING fts5(
+    name,
+    legal_name,
+    description,
+    address,
+    tokenize = 'unicode61 remove_diacritics 2',
+    prefix = '2 3'
+);
+
+INSERT INTO company_search (rowid, name, legal_name, description, address)
+SELECT c.id, c.name, c.legal_name, c.description,
+       (SELECT group_concat(concat_ws(' ', a.address_line1, a.address_line2, a.city, a.state, a.postal_code, a.country), ' ')
+        FROM addresses a WHERE a.company_id = c.id)
+FROM companies c;
+
+CREATE TRIGGER company_search_company_insert AFTER INSERT ON companies BEGIN
+    INSERT INTO company_search (rowid, name, legal_name, description, address)
+    VALUES (new.id, new.name, new.legal_name, new.description,
+            (SELECT group_concat(concat_ws(' ', a.address_line1, a.address_line2, a.city, a.state, a.postal_code, a.country), ' ')
+             FROM addresses a WHERE a.company_id = new.id));
+END;
+
+CREATE TRIGGER company_search_company_update AFTER UPDATE OF name, legal_name, description ON companies BEGIN
+    UPDATE company_search
+    SET name = new.name, legal_name = new.legal_name, description = new.description
+    WHERE rowid = new.id;
+END;
+
+CREATE TRIGGER company_search_company_delete AFTER DELETE ON companies BEGIN
+    DELETE FROM company_search WHERE rowid = old.id;
+END;
+
+CREATE TRIGGER company_search_address_insert AFTER INSERT ON addresses BEGIN
+    UPDATE company_search
+    SET address = (SELECT group_concat(concat_ws(' ', a.address_line1, a.address_line2, a.city, a.state, a.postal_code, a.country), ' ')
+                   FROM addresses a WHERE a.company_id = new.company_id)
+    WHERE rowid = new.company_id;
+END;
+
+CREATE TRIGGER company_search_address_update AFTER UPDATE OF address_line1, address_line2, city, state, postal_code, country ON addresses BEGIN
+    UPDATE company_search
+    SET address = (SELECT group_concat(concat_ws(' ', a.address_line1, a.address_line2, a.city, a.state, a.postal_code, a.country), ' ')
+                   FROM addresses a WHERE a.company_id = new.company_id)
+    WHERE rowid = new.company_id;
+END;
+
+CREATE TRIGGER company_search_address_delete AFTER DELETE ON addresses BEGIN
+    UPDATE company_search
+    SET address = (SELECT group_concat(concat_ws(' ', a.address_line1, a.address_line2, a.city, a.state, a.postal_code, a.country), ' ')
+                   FROM addresses a WHERE a.company_id = old.company_id)
+    WHERE rowid = old.company_id;
+END;